@@ -0,0 +1,5 @@
+// Package featureflagsv1 holds the gRPC API definition and the code
+// generated from it.
+package featureflagsv1
+
+//go:generate protoc -I ../../.. --go_out=../../.. --go_opt=paths=source_relative --go-grpc_out=../../.. --go-grpc_opt=paths=source_relative api/featureflags/v1/featureflags.proto
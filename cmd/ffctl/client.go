@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"feature-flags/internal/manifest"
+	"feature-flags/internal/models"
+)
+
+// Client is a thin wrapper around the feature flags HTTP API.
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// APIError is a non-2xx response from the server.
+type APIError struct {
+	StatusCode int
+	Message    string
+	// Applied lists the changes of a manifest apply that landed before it
+	// failed without a transaction.
+	Applied *manifest.Plan
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s (HTTP %d)", e.Message, e.StatusCode)
+}
+
+// PendingError is returned when the server accepted a change on a protected
+// feature as a change request instead of applying it.
+type PendingError struct {
+	ChangeRequest *models.ChangeRequest
+}
+
+func (e *PendingError) Error() string {
+	return fmt.Sprintf("change request %s is pending approval", e.ChangeRequest.ID.Hex())
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 300 {
+		// Errors are problem details; older servers sent {"error": "..."}.
+		var apiErr struct {
+			Detail  string         `json:"detail"`
+			Error   string         `json:"error"`
+			Applied *manifest.Plan `json:"applied"`
+		}
+		message := strings.TrimSpace(string(data))
+		if json.Unmarshal(data, &apiErr) == nil {
+			switch {
+			case apiErr.Detail != "":
+				message = apiErr.Detail
+			case apiErr.Error != "":
+				message = apiErr.Error
+			}
+		}
+		if message == "" {
+			message = http.StatusText(resp.StatusCode)
+		}
+		return &APIError{StatusCode: resp.StatusCode, Message: message, Applied: apiErr.Applied}
+	}
+
+	if resp.StatusCode == http.StatusAccepted {
+		var request models.ChangeRequest
+		if err := json.Unmarshal(data, &request); err != nil {
+			return err
+		}
+		return &PendingError{ChangeRequest: &request}
+	}
+
+	if out != nil && len(data) > 0 {
+		return json.Unmarshal(data, out)
+	}
+	return nil
+}
+
+func (c *Client) ListFeatures(ctx context.Context) ([]*models.Feature, error) {
+	var features []*models.Feature
+	err := c.do(ctx, http.MethodGet, "/api/features", nil, nil, &features)
+	return features, err
+}
+
+func (c *Client) GetFeature(ctx context.Context, id string) (*models.Feature, error) {
+	var feature models.Feature
+	if err := c.do(ctx, http.MethodGet, "/api/features/"+url.PathEscape(id), nil, nil, &feature); err != nil {
+		return nil, err
+	}
+	return &feature, nil
+}
+
+type createFeatureRequest struct {
+	Key       string             `json:"key,omitempty"`
+	Name      string             `json:"name"`
+	Type      models.FeatureType `json:"type"`
+	IsEnabled bool               `json:"is_enabled"`
+	Protected bool               `json:"protected"`
+}
+
+func (c *Client) CreateFeature(ctx context.Context, req createFeatureRequest) (*models.Feature, error) {
+	var feature models.Feature
+	if err := c.do(ctx, http.MethodPost, "/api/features", nil, req, &feature); err != nil {
+		return nil, err
+	}
+	return &feature, nil
+}
+
+func (c *Client) EnableFeature(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, "/api/features/"+url.PathEscape(id)+"/enable", nil, nil, nil)
+}
+
+func (c *Client) DisableFeature(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, "/api/features/"+url.PathEscape(id)+"/disable", nil, nil, nil)
+}
+
+// PreviewDisable returns the features a disable would cascade to.
+func (c *Client) PreviewDisable(ctx context.Context, id string) ([]*models.Feature, error) {
+	var preview struct {
+		Features []*models.Feature `json:"features"`
+	}
+	query := url.Values{"dry_run": {"true"}}
+	err := c.do(ctx, http.MethodPost, "/api/features/"+url.PathEscape(id)+"/disable", query, nil, &preview)
+	return preview.Features, err
+}
+
+func (c *Client) ListDependencies(ctx context.Context) ([]models.FeatureDependency, error) {
+	var dependencies []models.FeatureDependency
+	err := c.do(ctx, http.MethodGet, "/api/features/dependencies", nil, nil, &dependencies)
+	return dependencies, err
+}
+
+type featureDependencies struct {
+	Parents  []string `json:"parents"`
+	Children []string `json:"children"`
+}
+
+func (c *Client) GetDependencies(ctx context.Context, id string) (*featureDependencies, error) {
+	var deps featureDependencies
+	if err := c.do(ctx, http.MethodGet, "/api/features/"+url.PathEscape(id)+"/dependencies", nil, nil, &deps); err != nil {
+		return nil, err
+	}
+	return &deps, nil
+}
+
+func (c *Client) AddDependency(ctx context.Context, parentID, childID string) error {
+	body := map[string]string{"parent_id": parentID, "child_id": childID}
+	return c.do(ctx, http.MethodPost, "/api/features/dependencies", nil, body, nil)
+}
+
+func (c *Client) RemoveDependency(ctx context.Context, parentID, childID string) error {
+	query := url.Values{"parent_id": {parentID}, "child_id": {childID}}
+	return c.do(ctx, http.MethodDelete, "/api/features/dependencies", query, nil, nil)
+}
+
+func (c *Client) ExportManifest(ctx context.Context) (*manifest.Manifest, error) {
+	var m manifest.Manifest
+	err := c.do(ctx, http.MethodGet, "/api/manifest", url.Values{"format": {"json"}}, nil, &m)
+	return &m, err
+}
+
+// PlanManifest asks the server what applying m would change. With apply set
+// the changes are made.
+func (c *Client) PlanManifest(ctx context.Context, m *manifest.Manifest, prune, apply bool) (*manifest.Plan, error) {
+	path := "/api/manifest/plan"
+	if apply {
+		path = "/api/manifest/apply"
+	}
+	query := url.Values{}
+	if prune {
+		query.Set("prune", "true")
+	}
+	var plan manifest.Plan
+	err := c.do(ctx, http.MethodPost, path, query, m, &plan)
+	return &plan, err
+}
@@ -0,0 +1,515 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+
+	"feature-flags/internal/importer"
+	"feature-flags/internal/manifest"
+	"feature-flags/internal/models"
+)
+
+var (
+	errFeatureNotFound = errors.New("feature not found")
+	objectIDPattern    = regexp.MustCompile(`^[0-9a-f]{24}$`)
+)
+
+// resolveFeature turns a feature ID, key or name into an ID. Keys are
+// unique, so a key match wins over name matches.
+func (a *app) resolveFeature(ctx context.Context, ref string) (string, error) {
+	if objectIDPattern.MatchString(ref) {
+		return ref, nil
+	}
+
+	client, err := a.api()
+	if err != nil {
+		return "", err
+	}
+	features, err := client.ListFeatures(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var matches []*models.Feature
+	for _, f := range features {
+		if f.Key == ref {
+			return f.ID.Hex(), nil
+		}
+		if f.Name == ref {
+			matches = append(matches, f)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("%w: %s", errFeatureNotFound, ref)
+	case 1:
+		return matches[0].ID.Hex(), nil
+	default:
+		return "", usagef("feature name %q is ambiguous (%d matches), use its ID", ref, len(matches))
+	}
+}
+
+func (a *app) cmdList(ctx context.Context, args []string) error {
+	fs := a.flagSet("list")
+	if _, err := a.parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	features, err := client.ListFeatures(ctx)
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(features, func(i, j int) bool { return features[i].Name < features[j].Name })
+	return a.printFeatures(features)
+}
+
+func (a *app) cmdGet(ctx context.Context, args []string) error {
+	fs := a.flagSet("get")
+	expect := fs.String("expect", "", "fail with exit code 7 unless the feature is enabled or disabled")
+	args, err := a.parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if *expect != "" && *expect != "enabled" && *expect != "disabled" {
+		return usagef("--expect must be enabled or disabled")
+	}
+
+	id, err := a.resolveFeature(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	feature, err := client.GetFeature(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := a.printFeatures([]*models.Feature{feature}); err != nil {
+		return err
+	}
+
+	if *expect != "" && feature.IsEnabled != (*expect == "enabled") {
+		return &checkError{msg: fmt.Sprintf("feature %s is not %s", feature.Name, *expect)}
+	}
+	return nil
+}
+
+func (a *app) cmdCreate(ctx context.Context, args []string) error {
+	fs := a.flagSet("create")
+	name := fs.String("name", "", "feature name")
+	key := fs.String("key", "", "feature key (default derived from the name)")
+	featureType := fs.String("type", string(models.FeatureTypeBasic), "feature type (basic, premium, enterprise)")
+	enabled := fs.Bool("enabled", false, "create the feature enabled")
+	protected := fs.Bool("protected", false, "require approval for changes")
+	if _, err := a.parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if *name == "" {
+		return usagef("create: --name is required")
+	}
+
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	feature, err := client.CreateFeature(ctx, createFeatureRequest{
+		Key:       *key,
+		Name:      *name,
+		Type:      models.FeatureType(*featureType),
+		IsEnabled: *enabled,
+		Protected: *protected,
+	})
+	if err != nil {
+		return err
+	}
+	return a.printFeatures([]*models.Feature{feature})
+}
+
+func (a *app) cmdEnable(ctx context.Context, args []string) error {
+	fs := a.flagSet("enable")
+	args, err := a.parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	id, err := a.resolveFeature(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	if err := a.reportPending(client.EnableFeature(ctx, id)); err != nil {
+		return err
+	}
+	return a.printMessage("enabled", id)
+}
+
+func (a *app) cmdDisable(ctx context.Context, args []string) error {
+	fs := a.flagSet("disable")
+	dryRun := fs.Bool("dry-run", false, "show the features that would be disabled")
+	args, err := a.parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	id, err := a.resolveFeature(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		cascade, err := client.PreviewDisable(ctx, id)
+		if err != nil {
+			return err
+		}
+		return a.printFeatures(cascade)
+	}
+
+	if err := a.reportPending(client.DisableFeature(ctx, id)); err != nil {
+		return err
+	}
+	return a.printMessage("disabled", id)
+}
+
+func (a *app) cmdDep(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return usagef("dep: expected add, rm or ls")
+	}
+
+	subcommand := args[0]
+	switch subcommand {
+	case "add", "rm":
+		fs := a.flagSet("dep " + subcommand)
+		args, err := a.parse(fs, args[1:], 2, 2)
+		if err != nil {
+			return err
+		}
+		parentID, err := a.resolveFeature(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		childID, err := a.resolveFeature(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		client, err := a.api()
+		if err != nil {
+			return err
+		}
+
+		if subcommand == "add" {
+			if err := a.reportPending(client.AddDependency(ctx, parentID, childID)); err != nil {
+				return err
+			}
+			return a.printMessage("dependency added", parentID+" -> "+childID)
+		}
+		if err := client.RemoveDependency(ctx, parentID, childID); err != nil {
+			return err
+		}
+		return a.printMessage("dependency removed", parentID+" -> "+childID)
+
+	case "ls":
+		fs := a.flagSet("dep ls")
+		args, err := a.parse(fs, args[1:], 0, 1)
+		if err != nil {
+			return err
+		}
+		client, err := a.api()
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 1 {
+			id, err := a.resolveFeature(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			deps, err := client.GetDependencies(ctx, id)
+			if err != nil {
+				return err
+			}
+			return a.printFeatureDependencies(deps)
+		}
+
+		dependencies, err := client.ListDependencies(ctx)
+		if err != nil {
+			return err
+		}
+		return a.printDependencies(dependencies)
+
+	default:
+		return usagef("dep: unknown subcommand %q", subcommand)
+	}
+}
+
+func (a *app) cmdGraph(ctx context.Context, args []string) error {
+	fs := a.flagSet("graph")
+	format := fs.String("format", "text", "graph format (text, dot)")
+	if _, err := a.parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	features, err := client.ListFeatures(ctx)
+	if err != nil {
+		return err
+	}
+	dependencies, err := client.ListDependencies(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "dot":
+		writeDOT(a.stdout, features, dependencies)
+	case "text":
+		writeTree(a.stdout, features, dependencies)
+	default:
+		return usagef("graph: unknown format %q", *format)
+	}
+	return nil
+}
+
+func (a *app) cmdExport(ctx context.Context, args []string) error {
+	fs := a.flagSet("export")
+	file := fs.String("f", "", "write to file instead of stdout")
+	format := fs.String("format", "yaml", "manifest format (yaml, json)")
+	if _, err := a.parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if *format != "yaml" && *format != "json" {
+		return usagef("export: unknown format %q", *format)
+	}
+
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	m, err := client.ExportManifest(ctx)
+	if err != nil {
+		return err
+	}
+	data, err := m.Marshal(*format)
+	if err != nil {
+		return err
+	}
+
+	if *file == "" {
+		_, err = a.stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(*file, data, 0o644)
+}
+
+func (a *app) cmdImport(ctx context.Context, args []string) error {
+	fs := a.flagSet("import")
+	file := fs.String("f", "", "YAML or JSON manifest to import")
+	from := fs.String("from", "", "read an unleash, launchdarkly or flagsmith export instead of a manifest")
+	env := fs.String("env", "", "environment to take from the export")
+	dryRun := fs.Bool("dry-run", false, "only print the plan")
+	prune := fs.Bool("prune", false, "delete features and dependencies missing from the manifest")
+	detailedExit := fs.Bool("exit-code", false, "with --dry-run, exit with code 8 when the plan is not empty")
+	if _, err := a.parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if *file == "" {
+		return usagef("import: -f is required")
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+	var m *manifest.Manifest
+	if *from != "" {
+		var report *importer.Report
+		m, report, err = importer.Import(importer.Source(*from), data, importer.Options{Environment: *env})
+		if err != nil {
+			return a.importError(*file, err)
+		}
+		fmt.Fprint(a.stderr, report)
+	} else if m, err = manifest.Parse(data); err != nil {
+		return fmt.Errorf("%s: %w", *file, err)
+	}
+
+	client, err := a.api()
+	if err != nil {
+		return err
+	}
+	plan, err := client.PlanManifest(ctx, m, *prune, !*dryRun)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Applied != nil {
+		fmt.Fprintln(a.stderr, "The import failed partway; these changes were applied:")
+		apiErr.Applied.Write(a.stderr)
+	}
+	if err != nil {
+		return err
+	}
+
+	if a.output == "json" {
+		if err := a.printJSON(plan); err != nil {
+			return err
+		}
+	} else {
+		plan.Write(a.stdout)
+	}
+
+	if *dryRun && *detailedExit && !plan.Empty() {
+		return &driftError{msg: "server differs from manifest"}
+	}
+	return nil
+}
+
+// cmdConvert turns an export from another flag system into a manifest that
+// can be reviewed and imported later.
+func (a *app) cmdConvert(args []string) error {
+	fs := a.flagSet("convert")
+	from := fs.String("from", "", "export format: unleash, launchdarkly or flagsmith")
+	file := fs.String("f", "", "export file to read")
+	out := fs.String("out", "", "write the manifest to file instead of stdout")
+	env := fs.String("env", "", "environment to take from the export")
+	featureType := fs.String("type", "basic", "type given to every imported feature")
+	format := fs.String("format", "yaml", "manifest format (yaml, json)")
+	if _, err := a.parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if *from == "" || *file == "" {
+		return usagef("convert: --from and -f are required")
+	}
+	if *format != "yaml" && *format != "json" {
+		return usagef("convert: unknown format %q", *format)
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+	m, report, err := importer.Import(importer.Source(*from), data, importer.Options{
+		Environment: *env,
+		Type:        models.FeatureType(*featureType),
+	})
+	if err != nil {
+		return a.importError(*file, err)
+	}
+
+	encoded, err := m.Marshal(*format)
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		_, err = a.stdout.Write(encoded)
+	} else {
+		err = os.WriteFile(*out, encoded, 0o644)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(a.stderr, report)
+	return nil
+}
+
+func (a *app) importError(file string, err error) error {
+	if errors.Is(err, importer.ErrUnknownSource) {
+		return &usageError{msg: err.Error()}
+	}
+	return fmt.Errorf("%s: %w", file, err)
+}
+
+func (a *app) cmdProfile(args []string) error {
+	if len(args) == 0 {
+		return usagef("profile: expected ls, set or use")
+	}
+
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "ls":
+		names := make([]string, 0, len(cfg.Profiles))
+		for name := range cfg.Profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			marker := " "
+			if name == cfg.Current {
+				marker = "*"
+			}
+			fmt.Fprintf(a.stdout, "%s %s\t%s\n", marker, name, cfg.Profiles[name].Server)
+		}
+		return nil
+
+	case "set":
+		fs := a.flagSet("profile set")
+		args, err := a.parse(fs, args[1:], 1, 1)
+		if err != nil {
+			return err
+		}
+		if a.server == "" {
+			return usagef("profile set: --server is required")
+		}
+		name := args[0]
+		cfg.Profiles[name] = &Profile{Server: a.server, Token: a.token}
+		if cfg.Current == "" {
+			cfg.Current = name
+		}
+		return cfg.save(path)
+
+	case "use":
+		fs := a.flagSet("profile use")
+		args, err := a.parse(fs, args[1:], 1, 1)
+		if err != nil {
+			return err
+		}
+		name := args[0]
+		if _, ok := cfg.Profiles[name]; !ok {
+			return usagef("profile %q not found", name)
+		}
+		cfg.Current = name
+		return cfg.save(path)
+
+	default:
+		return usagef("profile: unknown subcommand %q", args[0])
+	}
+}
+
+// reportPending prints the change request created for a protected feature
+// and passes the error through so the command exits with exitPending.
+func (a *app) reportPending(err error) error {
+	var pending *PendingError
+	if errors.As(err, &pending) {
+		if a.output == "json" {
+			_ = a.printJSON(pending.ChangeRequest)
+		} else {
+			fmt.Fprintf(a.stdout, "change request %s created; awaiting approval\n", pending.ChangeRequest.ID.Hex())
+		}
+	}
+	return err
+}
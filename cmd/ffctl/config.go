@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const defaultServer = "http://localhost:8080"
+
+// Profile holds the connection settings for one feature flags server.
+type Profile struct {
+	Server string `json:"server"`
+	Token  string `json:"token,omitempty"`
+}
+
+// Config is the ffctl configuration file. It lives at $FFCTL_CONFIG or
+// <user config dir>/ffctl/config.json.
+type Config struct {
+	Current  string              `json:"current"`
+	Profiles map[string]*Profile `json:"profiles"`
+}
+
+func configPath() (string, error) {
+	if path := os.Getenv("FFCTL_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "ffctl", "config.json"), nil
+}
+
+// loadConfig reads the configuration file. A missing file yields an empty
+// configuration.
+func loadConfig(path string) (*Config, error) {
+	cfg := &Config{Profiles: map[string]*Profile{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]*Profile{}
+	}
+	return cfg, nil
+}
+
+func (c *Config) save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Profiles may hold tokens, so keep the file private.
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// resolveProfile picks the active profile: the explicit name if given,
+// otherwise $FFCTL_PROFILE, otherwise the configured current profile.
+func (c *Config) resolveProfile(name string) (*Profile, error) {
+	if name == "" {
+		name = os.Getenv("FFCTL_PROFILE")
+	}
+	if name == "" {
+		name = c.Current
+	}
+	if name == "" {
+		return &Profile{}, nil
+	}
+
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %q not found", name)
+	}
+	return profile, nil
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"feature-flags/internal/manifest"
+	"feature-flags/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type fakeAPI struct {
+	features     []*models.Feature
+	dependencies []models.FeatureDependency
+	protected    map[string]bool
+	lastAuth     string
+	lastManifest *manifest.Manifest
+	lastQuery    string
+}
+
+func newFakeAPI() *fakeAPI {
+	parent := &models.Feature{ID: primitive.NewObjectID(), Name: "checkout", Type: models.FeatureTypeBasic, IsEnabled: true}
+	child := &models.Feature{ID: primitive.NewObjectID(), Key: "express-checkout", Name: "one-click", Type: models.FeatureTypePremium, IsEnabled: true, Protected: true}
+	return &fakeAPI{
+		features:     []*models.Feature{parent, child},
+		dependencies: []models.FeatureDependency{{ParentID: parent.ID, ChildID: child.ID}},
+		protected:    map[string]bool{child.ID.Hex(): true},
+	}
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lastAuth = r.Header.Get("Authorization")
+	writeJSON := func(status int, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(v)
+	}
+	find := func(id string) *models.Feature {
+		for _, feature := range f.features {
+			if feature.ID.Hex() == id {
+				return feature
+			}
+		}
+		return nil
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/manifest") {
+		f.serveManifest(w, r, writeJSON)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/features")
+	switch {
+	case r.Method == http.MethodGet && path == "":
+		writeJSON(http.StatusOK, f.features)
+	case r.Method == http.MethodGet && path == "/dependencies":
+		writeJSON(http.StatusOK, f.dependencies)
+	case r.Method == http.MethodGet:
+		feature := find(strings.TrimPrefix(path, "/"))
+		if feature == nil {
+			writeJSON(http.StatusNotFound, map[string]string{"error": "feature not found"})
+			return
+		}
+		writeJSON(http.StatusOK, feature)
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/disable"):
+		id := strings.TrimSuffix(strings.TrimPrefix(path, "/"), "/disable")
+		if r.URL.Query().Get("dry_run") == "true" {
+			writeJSON(http.StatusOK, map[string]interface{}{"dry_run": true, "features": f.features})
+			return
+		}
+		if f.protected[id] {
+			writeJSON(http.StatusAccepted, models.ChangeRequest{ID: primitive.NewObjectID(), Status: models.ChangeRequestStatusPending})
+			return
+		}
+		writeJSON(http.StatusOK, map[string]string{"message": "feature disabled successfully"})
+	case r.Method == http.MethodPost && path == "/dependencies":
+		writeJSON(http.StatusUnprocessableEntity, map[string]interface{}{
+			"type":   "urn:feature-flags:problem:dependency-cycle",
+			"title":  "Dependency cycle",
+			"status": http.StatusUnprocessableEntity,
+			"detail": "cyclic dependency detected: a -> b -> a",
+		})
+	default:
+		writeJSON(http.StatusNotFound, map[string]string{"error": "not found"})
+	}
+}
+
+func (f *fakeAPI) serveManifest(w http.ResponseWriter, r *http.Request, writeJSON func(int, interface{})) {
+	if r.Method == http.MethodGet {
+		writeJSON(http.StatusOK, manifest.Manifest{
+			Features: []manifest.Feature{
+				{Name: "checkout", Type: models.FeatureTypeBasic, Enabled: true},
+				{Name: "one-click", Type: models.FeatureTypePremium, Enabled: true, Protected: true},
+			},
+			Dependencies: []manifest.Dependency{{Parent: "checkout", Child: "one-click"}},
+		})
+		return
+	}
+
+	var m manifest.Manifest
+	if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+		writeJSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	f.lastManifest = &m
+	f.lastQuery = r.URL.RawQuery
+
+	plan := manifest.NewPlan()
+	for _, feature := range m.Features {
+		if feature.Name == "wallet" {
+			plan.Creates = append(plan.Creates, manifest.FeatureChange{Name: "wallet"})
+		}
+	}
+	plan.Applied = strings.HasSuffix(r.URL.Path, "/apply")
+	writeJSON(http.StatusOK, plan)
+}
+
+func runFFCTL(t *testing.T, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func setupFFCTL(t *testing.T) (*fakeAPI, string) {
+	t.Setenv("FFCTL_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	t.Setenv("FFCTL_SERVER", "")
+	t.Setenv("FFCTL_TOKEN", "")
+	t.Setenv("FFCTL_PROFILE", "")
+
+	api := newFakeAPI()
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+	return api, srv.URL
+}
+
+func TestFFCTL_ListAndGet(t *testing.T) {
+	api, server := setupFFCTL(t)
+
+	code, out, _ := runFFCTL(t, "--server", server, "list", "-o", "json")
+	require.Equal(t, exitOK, code)
+	var features []*models.Feature
+	require.NoError(t, json.Unmarshal([]byte(out), &features))
+	assert.Len(t, features, 2)
+
+	code, out, _ = runFFCTL(t, "get", "checkout", "--server", server)
+	require.Equal(t, exitOK, code)
+	assert.Contains(t, out, "checkout")
+	assert.Contains(t, out, "ENABLED")
+
+	code, _, _ = runFFCTL(t, "get", "checkout", "--server", server, "--expect", "disabled")
+	assert.Equal(t, exitCheckFailed, code)
+
+	code, _, _ = runFFCTL(t, "get", "missing", "--server", server)
+	assert.Equal(t, exitNotFound, code)
+
+	code, out, _ = runFFCTL(t, "get", "express-checkout", "--server", server)
+	require.Equal(t, exitOK, code)
+	assert.Contains(t, out, "one-click")
+
+	code, _, _ = runFFCTL(t, "get", api.features[0].ID.Hex(), "--server", server, "--token", "secret")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "Bearer secret", api.lastAuth)
+}
+
+func TestFFCTL_Disable(t *testing.T) {
+	_, server := setupFFCTL(t)
+
+	code, out, _ := runFFCTL(t, "--server", server, "disable", "--dry-run", "checkout")
+	require.Equal(t, exitOK, code)
+	assert.Contains(t, out, "one-click")
+
+	code, _, _ = runFFCTL(t, "--server", server, "disable", "checkout")
+	assert.Equal(t, exitOK, code)
+
+	code, out, _ = runFFCTL(t, "--server", server, "disable", "one-click")
+	assert.Equal(t, exitPending, code)
+	assert.Contains(t, out, "awaiting approval")
+}
+
+func TestFFCTL_Graph(t *testing.T) {
+	_, server := setupFFCTL(t)
+
+	code, out, _ := runFFCTL(t, "--server", server, "graph")
+	require.Equal(t, exitOK, code)
+	assert.Equal(t, "checkout [on]\n  one-click [on]\n", out)
+
+	code, out, _ = runFFCTL(t, "--server", server, "graph", "--format", "dot")
+	require.Equal(t, exitOK, code)
+	assert.Contains(t, out, "digraph features")
+	assert.Contains(t, out, "->")
+}
+
+func TestFFCTL_Profiles(t *testing.T) {
+	api, server := setupFFCTL(t)
+
+	code, _, _ := runFFCTL(t, "profile", "set", "staging", "--server", server, "--token", "staging-token")
+	require.Equal(t, exitOK, code)
+	code, _, _ = runFFCTL(t, "profile", "set", "prod", "--server", "http://127.0.0.1:1")
+	require.Equal(t, exitOK, code)
+
+	code, out, _ := runFFCTL(t, "profile", "ls")
+	require.Equal(t, exitOK, code)
+	assert.Contains(t, out, "* staging")
+
+	// The current profile supplies the server and token.
+	code, _, _ = runFFCTL(t, "list")
+	require.Equal(t, exitOK, code)
+	assert.Equal(t, "Bearer staging-token", api.lastAuth)
+
+	code, _, _ = runFFCTL(t, "--profile", "missing", "list")
+	assert.Equal(t, exitError, code)
+}
+
+func TestFFCTL_ExitCodes(t *testing.T) {
+	_, server := setupFFCTL(t)
+
+	code, _, _ := runFFCTL(t, "frobnicate")
+	assert.Equal(t, exitUsage, code)
+
+	code, _, _ = runFFCTL(t, "get")
+	assert.Equal(t, exitUsage, code)
+
+	code, _, _ = runFFCTL(t, "list", "-o", "yaml")
+	assert.Equal(t, exitUsage, code)
+
+	code, _, stderr := runFFCTL(t, "--server", server, "dep", "add", "one-click", "checkout")
+	assert.Equal(t, exitRejected, code)
+	assert.Contains(t, stderr, "cyclic dependency detected")
+}
+
+func TestFFCTL_ExportImport(t *testing.T) {
+	api, server := setupFFCTL(t)
+	file := filepath.Join(t.TempDir(), "flags.yaml")
+
+	code, _, _ := runFFCTL(t, "--server", server, "export", "-f", file)
+	require.Equal(t, exitOK, code)
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "name: one-click")
+
+	// An unchanged manifest has an empty plan.
+	code, out, _ := runFFCTL(t, "--server", server, "import", "-f", file, "--dry-run", "--exit-code")
+	require.Equal(t, exitOK, code)
+	assert.Contains(t, out, "Plan: 0 to create")
+	assert.Equal(t, "", api.lastQuery)
+
+	m := "features:\n  - name: checkout\n    type: basic\n    enabled: true\n  - name: wallet\n    type: basic\n    enabled: true\n"
+	require.NoError(t, os.WriteFile(file, []byte(m), 0o644))
+
+	code, out, _ = runFFCTL(t, "--server", server, "import", "-f", file, "--dry-run", "--exit-code", "--prune")
+	assert.Equal(t, exitDrift, code)
+	assert.Contains(t, out, "+ create wallet")
+	assert.Equal(t, "prune=true", api.lastQuery)
+
+	code, out, _ = runFFCTL(t, "--server", server, "import", "-f", file, "-o", "json")
+	require.Equal(t, exitOK, code)
+	var plan manifest.Plan
+	require.NoError(t, json.Unmarshal([]byte(out), &plan))
+	assert.True(t, plan.Applied)
+	assert.Len(t, api.lastManifest.Features, 2)
+
+	// Invalid manifests are rejected before anything is sent.
+	require.NoError(t, os.WriteFile(file, []byte("features:\n  - name: x\n    type: gold\n"), 0o644))
+	code, _, stderr := runFFCTL(t, "--server", server, "import", "-f", file)
+	assert.Equal(t, exitRejected, code)
+	assert.Contains(t, stderr, `unknown type "gold"`)
+}
+
+func TestFFCTL_Convert(t *testing.T) {
+	api, server := setupFFCTL(t)
+	dir := t.TempDir()
+	export := filepath.Join(dir, "unleash.json")
+	require.NoError(t, os.WriteFile(export, []byte(`{
+  "features": [{"name": "checkout"}, {"name": "wallet"}],
+  "featureStrategies": [
+    {"featureName": "checkout", "environment": "production", "strategyName": "default"},
+    {"featureName": "wallet", "environment": "production", "strategyName": "flexibleRollout", "parameters": {"rollout": "10"}}
+  ],
+  "featureEnvironments": [
+    {"featureName": "checkout", "environment": "production", "enabled": true},
+    {"featureName": "wallet", "environment": "production", "enabled": true}
+  ]
+}`), 0o644))
+
+	out := filepath.Join(dir, "flags.yaml")
+	code, _, stderr := runFFCTL(t, "convert", "--from", "unleash", "-f", export, "--out", out)
+	require.Equal(t, exitOK, code, stderr)
+	assert.Contains(t, stderr, "percentage rollout of 10%")
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "name: wallet")
+
+	code, _, _ = runFFCTL(t, "--server", server, "import", "--from", "unleash", "-f", export, "--dry-run")
+	require.Equal(t, exitOK, code)
+	assert.Len(t, api.lastManifest.Features, 2)
+
+	code, _, _ = runFFCTL(t, "convert", "--from", "split", "-f", export)
+	assert.Equal(t, exitUsage, code)
+}
@@ -0,0 +1,262 @@
+// Command ffctl manages feature flags from terminals and CI pipelines by
+// talking to the feature flags HTTP API.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"feature-flags/internal/manifest"
+)
+
+// Exit codes. CI gates can rely on these staying stable.
+const (
+	exitOK           = 0
+	exitError        = 1 // unexpected or server-side failure
+	exitUsage        = 2 // invalid command line
+	exitNotFound     = 3 // feature or dependency does not exist
+	exitRejected     = 4 // request rejected: validation, conflict, cycle, disabled parent
+	exitUnauthorized = 5 // missing or insufficient credentials
+	exitPending      = 6 // change recorded as a change request awaiting approval
+	exitCheckFailed  = 7 // a --expect assertion did not hold
+	exitDrift        = 8 // import --dry-run --exit-code found changes
+)
+
+const usage = `Usage: ffctl [global flags] <command> [flags] [args]
+
+Commands:
+  list                          List features
+  get <feature>                 Show a feature (--expect enabled|disabled)
+  create --name N --type T      Create a feature (--enabled, --protected)
+  enable <feature>              Enable a feature
+  disable <feature>             Disable a feature and its dependents (--dry-run)
+  dep add <parent> <child>      Add a dependency
+  dep rm <parent> <child>       Remove a dependency
+  dep ls [feature]              List dependencies of a feature, or all edges
+  graph                         Print the dependency graph (--format dot|text)
+  export                        Export the manifest (-f file, --format yaml|json)
+  import -f file                Apply a manifest (--dry-run, --prune, --exit-code,
+                                --from unleash|launchdarkly|flagsmith, --env)
+  convert --from SRC -f file    Convert another system's export to a manifest
+  profile ls|set|use            Manage server profiles
+
+Features can be given by ID, key or name.
+
+Global flags (also accepted after the command):
+  --profile NAME   profile from the config file ($FFCTL_PROFILE)
+  --server URL     server base URL ($FFCTL_SERVER)
+  --token TOKEN    bearer token ($FFCTL_TOKEN)
+  -o FORMAT        output format: table or json
+`
+
+type app struct {
+	stdout io.Writer
+	stderr io.Writer
+
+	profile string
+	server  string
+	token   string
+	output  string
+
+	client *Client
+}
+
+// usageError marks errors caused by an invalid command line.
+type usageError struct{ msg string }
+
+func (e *usageError) Error() string { return e.msg }
+
+func usagef(format string, args ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// checkError marks a failed --expect assertion.
+type checkError struct{ msg string }
+
+func (e *checkError) Error() string { return e.msg }
+
+// driftError marks a non-empty plan under import --exit-code.
+type driftError struct{ msg string }
+
+func (e *driftError) Error() string { return e.msg }
+
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	a := &app{stdout: stdout, stderr: stderr}
+
+	fs := a.flagSet("ffctl")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	err := a.dispatch(ctx, fs.Arg(0), fs.Args()[1:])
+	if err == nil {
+		return exitOK
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+
+	fmt.Fprintln(stderr, "Error:", err)
+	return exitCode(err)
+}
+
+func (a *app) dispatch(ctx context.Context, command string, args []string) error {
+	switch command {
+	case "list":
+		return a.cmdList(ctx, args)
+	case "get":
+		return a.cmdGet(ctx, args)
+	case "create":
+		return a.cmdCreate(ctx, args)
+	case "enable":
+		return a.cmdEnable(ctx, args)
+	case "disable":
+		return a.cmdDisable(ctx, args)
+	case "dep":
+		return a.cmdDep(ctx, args)
+	case "graph":
+		return a.cmdGraph(ctx, args)
+	case "export":
+		return a.cmdExport(ctx, args)
+	case "import":
+		return a.cmdImport(ctx, args)
+	case "convert":
+		return a.cmdConvert(args)
+	case "profile":
+		return a.cmdProfile(args)
+	case "help":
+		fmt.Fprint(a.stdout, usage)
+		return nil
+	default:
+		return usagef("unknown command %q", command)
+	}
+}
+
+// flagSet returns a flag set that also accepts the global flags, so they can
+// appear before or after the command name.
+func (a *app) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() { fmt.Fprint(a.stderr, usage) }
+	fs.StringVar(&a.profile, "profile", a.profile, "config profile")
+	fs.StringVar(&a.server, "server", a.server, "server base URL")
+	fs.StringVar(&a.token, "token", a.token, "bearer token")
+	fs.StringVar(&a.output, "o", a.output, "output format (table, json)")
+	return fs
+}
+
+// parse parses command flags, which may be interleaved with positional
+// arguments, and checks the positional argument count.
+func (a *app) parse(fs *flag.FlagSet, args []string, minArgs, maxArgs int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, &usageError{msg: err.Error()}
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if len(positional) < minArgs || (maxArgs >= 0 && len(positional) > maxArgs) {
+		return nil, usagef("%s: wrong number of arguments", fs.Name())
+	}
+	switch a.output {
+	case "", "table", "json":
+	default:
+		return nil, usagef("unknown output format %q", a.output)
+	}
+	return positional, nil
+}
+
+// api returns the API client for the active profile. Flags take precedence
+// over environment variables, which take precedence over the profile.
+func (a *app) api() (*Client, error) {
+	if a.client != nil {
+		return a.client, nil
+	}
+
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := cfg.resolveProfile(a.profile)
+	if err != nil {
+		return nil, err
+	}
+
+	server := firstNonEmpty(a.server, os.Getenv("FFCTL_SERVER"), profile.Server, defaultServer)
+	token := firstNonEmpty(a.token, os.Getenv("FFCTL_TOKEN"), profile.Token)
+
+	a.client = NewClient(server, token)
+	return a.client, nil
+}
+
+func exitCode(err error) int {
+	var usageErr *usageError
+	var checkErr *checkError
+	var driftErr *driftError
+	var pendingErr *PendingError
+	var apiErr *APIError
+
+	switch {
+	case errors.As(err, &usageErr):
+		return exitUsage
+	case errors.As(err, &checkErr):
+		return exitCheckFailed
+	case errors.As(err, &driftErr):
+		return exitDrift
+	case errors.As(err, &pendingErr):
+		return exitPending
+	case errors.As(err, &apiErr):
+		switch {
+		case apiErr.StatusCode == http.StatusNotFound:
+			return exitNotFound
+		case apiErr.StatusCode == http.StatusUnauthorized, apiErr.StatusCode == http.StatusForbidden:
+			return exitUnauthorized
+		case apiErr.StatusCode >= 400 && apiErr.StatusCode < 500:
+			return exitRejected
+		}
+	case errors.Is(err, errFeatureNotFound):
+		return exitNotFound
+	case errors.Is(err, manifest.ErrInvalid):
+		return exitRejected
+	}
+	return exitError
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
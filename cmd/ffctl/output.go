@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"feature-flags/internal/models"
+)
+
+func (a *app) printJSON(v interface{}) error {
+	enc := json.NewEncoder(a.stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func (a *app) table() *tabwriter.Writer {
+	return tabwriter.NewWriter(a.stdout, 0, 4, 2, ' ', 0)
+}
+
+func (a *app) printFeatures(features []*models.Feature) error {
+	if a.output == "json" {
+		if features == nil {
+			features = []*models.Feature{}
+		}
+		return a.printJSON(features)
+	}
+
+	w := a.table()
+	fmt.Fprintln(w, "ID\tNAME\tTYPE\tENABLED\tPROTECTED")
+	for _, f := range features {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%t\n", f.ID.Hex(), f.Name, f.Type, f.IsEnabled, f.Protected)
+	}
+	return w.Flush()
+}
+
+func (a *app) printMessage(action, subject string) error {
+	if a.output == "json" {
+		return a.printJSON(map[string]string{"result": action, "subject": subject})
+	}
+	_, err := fmt.Fprintf(a.stdout, "%s: %s\n", action, subject)
+	return err
+}
+
+func (a *app) printDependencies(dependencies []models.FeatureDependency) error {
+	if a.output == "json" {
+		if dependencies == nil {
+			dependencies = []models.FeatureDependency{}
+		}
+		return a.printJSON(dependencies)
+	}
+
+	w := a.table()
+	fmt.Fprintln(w, "PARENT\tCHILD")
+	for _, d := range dependencies {
+		fmt.Fprintf(w, "%s\t%s\n", d.ParentID.Hex(), d.ChildID.Hex())
+	}
+	return w.Flush()
+}
+
+func (a *app) printFeatureDependencies(deps *featureDependencies) error {
+	if a.output == "json" {
+		return a.printJSON(deps)
+	}
+
+	w := a.table()
+	fmt.Fprintln(w, "RELATION\tFEATURE")
+	for _, id := range deps.Parents {
+		fmt.Fprintf(w, "parent\t%s\n", id)
+	}
+	for _, id := range deps.Children {
+		fmt.Fprintf(w, "child\t%s\n", id)
+	}
+	return w.Flush()
+}
+
+// writeDOT renders the dependency graph in Graphviz DOT format, with enabled
+// features filled green.
+func writeDOT(w io.Writer, features []*models.Feature, dependencies []models.FeatureDependency) {
+	fmt.Fprintln(w, "digraph features {")
+	fmt.Fprintln(w, "  node [shape=box, style=filled];")
+	for _, f := range features {
+		color := "lightgray"
+		if f.IsEnabled {
+			color = "palegreen"
+		}
+		fmt.Fprintf(w, "  %q [label=%q, fillcolor=%s];\n", f.ID.Hex(), f.Name, color)
+	}
+	for _, d := range dependencies {
+		fmt.Fprintf(w, "  %q -> %q;\n", d.ParentID.Hex(), d.ChildID.Hex())
+	}
+	fmt.Fprintln(w, "}")
+}
+
+// writeTree renders the dependency graph as an indented tree rooted at the
+// features without parents. Features with several parents appear under each.
+func writeTree(w io.Writer, features []*models.Feature, dependencies []models.FeatureDependency) {
+	byID := make(map[string]*models.Feature, len(features))
+	for _, f := range features {
+		byID[f.ID.Hex()] = f
+	}
+	children := make(map[string][]string)
+	hasParent := make(map[string]bool)
+	for _, d := range dependencies {
+		parent, child := d.ParentID.Hex(), d.ChildID.Hex()
+		children[parent] = append(children[parent], child)
+		hasParent[child] = true
+	}
+
+	byName := func(ids []string) {
+		sort.Slice(ids, func(i, j int) bool { return nameOf(byID, ids[i]) < nameOf(byID, ids[j]) })
+	}
+
+	var walk func(id string, depth int)
+	walk = func(id string, depth int) {
+		state := "off"
+		if f, ok := byID[id]; ok && f.IsEnabled {
+			state = "on"
+		}
+		fmt.Fprintf(w, "%s%s [%s]\n", strings.Repeat("  ", depth), nameOf(byID, id), state)
+		kids := children[id]
+		byName(kids)
+		for _, child := range kids {
+			walk(child, depth+1)
+		}
+	}
+
+	roots := make([]string, 0)
+	for _, f := range features {
+		if !hasParent[f.ID.Hex()] {
+			roots = append(roots, f.ID.Hex())
+		}
+	}
+	byName(roots)
+	for _, id := range roots {
+		walk(id, 0)
+	}
+}
+
+func nameOf(byID map[string]*models.Feature, id string) string {
+	if f, ok := byID[id]; ok {
+		return f.Name
+	}
+	return id
+}
@@ -15,101 +15,174 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/features": {
-            "post": {
-                "description": "Create a new feature flag",
-                "consumes": [
-                    "application/json"
+        "/api/audit": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "List safety actions taken on features, such as kill switch trips, rollout steps and rollout aborts, newest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "features"
+                    "kill-switch"
                 ],
-                "summary": "Create a new feature",
+                "summary": "List the audit trail",
                 "parameters": [
                     {
-                        "description": "Feature to create",
+                        "type": "string",
+                        "description": "Only entries for the feature with this key",
                         "name": "feature",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.CreateFeatureRequest"
-                        }
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "kill_switch",
+                            "rollout_step",
+                            "rollout_abort"
+                        ],
+                        "type": "string",
+                        "description": "Only entries with this action",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Most entries to return, at most 1000",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Feature"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AuditEntry"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
             }
         },
-        "/api/features/dependencies": {
-            "post": {
-                "description": "Add a parent-child dependency between two features",
-                "consumes": [
-                    "application/json"
+        "/api/change-requests": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "List change requests for protected features, newest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "features"
+                    "change-requests"
                 ],
-                "summary": "Add a dependency between features",
+                "summary": "List change requests",
                 "parameters": [
                     {
-                        "description": "Dependency to add",
-                        "name": "dependency",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "Filter by status (pending, approved, rejected)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.AddDependencyRequest"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ChangeRequest"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
+                }
+            }
+        },
+        "/api/change-requests/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a change request and its diff by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "change-requests"
+                ],
+                "summary": "Get a change request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Change request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/models.ChangeRequest"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
             }
         },
-        "/api/features/{id}": {
-            "get": {
-                "description": "Get the status of a feature by ID",
+        "/api/change-requests/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Approve a pending change request and apply it. Requesters cannot approve their own requests.",
                 "consumes": [
                     "application/json"
                 ],
@@ -117,49 +190,74 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "features"
+                    "change-requests"
                 ],
-                "summary": "Get feature status",
+                "summary": "Approve a change request",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Feature ID",
+                        "description": "Change request ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Review comment",
+                        "name": "review",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReviewChangeRequestRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Feature"
+                            "$ref": "#/definitions/models.ChangeRequest"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
             }
         },
-        "/api/features/{id}/disable": {
+        "/api/change-requests/{id}/reject": {
             "post": {
-                "description": "Disable a feature by ID",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reject a pending change request without applying it",
                 "consumes": [
                     "application/json"
                 ],
@@ -167,43 +265,74 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "features"
+                    "change-requests"
                 ],
-                "summary": "Disable a feature",
+                "summary": "Reject a change request",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Feature ID",
+                        "description": "Change request ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Review comment",
+                        "name": "review",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReviewChangeRequestRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/models.ChangeRequest"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
             }
         },
-        "/api/features/{id}/enable": {
+        "/api/events/conversions": {
             "post": {
-                "description": "Enable a feature by ID",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Record that contexts completed a goal, e.g. a purchase. Conversions are matched to exposures by targeting key. Conversions without a timestamp get the current time; those timestamped more than 5 minutes in the future are rejected. At most 1000 may be sent at once.",
                 "consumes": [
                     "application/json"
                 ],
@@ -211,102 +340,3967 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "features"
+                    "experiments"
                 ],
-                "summary": "Enable a feature",
+                "summary": "Track conversion events",
+                "parameters": [
+                    {
+                        "description": "Conversions to record",
+                        "name": "conversions",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TrackConversionsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TrackConversionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing event or targeting key, future timestamp, or too many conversions",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/events/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stream exposure or conversion events, oldest first, as CSV with a header row or as JSON Lines",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "experiments"
+                ],
+                "summary": "Export raw events",
                 "parameters": [
                     {
+                        "enum": [
+                            "exposures",
+                            "conversions"
+                        ],
                         "type": "string",
-                        "description": "Feature ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Events to export",
+                        "name": "type",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "jsonl"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Output format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only exposures to this flag",
+                        "name": "flag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only conversions for this event",
+                        "name": "event",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events at or after this RFC 3339 time",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events before this RFC 3339 time",
+                        "name": "until",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Events",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "type": "string"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handlers.AddDependencyRequest": {
-            "type": "object",
-            "required": [
-                "child_id",
-                "parent_id"
-            ],
-            "properties": {
-                "child_id": {
-                    "type": "string"
-                },
-                "parent_id": {
-                    "type": "string"
+        },
+        "/api/experiments/{key}/results": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Compare the conversion rates of a flag's variants for a conversion event. Only contexts assigned by a weighted split count, each with the variant of its first exposure; a context converted if it sent the event at or after that exposure. Every variant but the control gets the difference from the control, with its confidence interval and p-value.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "experiments"
+                ],
+                "summary": "Get experiment results",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Flag key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Conversion event",
+                        "name": "event",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Control variant; defaults to the default variant, or the first",
+                        "name": "control",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events at or after this RFC 3339 time",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events before this RFC 3339 time",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "default": 0.95,
+                        "description": "Confidence level of the intervals",
+                        "name": "confidence",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ExperimentResults"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Flag not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
                 }
             }
         },
-        "handlers.CreateFeatureRequest": {
-            "type": "object",
-            "required": [
-                "name",
-                "type"
-            ],
-            "properties": {
-                "is_enabled": {
+        "/api/features": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List feature flags ordered by name, optionally filtered by type, enabled state, tags, owner, segment and guard",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "features"
+                ],
+                "summary": "List features",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only features of this type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only enabled (true) or disabled (false) features",
+                        "name": "enabled",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only features with this tag; repeat to require several",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only features owned by this team",
+                        "name": "owner",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only features whose rules reference this segment",
+                        "name": "segment",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only features with a guard rule",
+                        "name": "guarded",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Feature"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new feature flag",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "features"
+                ],
+                "summary": "Create a new feature",
+                "parameters": [
+                    {
+                        "description": "Feature to create",
+                        "name": "feature",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateFeatureRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Feature"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Key already exists, the feature is managed by GitOps sync, or it is protected while authentication is off",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/features/dependencies": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List every parent-child dependency edge",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "features"
+                ],
+                "summary": "List dependencies",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.FeatureDependency"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a parent-child dependency between two features, each given by ID or by key",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "features"
+                ],
+                "summary": "Add a dependency between features",
+                "parameters": [
+                    {
+                        "description": "Dependency to add",
+                        "name": "dependency",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AddDependencyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MessageResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Pending approval (protected feature)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChangeRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Feature not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Dependency already exists, or a feature is managed by GitOps sync",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Dependency would create a cycle; cycle_path lists it",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a parent-child dependency between two features",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "features"
+                ],
+                "summary": "Remove a dependency between features",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Parent feature ID",
+                        "name": "parent_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Child feature ID",
+                        "name": "child_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Feature is managed by GitOps sync",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/features/key/{key}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the status of a feature by its key",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "features"
+                ],
+                "summary": "Get feature status by key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feature key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Feature"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/features/key/{key}/disable": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Disable a feature by its key, cascading to every enabled feature that depends on it. With dry_run=true the cascade is returned without disabling anything.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "features"
+                ],
+                "summary": "Disable a feature by key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feature key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only report the features that would be disabled",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MessageResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Pending approval (protected feature)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChangeRequest"
+                        }
+                    },
+                    "404": {
+                        "description": "Feature not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Feature is managed by GitOps sync",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/features/key/{key}/enable": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Enable a feature by its key",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "features"
+                ],
+                "summary": "Enable a feature by key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feature key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MessageResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Pending approval (protected feature)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChangeRequest"
+                        }
+                    },
+                    "404": {
+                        "description": "Feature not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Feature is managed by GitOps sync",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "A parent is disabled; parent_ids lists them",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/features/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the status of a feature by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "features"
+                ],
+                "summary": "Get feature status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feature ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Feature"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/features/{id}/dependencies": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the direct parents and children of a feature",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "features"
+                ],
+                "summary": "Get feature dependencies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feature ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FeatureDependenciesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/features/{id}/disable": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Disable a feature by ID, cascading to every enabled feature that depends on it. With dry_run=true the cascade is returned without disabling anything.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "features"
+                ],
+                "summary": "Disable a feature",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feature ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only report the features that would be disabled",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MessageResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Pending approval (protected feature)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChangeRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Feature not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Feature is managed by GitOps sync",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/features/{id}/enable": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Enable a feature by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "features"
+                ],
+                "summary": "Enable a feature",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feature ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MessageResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Pending approval (protected feature)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChangeRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Feature not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Feature is managed by GitOps sync",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "A parent is disabled; parent_ids lists them",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/features/{id}/guard": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Turn the feature off automatically, with its full disable cascade, when the metric over the signals reported for it in the last window reaches the threshold. error_rate is errors / (errors + successes) and needs a threshold in (0, 1]; error_count needs a threshold of at least 1. Windows are at most 24h. Trips of protected features create a change request instead. Setting a rule clears when it last tripped. GitOps-managed features cannot have guards.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "features"
+                ],
+                "summary": "Set feature guard rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feature ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Guard rule",
+                        "name": "guard",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.GuardRule"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Feature"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Feature is managed by GitOps sync",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stop turning the feature off automatically",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "features"
+                ],
+                "summary": "Remove feature guard rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feature ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Feature"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Feature is managed by GitOps sync",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/features/{id}/metadata": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the description, tags, owner, maintainer, links and custom attributes of a feature. Metadata does not affect evaluation, so the change applies at once even to protected or GitOps-managed features and the feature's version is unchanged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "features"
+                ],
+                "summary": "Set feature metadata",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feature ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Feature metadata",
+                        "name": "metadata",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Metadata"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Feature"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/features/{id}/overrides": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the allow and deny overrides of a feature",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "features"
+                ],
+                "summary": "List feature overrides",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feature ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Override"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Turn a feature on (allow) or off (deny) for the evaluation contexts whose attribute has a given value, ahead of the enabled state, rules and splits. Overrides replace existing ones with the same attribute and value. All are applied or none: an invalid override or exceeding the per-feature limit fails the request.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "features"
+                ],
+                "summary": "Add feature overrides",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feature ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Overrides to add",
+                        "name": "overrides",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AddOverridesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Feature"
+                        }
+                    },
+                    "202": {
+                        "description": "Pending approval (protected feature)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChangeRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "The feature changed while the overrides were applied",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "The feature would exceed its override limit; limit and count give the numbers",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/features/{id}/overrides/remove": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove the overrides with the given attributes and values. Overrides the feature does not have are ignored.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "features"
+                ],
+                "summary": "Remove feature overrides",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feature ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Overrides to remove",
+                        "name": "overrides",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RemoveOverridesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Feature"
+                        }
+                    },
+                    "202": {
+                        "description": "Pending approval (protected feature)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChangeRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "The feature changed while the overrides were applied",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/features/{id}/protection": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mark a feature as protected. Enabling, disabling or adding dependencies to a protected feature creates a change request that must be approved. Approving needs an authenticated reviewer, so with authentication off features can be unprotected but not protected.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "features"
+                ],
+                "summary": "Set feature protection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feature ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Protection setting",
+                        "name": "protection",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SetProtectionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Feature"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Feature is managed by GitOps sync, or protecting it while authentication is off",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/features/{id}/rollout": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Release the feature gradually: while enabled it is only on for the percentage of targeting keys of the current step, and the plan moves to the next step once the step's hold has passed. Percentages must rise to 100 over 2 to 20 steps, and every step but the last needs a hold. Starting a plan replaces any plan the feature had and begins at the first step. Plans of disabled features do not advance. Protected features get a change request, and the plan starts once it is approved. GitOps-managed features cannot have plans.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rollouts"
+                ],
+                "summary": "Start a rollout plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feature ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rollout steps",
+                        "name": "plan",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SetRolloutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Feature"
+                        }
+                    },
+                    "202": {
+                        "description": "Pending approval (protected feature)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChangeRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Feature is managed by GitOps sync, or changed while the plan was set",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove the feature's rollout plan, so while enabled it is on for every context again. Protected features get a change request.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rollouts"
+                ],
+                "summary": "Remove the rollout plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feature ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Feature"
+                        }
+                    },
+                    "202": {
+                        "description": "Pending approval (protected feature)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChangeRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Feature is managed by GitOps sync, or changed while the plan was set",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/features/{id}/rollout/abort": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Roll an active or paused plan back to 0%, then disable the feature and every enabled feature that depends on it, as the disable endpoint does. If the cascade is protected the plan stays at 0% while the disable waits for approval, and the change request is returned. The abort is recorded in the audit trail.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rollouts"
+                ],
+                "summary": "Abort the rollout plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feature ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Feature"
+                        }
+                    },
+                    "202": {
+                        "description": "Disable pending approval (protected feature)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChangeRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "No plan, the plan is finished, or a feature is managed by GitOps sync",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/features/{id}/rollout/pause": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Keep an active plan at its current step until it is resumed. Time spent paused does not count towards the step's hold.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rollouts"
+                ],
+                "summary": "Pause the rollout plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feature ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Feature"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "No plan, or the plan is not active",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/features/{id}/rollout/resume": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Let a paused plan advance again once what remained of the current step's hold has passed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rollouts"
+                ],
+                "summary": "Resume the rollout plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feature ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Feature"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "No plan, or the plan is not paused",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/features/{id}/targeting": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the targeting rules and variants used when the feature is evaluated",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "features"
+                ],
+                "summary": "Set feature targeting",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feature ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Targeting rules and variants",
+                        "name": "targeting",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Targeting"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Feature"
+                        }
+                    },
+                    "202": {
+                        "description": "Pending approval (protected feature)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChangeRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Feature is managed by GitOps sync",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/gitops/status": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the manifest revision, managed features and drift found by the last sync",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "gitops"
+                ],
+                "summary": "Get GitOps sync status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/gitops.Status"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/gitops/sync": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reload the manifest directory and reconcile immediately, e.g. from a webhook after a git pull",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "gitops"
+                ],
+                "summary": "Sync now",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/gitops.Status"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Sync failed; see last_error",
+                        "schema": {
+                            "$ref": "#/definitions/gitops.Status"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/manifest": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Export every feature and dependency as a declarative manifest",
+                "produces": [
+                    "application/json",
+                    "application/yaml"
+                ],
+                "tags": [
+                    "manifest"
+                ],
+                "summary": "Export the flag manifest",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "yaml (default) or json",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/manifest.Manifest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/manifest/apply": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Make the server match a YAML or JSON manifest. Features are matched by key, so a manifest can rename them. All changes are applied in a single transaction. Without transaction support (a standalone MongoDB) a failed change leaves the changes before it in place, and the error lists them under \"applied\". Manifests cannot change protected features, delete them or change their dependencies; use the feature endpoints, which file change requests. GitOps sync is exempt.",
+                "consumes": [
+                    "application/json",
+                    "application/yaml"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "manifest"
+                ],
+                "summary": "Apply a manifest",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Delete features and dependencies missing from the manifest",
+                        "name": "prune",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Manifest",
+                        "name": "manifest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/manifest.Manifest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/manifest.Plan"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Manifest changes a feature managed by GitOps sync or a protected feature, or protects a feature while authentication is off",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/manifest/plan": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Compare a YAML or JSON manifest with the server and report the creates, updates, deletes, dependency changes and cascaded disables applying it would cause",
+                "consumes": [
+                    "application/json",
+                    "application/yaml"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "manifest"
+                ],
+                "summary": "Plan a manifest",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Delete features and dependencies missing from the manifest",
+                        "name": "prune",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Manifest",
+                        "name": "manifest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/manifest.Manifest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/manifest.Plan"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/segments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List every segment, ordered by key",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "segments"
+                ],
+                "summary": "List segments",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Segment"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a reusable segment that targeting rules reference by key with the in_segment and not_in_segment operators",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "segments"
+                ],
+                "summary": "Create a segment",
+                "parameters": [
+                    {
+                        "description": "Segment to create",
+                        "name": "segment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateSegmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Segment"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid key, rules or lists",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Key already exists",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/segments/{key}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "segments"
+                ],
+                "summary": "Get a segment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Segment key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Segment"
+                        }
+                    },
+                    "404": {
+                        "description": "Segment not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the name, description, rules and lists of a segment. The change applies at once to every feature whose rules reference it, including protected features.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "segments"
+                ],
+                "summary": "Replace a segment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Segment key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New segment",
+                        "name": "segment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SegmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Segment"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid rules or lists",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Segment not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "The segment changed while the update was applied",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a segment that no targeting rule references",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "segments"
+                ],
+                "summary": "Delete a segment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Segment key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MessageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Segment not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Segment in use; features lists the keys of the features referencing it",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/segments/{key}/features": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the features whose targeting rules reference the segment, ordered by name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "segments"
+                ],
+                "summary": "List the features using a segment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Segment key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Feature"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Segment not found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/signals": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Report how many calls made under a flag failed and succeeded, for the flag's guard rule. Signals without a timestamp get the current time; those timestamped more than 5 minutes in the future are rejected. At most 1000 may be sent at once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "kill-switch"
+                ],
+                "summary": "Report error signals",
+                "parameters": [
+                    {
+                        "description": "Signals to record",
+                        "name": "signals",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReportSignalsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReportSignalsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing flag key or counts, future timestamp, or too many signals",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tenants": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List every tenant whose plan is overridden, ordered by tenant ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tenants"
+                ],
+                "summary": "List tenant plan overrides",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TenantPlan"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tenants/{tenant}/entitlements": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List every feature the tenant's plan entitles it to, whether or not the feature is enabled. The tenant's plan override is used when it has one; otherwise the plan query parameter is required.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tenants"
+                ],
+                "summary": "List a tenant's entitlements",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "tenant",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Plan of tenants without an override",
+                        "name": "plan",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Entitlements"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing or unknown plan",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tenants/{tenant}/plan": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tenants"
+                ],
+                "summary": "Get a tenant's plan override",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "tenant",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TenantPlan"
+                        }
+                    },
+                    "404": {
+                        "description": "The tenant has no override",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Put a tenant on a plan regardless of the plan its evaluation contexts carry. The override applies to evaluations whose context names the tenant.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tenants"
+                ],
+                "summary": "Override a tenant's plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "tenant",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Plan override",
+                        "name": "plan",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SetTenantPlanRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TenantPlan"
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown plan",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a tenant's plan override, so the plan in its evaluation contexts applies again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tenants"
+                ],
+                "summary": "Remove a tenant's plan override",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "tenant",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MessageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "The tenant has no override",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/debug/status": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get build info, uptime, storage backend, flag and dependency counts, readiness and background worker status. Only served when authentication is on, since it requires the admin role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Get server diagnostics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.DebugStatusResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Report that the process is up. Does not check dependencies; use /readyz for that.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ofrep/v1/evaluate/flags": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Resolve every flag for an evaluation context (OFREP bulk evaluation). Send the returned ETag as If-None-Match to get 304 when nothing changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ofrep"
+                ],
+                "summary": "Evaluate all flags",
+                "parameters": [
+                    {
+                        "description": "Evaluation context",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.OFREPRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a previous response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.OFREPBulkResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/evaluation.Result"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/ofrep/v1/evaluate/flags/{key}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Resolve a flag by key for an evaluation context (OFREP single flag evaluation)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ofrep"
+                ],
+                "summary": "Evaluate a flag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Flag key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Evaluation context",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.OFREPRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/evaluation.Result"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/evaluation.Result"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/evaluation.Result"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Ping MongoDB and check that required indexes exist. Fails with 503 while the server is shutting down.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/health.Readiness"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/health.Readiness"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "evaluation.Context": {
+            "type": "object",
+            "additionalProperties": true
+        },
+        "evaluation.ErrorCode": {
+            "type": "string",
+            "enum": [
+                "FLAG_NOT_FOUND",
+                "TYPE_MISMATCH",
+                "TARGETING_KEY_MISSING",
+                "INVALID_CONTEXT",
+                "PARSE_ERROR",
+                "GENERAL"
+            ],
+            "x-enum-varnames": [
+                "ErrorFlagNotFound",
+                "ErrorTypeMismatch",
+                "ErrorTargetingKeyMissing",
+                "ErrorInvalidContext",
+                "ErrorParse",
+                "ErrorGeneral"
+            ]
+        },
+        "evaluation.Reason": {
+            "type": "string",
+            "enum": [
+                "STATIC",
+                "TARGETING_MATCH",
+                "SPLIT",
+                "DISABLED",
+                "DEFAULT",
+                "OVERRIDE",
+                "ERROR"
+            ],
+            "x-enum-varnames": [
+                "ReasonStatic",
+                "ReasonTargetingMatch",
+                "ReasonSplit",
+                "ReasonDisabled",
+                "ReasonDefault",
+                "ReasonOverride",
+                "ReasonError"
+            ]
+        },
+        "evaluation.Result": {
+            "type": "object",
+            "properties": {
+                "errorCode": {
+                    "$ref": "#/definitions/evaluation.ErrorCode"
+                },
+                "errorDetails": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "reason": {
+                    "$ref": "#/definitions/evaluation.Reason"
+                },
+                "value": {},
+                "variant": {
+                    "type": "string"
+                }
+            }
+        },
+        "gitops.Mode": {
+            "type": "string",
+            "enum": [
+                "reconcile",
+                "detect"
+            ],
+            "x-enum-varnames": [
+                "ModeReconcile",
+                "ModeDetect"
+            ]
+        },
+        "gitops.Status": {
+            "type": "object",
+            "properties": {
+                "dir": {
+                    "type": "string"
+                },
+                "drift": {
+                    "description": "Drift is the plan found by the last sync, before anything was applied.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/manifest.Plan"
+                        }
+                    ]
+                },
+                "files": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "in_sync": {
+                    "description": "InSync is true when the server matched the manifests after the last\nsync.",
+                    "type": "boolean"
+                },
+                "last_applied": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_sync": {
+                    "type": "string"
+                },
+                "managed": {
+                    "description": "Managed lists the flag keys of the features the manifests declare.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "mode": {
+                    "$ref": "#/definitions/gitops.Mode"
+                },
+                "prune": {
+                    "type": "boolean"
+                },
+                "revision": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.AddDependencyRequest": {
+            "type": "object",
+            "properties": {
+                "child_id": {
+                    "type": "string"
+                },
+                "child_key": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "parent_key": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.AddOverridesRequest": {
+            "type": "object",
+            "required": [
+                "overrides"
+            ],
+            "properties": {
+                "overrides": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/models.Override"
+                    }
+                }
+            }
+        },
+        "handlers.CreateFeatureRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "type"
+            ],
+            "properties": {
+                "attributes": {
+                    "description": "Attributes holds project-defined string, number and boolean values,\nchecked against the configured AttributeSchema.",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "default_variant": {
+                    "description": "DefaultVariant is served when the feature is on and no weights are\nset. OffVariant is served when it is off; without one, callers fall\nback to their own default.",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "is_enabled": {
+                    "type": "boolean"
+                },
+                "key": {
+                    "description": "Key defaults to one derived from Name.",
+                    "type": "string",
+                    "example": "one-click-checkout"
+                },
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Link"
+                    }
+                },
+                "maintainer": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "off_variant": {
+                    "type": "string"
+                },
+                "owner": {
+                    "description": "Owner is the team that owns the feature; Maintainer is how to reach\nthe person looking after it, such as an email address.",
+                    "type": "string"
+                },
+                "protected": {
+                    "type": "boolean"
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TargetingRule"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "enum": [
+                        "basic",
+                        "premium",
+                        "enterprise"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.FeatureType"
+                        }
+                    ]
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Variant"
+                    }
+                }
+            }
+        },
+        "handlers.CreateSegmentRequest": {
+            "type": "object",
+            "required": [
+                "key"
+            ],
+            "properties": {
+                "attribute": {
+                    "description": "Attribute is the context attribute Included and Excluded list values\nof, the targeting key by default.",
+                    "type": "string",
+                    "example": "userId"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "excluded": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "included": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "key": {
+                    "type": "string",
+                    "example": "internal-employees"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Internal employees"
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TargetingRule"
+                    }
+                }
+            }
+        },
+        "handlers.DebugStatusResponse": {
+            "type": "object",
+            "properties": {
+                "backend": {
+                    "type": "string",
+                    "example": "mongodb"
+                },
+                "build": {
+                    "$ref": "#/definitions/health.BuildInfo"
+                },
+                "dependencies": {
+                    "type": "integer"
+                },
+                "features": {
+                    "type": "integer"
+                },
+                "readiness": {
+                    "$ref": "#/definitions/health.Readiness"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "uptime": {
+                    "type": "string",
+                    "example": "26h3m12s"
+                },
+                "workers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/health.WorkerStatus"
+                    }
+                }
+            }
+        },
+        "handlers.FeatureDependenciesResponse": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "parents": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "handlers.MessageResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "feature enabled successfully"
+                }
+            }
+        },
+        "handlers.OFREPBulkResponse": {
+            "type": "object",
+            "properties": {
+                "flags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/evaluation.Result"
+                    }
+                }
+            }
+        },
+        "handlers.OFREPRequest": {
+            "type": "object",
+            "properties": {
+                "context": {
+                    "$ref": "#/definitions/evaluation.Context"
+                }
+            }
+        },
+        "handlers.RemoveOverridesRequest": {
+            "type": "object",
+            "required": [
+                "overrides"
+            ],
+            "properties": {
+                "overrides": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/models.OverrideKey"
+                    }
+                }
+            }
+        },
+        "handlers.ReportSignalsRequest": {
+            "type": "object",
+            "required": [
+                "signals"
+            ],
+            "properties": {
+                "signals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Signal"
+                    }
+                }
+            }
+        },
+        "handlers.ReportSignalsResponse": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "handlers.ReviewChangeRequestRequest": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.SegmentRequest": {
+            "type": "object",
+            "properties": {
+                "attribute": {
+                    "description": "Attribute is the context attribute Included and Excluded list values\nof, the targeting key by default.",
+                    "type": "string",
+                    "example": "userId"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "excluded": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "included": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Internal employees"
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TargetingRule"
+                    }
+                }
+            }
+        },
+        "handlers.SetProtectionRequest": {
+            "type": "object",
+            "properties": {
+                "protected": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.SetRolloutRequest": {
+            "type": "object",
+            "required": [
+                "steps"
+            ],
+            "properties": {
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RolloutStep"
+                    }
+                }
+            }
+        },
+        "handlers.SetTenantPlanRequest": {
+            "type": "object",
+            "required": [
+                "plan"
+            ],
+            "properties": {
+                "plan": {
+                    "type": "string",
+                    "example": "enterprise"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "enterprise trial until 2026-12-31"
+                }
+            }
+        },
+        "handlers.TrackConversionsRequest": {
+            "type": "object",
+            "required": [
+                "conversions"
+            ],
+            "properties": {
+                "conversions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Conversion"
+                    }
+                }
+            }
+        },
+        "handlers.TrackConversionsResponse": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "health.BuildInfo": {
+            "type": "object",
+            "properties": {
+                "go_version": {
+                    "type": "string"
+                },
+                "modified": {
+                    "type": "boolean"
+                },
+                "revision": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "health.CheckResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "health.Readiness": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/health.CheckResult"
+                    }
+                },
+                "ready": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "health.WorkerStatus": {
+            "type": "object",
+            "properties": {
+                "detail": {},
+                "name": {
+                    "type": "string"
+                },
+                "running": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "manifest.Cascade": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "parent": {
+                    "type": "string"
+                }
+            }
+        },
+        "manifest.Dependency": {
+            "type": "object",
+            "properties": {
+                "child": {
+                    "type": "string"
+                },
+                "parent": {
+                    "type": "string"
+                }
+            }
+        },
+        "manifest.Feature": {
+            "type": "object",
+            "properties": {
+                "default_variant": {
+                    "description": "DefaultVariant is served when the feature is on and no weights are\nset. OffVariant is served when it is off; without one, callers fall\nback to their own default.",
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "key": {
+                    "description": "Key is set on the feature when it is created and defaults to one\nderived from Name. Keys cannot be changed afterwards.",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "off_variant": {
+                    "type": "string"
+                },
+                "protected": {
+                    "type": "boolean"
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TargetingRule"
+                    }
+                },
+                "type": {
+                    "$ref": "#/definitions/models.FeatureType"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Variant"
+                    }
+                }
+            }
+        },
+        "manifest.FeatureChange": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/manifest.FieldChange"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "manifest.FieldChange": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "from": {},
+                "to": {}
+            }
+        },
+        "manifest.Manifest": {
+            "type": "object",
+            "properties": {
+                "dependencies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/manifest.Dependency"
+                    }
+                },
+                "features": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/manifest.Feature"
+                    }
+                }
+            }
+        },
+        "manifest.Plan": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "type": "boolean"
+                },
+                "cascades": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/manifest.Cascade"
+                    }
+                },
+                "creates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/manifest.FeatureChange"
+                    }
+                },
+                "deletes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/manifest.FeatureChange"
+                    }
+                },
+                "dependency_adds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/manifest.Dependency"
+                    }
+                },
+                "dependency_removes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/manifest.Dependency"
+                    }
+                },
+                "updates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/manifest.FeatureChange"
+                    }
+                }
+            }
+        },
+        "models.AuditAction": {
+            "type": "string",
+            "enum": [
+                "kill_switch",
+                "rollout_step",
+                "rollout_abort"
+            ],
+            "x-enum-varnames": [
+                "AuditActionKillSwitch",
+                "AuditActionRolloutStep",
+                "AuditActionRolloutAbort"
+            ]
+        },
+        "models.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "enum": [
+                        "kill_switch",
+                        "rollout_step",
+                        "rollout_abort"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AuditAction"
+                        }
+                    ]
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "change_request_id": {
+                    "description": "ChangeRequestID is set when the feature was protected, so a change\nrequest was created instead of disabling it.",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "disabled": {
+                    "description": "Disabled lists the keys of the features disabled, the feature itself\nfirst.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "error": {
+                    "description": "Error is set when the action failed.",
+                    "type": "string"
+                },
+                "feature_id": {
+                    "type": "string"
+                },
+                "feature_key": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "pending": {
+                    "description": "Pending lists the keys of the features a change request would\ndisable when the feature was protected. They stay enabled until it is\napproved.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ChangeRequest": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/models.ChangeRequestAction"
+                },
+                "child_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "diff": {
+                    "$ref": "#/definitions/models.ChangeRequestDiff"
+                },
+                "feature_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "overrides": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Override"
+                    }
+                },
+                "requested_by": {
+                    "type": "string"
+                },
+                "review_comment": {
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by": {
+                    "type": "string"
+                },
+                "rollout": {
+                    "$ref": "#/definitions/models.RolloutPlan"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.ChangeRequestStatus"
+                },
+                "targeting": {
+                    "$ref": "#/definitions/models.Targeting"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ChangeRequestAction": {
+            "type": "string",
+            "enum": [
+                "enable",
+                "disable",
+                "add_child",
+                "set_targeting",
+                "add_overrides",
+                "remove_overrides",
+                "set_rollout"
+            ],
+            "x-enum-varnames": [
+                "ChangeRequestActionEnable",
+                "ChangeRequestActionDisable",
+                "ChangeRequestActionAddChild",
+                "ChangeRequestActionSetTargeting",
+                "ChangeRequestActionAddOverrides",
+                "ChangeRequestActionRemoveOverrides",
+                "ChangeRequestActionSetRollout"
+            ]
+        },
+        "models.ChangeRequestDiff": {
+            "type": "object",
+            "properties": {
+                "dependencies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DependencyChange"
+                    }
+                },
+                "features": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FeatureChange"
+                    }
+                }
+            }
+        },
+        "models.ChangeRequestStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "approved",
+                "rejected"
+            ],
+            "x-enum-varnames": [
+                "ChangeRequestStatusPending",
+                "ChangeRequestStatusApproved",
+                "ChangeRequestStatusRejected"
+            ]
+        },
+        "models.Conversion": {
+            "type": "object",
+            "properties": {
+                "event": {
+                    "type": "string",
+                    "example": "checkout_completed"
+                },
+                "targeting_key": {
+                    "type": "string",
+                    "example": "user-1"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number",
+                    "example": 49.9
+                }
+            }
+        },
+        "models.DependencyChange": {
+            "type": "object",
+            "properties": {
+                "child_id": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Difference": {
+            "type": "object",
+            "properties": {
+                "lower": {
+                    "type": "number"
+                },
+                "p_value": {
+                    "type": "number"
+                },
+                "upper": {
+                    "type": "number"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.Entitlements": {
+            "type": "object",
+            "properties": {
+                "features": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Feature"
+                    }
+                },
+                "override": {
+                    "description": "Override is set when Plan is the tenant's override rather than the\nplan given in the request.",
                     "type": "boolean"
                 },
-                "name": {
-                    "type": "string"
+                "plan": {
+                    "type": "string",
+                    "example": "premium"
+                },
+                "tenant_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ExperimentResults": {
+            "type": "object",
+            "properties": {
+                "confidence": {
+                    "description": "Confidence is the level of every interval, e.g. 0.95.",
+                    "type": "number"
+                },
+                "control": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string"
+                },
+                "flag_key": {
+                    "type": "string"
+                },
+                "since": {
+                    "type": "string"
+                },
+                "until": {
+                    "type": "string"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.VariantResult"
+                    }
+                }
+            }
+        },
+        "models.Feature": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "description": "Attributes holds project-defined string, number and boolean values,\nchecked against the configured AttributeSchema.",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "default_variant": {
+                    "description": "DefaultVariant is served when the feature is on and no weights are\nset. OffVariant is served when it is off; without one, callers fall\nback to their own default.",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "guard": {
+                    "description": "Guard is not part of manifests either.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.GuardRule"
+                        }
+                    ]
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_enabled": {
+                    "type": "boolean"
+                },
+                "key": {
+                    "description": "Key identifies the feature in application code and evaluation. It is\nunique and never changes once the feature is created. Features created\nbefore keys existed have none until they are migrated.",
+                    "type": "string"
+                },
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Link"
+                    }
+                },
+                "maintainer": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "off_variant": {
+                    "type": "string"
+                },
+                "overrides": {
+                    "description": "Overrides are not part of manifests, so applying one keeps them.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Override"
+                    }
+                },
+                "owner": {
+                    "description": "Owner is the team that owns the feature; Maintainer is how to reach\nthe person looking after it, such as an email address.",
+                    "type": "string"
+                },
+                "protected": {
+                    "type": "boolean"
+                },
+                "rollout": {
+                    "description": "Rollout is not part of manifests either.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RolloutPlan"
+                        }
+                    ]
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TargetingRule"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
                 "type": {
                     "$ref": "#/definitions/models.FeatureType"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Variant"
+                    }
+                },
+                "version": {
+                    "description": "Version counts changes that can change how the feature evaluates:\ncreation, enabling, disabling, type and targeting. Metadata edits leave\nit unchanged.",
+                    "type": "integer"
                 }
             }
         },
-        "handlers.ErrorResponse": {
+        "models.FeatureChange": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "error message"
-                }
+                "feature_id": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "from": {},
+                "name": {
+                    "type": "string"
+                },
+                "to": {}
             }
         },
-        "models.Feature": {
+        "models.FeatureDependency": {
             "type": "object",
             "properties": {
+                "child_id": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "is_enabled": {
-                    "type": "boolean"
-                },
-                "name": {
+                "parent_id": {
                     "type": "string"
                 },
-                "type": {
-                    "$ref": "#/definitions/models.FeatureType"
-                },
                 "updated_at": {
                     "type": "string"
                 }
@@ -324,6 +4318,461 @@ const docTemplate = `{
                 "FeatureTypePremium",
                 "FeatureTypeEnterprise"
             ]
+        },
+        "models.GuardMetric": {
+            "type": "string",
+            "enum": [
+                "error_rate",
+                "error_count"
+            ],
+            "x-enum-varnames": [
+                "GuardMetricErrorRate",
+                "GuardMetricErrorCount"
+            ]
+        },
+        "models.GuardRule": {
+            "type": "object",
+            "properties": {
+                "metric": {
+                    "enum": [
+                        "error_rate",
+                        "error_count"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.GuardMetric"
+                        }
+                    ]
+                },
+                "min_requests": {
+                    "description": "MinRequests is the fewest errors and successes the window must hold\nbefore the rule is checked, so a single early error cannot trip an\nerror rate.",
+                    "type": "integer",
+                    "example": 100
+                },
+                "threshold": {
+                    "type": "number",
+                    "example": 0.05
+                },
+                "tripped_at": {
+                    "description": "TrippedAt is when the rule last tripped. Signals reported before it\nare not counted again.",
+                    "type": "string"
+                },
+                "window": {
+                    "description": "Window is a duration such as \"5m\".",
+                    "type": "string",
+                    "example": "5m"
+                }
+            }
+        },
+        "models.Link": {
+            "type": "object",
+            "properties": {
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Metadata": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "description": "Attributes holds project-defined string, number and boolean values,\nchecked against the configured AttributeSchema.",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "description": {
+                    "type": "string"
+                },
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Link"
+                    }
+                },
+                "maintainer": {
+                    "type": "string"
+                },
+                "owner": {
+                    "description": "Owner is the team that owns the feature; Maintainer is how to reach\nthe person looking after it, such as an email address.",
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.Override": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "enum": [
+                        "allow",
+                        "deny"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.OverrideAction"
+                        }
+                    ]
+                },
+                "attribute": {
+                    "type": "string",
+                    "example": "tenantId"
+                },
+                "bypass_dependencies": {
+                    "description": "BypassDependencies serves an allow override even where a parent\nfeature is off. Without it the feature stays off with its parent.",
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "early access for the Q3 pilot"
+                },
+                "value": {
+                    "type": "string",
+                    "example": "acme"
+                },
+                "variant": {
+                    "description": "Variant is served by an allow override instead of the variant the\nfeature would serve when on.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.OverrideAction": {
+            "type": "string",
+            "enum": [
+                "allow",
+                "deny"
+            ],
+            "x-enum-varnames": [
+                "OverrideActionAllow",
+                "OverrideActionDeny"
+            ]
+        },
+        "models.OverrideKey": {
+            "type": "object",
+            "required": [
+                "attribute",
+                "value"
+            ],
+            "properties": {
+                "attribute": {
+                    "type": "string",
+                    "example": "tenantId"
+                },
+                "value": {
+                    "type": "string",
+                    "example": "acme"
+                }
+            }
+        },
+        "models.RolloutPlan": {
+            "type": "object",
+            "properties": {
+                "paused_at": {
+                    "description": "PausedAt is set while the plan is paused. The time spent paused does\nnot count towards the hold.",
+                    "type": "string"
+                },
+                "status": {
+                    "enum": [
+                        "active",
+                        "paused",
+                        "completed",
+                        "aborted"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RolloutStatus"
+                        }
+                    ]
+                },
+                "step": {
+                    "description": "Step is the index of the current step.",
+                    "type": "integer"
+                },
+                "step_started_at": {
+                    "type": "string"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RolloutStep"
+                    }
+                }
+            }
+        },
+        "models.RolloutStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "paused",
+                "completed",
+                "aborted"
+            ],
+            "x-enum-varnames": [
+                "RolloutStatusActive",
+                "RolloutStatusPaused",
+                "RolloutStatusCompleted",
+                "RolloutStatusAborted"
+            ]
+        },
+        "models.RolloutStep": {
+            "type": "object",
+            "properties": {
+                "hold": {
+                    "description": "Hold is a duration such as \"1h\". The last step has none.",
+                    "type": "string",
+                    "example": "1h"
+                },
+                "percentage": {
+                    "type": "number",
+                    "example": 5
+                }
+            }
+        },
+        "models.RuleOperator": {
+            "type": "string",
+            "enum": [
+                "in",
+                "not_in",
+                "in_segment",
+                "not_in_segment"
+            ],
+            "x-enum-varnames": [
+                "RuleOperatorIn",
+                "RuleOperatorNotIn",
+                "RuleOperatorInSegment",
+                "RuleOperatorNotInSegment"
+            ]
+        },
+        "models.Segment": {
+            "type": "object",
+            "properties": {
+                "attribute": {
+                    "description": "Attribute is the context attribute Included and Excluded list values\nof. It defaults to the targeting key.",
+                    "type": "string",
+                    "example": "userId"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "excluded": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "included": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "key": {
+                    "description": "Key identifies the segment in targeting rules. It is unique and never\nchanges once the segment is created.",
+                    "type": "string",
+                    "example": "internal-employees"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Internal employees"
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TargetingRule"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version counts changes to the segment, starting at 1.",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Signal": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "flag_key": {
+                    "type": "string",
+                    "example": "new-checkout"
+                },
+                "successes": {
+                    "type": "integer",
+                    "example": 97
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Targeting": {
+            "type": "object",
+            "properties": {
+                "default_variant": {
+                    "description": "DefaultVariant is served when the feature is on and no weights are\nset. OffVariant is served when it is off; without one, callers fall\nback to their own default.",
+                    "type": "string"
+                },
+                "off_variant": {
+                    "type": "string"
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TargetingRule"
+                    }
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Variant"
+                    }
+                }
+            }
+        },
+        "models.TargetingRule": {
+            "type": "object",
+            "properties": {
+                "attribute": {
+                    "type": "string"
+                },
+                "operator": {
+                    "$ref": "#/definitions/models.RuleOperator"
+                },
+                "values": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.TenantPlan": {
+            "type": "object",
+            "properties": {
+                "plan": {
+                    "type": "string",
+                    "example": "enterprise"
+                },
+                "reason": {
+                    "description": "Reason records why the tenant was moved, for whoever reviews it later.",
+                    "type": "string",
+                    "example": "enterprise trial until 2026-12-31"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Variant": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "value": {},
+                "weight": {
+                    "description": "Weight splits contexts between variants by targeting key when any\nvariant has a weight.",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.VariantResult": {
+            "type": "object",
+            "properties": {
+                "conversion_rate": {
+                    "type": "number"
+                },
+                "converted": {
+                    "type": "integer"
+                },
+                "difference": {
+                    "description": "Difference compares the variant with the control. It is nil for the\ncontrol itself and for variants without exposures.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Difference"
+                        }
+                    ]
+                },
+                "exposed": {
+                    "type": "integer"
+                },
+                "lower": {
+                    "type": "number"
+                },
+                "upper": {
+                    "type": "number"
+                },
+                "variant": {
+                    "type": "string"
+                }
+            }
+        },
+        "problem.Problem": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string",
+                    "example": "dependency would create a cycle"
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/api/features/dependencies"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 422
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Dependency cycle"
+                },
+                "type": {
+                    "description": "Type identifies the kind of problem. It is \"about:blank\" when the\nstatus code says everything there is to say.",
+                    "type": "string",
+                    "example": "urn:feature-flags:problem:dependency-cycle"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "description": "OIDC access token as \"Bearer \u003ctoken\u003e\". Required in the jwt auth mode.",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
@@ -0,0 +1,270 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testIssuer   = "https://sso.example.com"
+	testAudience = "feature-flags"
+)
+
+type testKeys struct {
+	rsaKey *rsa.PrivateKey
+	ecKey  *ecdsa.PrivateKey
+	jwks   []byte
+}
+
+func newTestKeys(t *testing.T) *testKeys {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	enc := func(b *big.Int) string { return base64.RawURLEncoding.EncodeToString(b.Bytes()) }
+	jwks, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa-1", "use": "sig", "alg": "RS256",
+				"n": enc(rsaKey.N), "e": enc(big.NewInt(int64(rsaKey.E)))},
+			{"kty": "EC", "kid": "ec-1", "use": "sig", "alg": "ES256", "crv": "P-256",
+				"x": enc(ecKey.X), "y": enc(ecKey.Y)},
+		},
+	})
+	require.NoError(t, err)
+
+	return &testKeys{rsaKey: rsaKey, ecKey: ecKey, jwks: jwks}
+}
+
+func (k *testKeys) writeFile(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, k.jwks, 0o600))
+	return path
+}
+
+func (k *testKeys) sign(t *testing.T, method jwt.SigningMethod, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+
+	var key interface{} = k.rsaKey
+	if method == jwt.SigningMethodES256 {
+		key = k.ecKey
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":    "user-1",
+		"email":  "user-1@example.com",
+		"iss":    testIssuer,
+		"aud":    testAudience,
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"groups": []string{"ff-admins", "unrelated"},
+	}
+}
+
+func newTestValidator(t *testing.T, keys *testKeys) *Validator {
+	ks, err := NewKeySet(context.Background(), keys.writeFile(t))
+	require.NoError(t, err)
+
+	return NewValidator(ks, ValidatorConfig{
+		Issuer:     testIssuer,
+		Audience:   testAudience,
+		RolesClaim: "groups",
+		RoleMap:    map[string]Role{"ff-admins": RoleAdmin, "ff-devs": RoleEditor},
+	})
+}
+
+func TestValidator_Validate(t *testing.T) {
+	keys := newTestKeys(t)
+	v := newTestValidator(t, keys)
+	ctx := context.Background()
+
+	t.Run("rsa token", func(t *testing.T) {
+		p, err := v.Validate(ctx, keys.sign(t, jwt.SigningMethodRS256, "rsa-1", validClaims()))
+		require.NoError(t, err)
+		assert.Equal(t, "user-1", p.Subject)
+		assert.Equal(t, "user-1@example.com", p.Email)
+		assert.Equal(t, []Role{RoleAdmin}, p.Roles)
+		assert.True(t, p.HasRole(RoleViewer))
+	})
+
+	t.Run("ec token", func(t *testing.T) {
+		_, err := v.Validate(ctx, keys.sign(t, jwt.SigningMethodES256, "ec-1", validClaims()))
+		require.NoError(t, err)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		claims := validClaims()
+		claims["exp"] = time.Now().Add(-time.Hour).Unix()
+		_, err := v.Validate(ctx, keys.sign(t, jwt.SigningMethodRS256, "rsa-1", claims))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("wrong audience", func(t *testing.T) {
+		claims := validClaims()
+		claims["aud"] = "someone-else"
+		_, err := v.Validate(ctx, keys.sign(t, jwt.SigningMethodRS256, "rsa-1", claims))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, err := v.Validate(ctx, keys.sign(t, jwt.SigningMethodRS256, "rotated", validClaims()))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("signed by another key", func(t *testing.T) {
+		other := newTestKeys(t)
+		_, err := v.Validate(ctx, other.sign(t, jwt.SigningMethodRS256, "rsa-1", validClaims()))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("hmac rejected", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims())
+		signed, err := token.SignedString([]byte("secret"))
+		require.NoError(t, err)
+		_, err = v.Validate(ctx, signed)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
+
+func TestKeySet_URL(t *testing.T) {
+	keys := newTestKeys(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(keys.jwks)
+	}))
+	defer srv.Close()
+
+	ks, err := NewKeySet(context.Background(), srv.URL)
+	require.NoError(t, err)
+
+	key, err := ks.Key(context.Background(), "rsa-1")
+	require.NoError(t, err)
+	assert.Equal(t, &keys.rsaKey.PublicKey, key)
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	keys := newTestKeys(t)
+	v := newTestValidator(t, keys)
+
+	r := gin.New()
+	r.Use(Middleware(v))
+	r.GET("/read", RequireRole(RoleViewer), func(c *gin.Context) {
+		c.JSON(http.StatusOK, GetPrincipal(c))
+	})
+	r.POST("/write", RequireRole(RoleEditor), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	do := func(method, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodGet, "/read", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = do(http.MethodGet, "/read", "not-a-jwt")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	admin := keys.sign(t, jwt.SigningMethodRS256, "rsa-1", validClaims())
+	w = do(http.MethodGet, "/read", admin)
+	require.Equal(t, http.StatusOK, w.Code)
+	var p Principal
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+	assert.Equal(t, "user-1", p.Subject)
+
+	w = do(http.MethodPost, "/write", admin)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	claims := validClaims()
+	claims["groups"] = []string{"unrelated"}
+	noRoles := keys.sign(t, jwt.SigningMethodRS256, "rsa-1", claims)
+	w = do(http.MethodPost, "/write", noRoles)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	keys := newTestKeys(t)
+	v := newTestValidator(t, keys)
+	interceptor := UnaryServerInterceptor(v, map[string]Role{"/ff/Read": RoleViewer})
+
+	call := func(method, token string) (*Principal, error) {
+		ctx := context.Background()
+		if token != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+token))
+		}
+		resp, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, _ interface{}) (interface{}, error) {
+			return PrincipalFromContext(ctx), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return resp.(*Principal), nil
+	}
+
+	_, err := call("/ff/Read", "")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	admin := keys.sign(t, jwt.SigningMethodRS256, "rsa-1", validClaims())
+	p, err := call("/ff/Read", admin)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", p.Subject)
+
+	// Methods without a mapping require admin.
+	claims := validClaims()
+	claims["groups"] = []string{"ff-devs"}
+	editor := keys.sign(t, jwt.SigningMethodRS256, "rsa-1", claims)
+	_, err = call("/ff/Read", editor)
+	require.NoError(t, err)
+	_, err = call("/ff/Unmapped", editor)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Without a validator calls pass through unauthenticated.
+	open := UnaryServerInterceptor(nil, nil)
+	resp, err := open(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/ff/Unmapped"}, func(ctx context.Context, _ interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+}
+
+func TestParseRoleMap(t *testing.T) {
+	roles, err := ParseRoleMap("ff-admins=admin, ff-devs = editor")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Role{"ff-admins": RoleAdmin, "ff-devs": RoleEditor}, roles)
+
+	_, err = ParseRoleMap("ff-admins=root")
+	assert.Error(t, err)
+}
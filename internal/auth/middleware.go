@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+
+	"feature-flags/internal/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Middleware authenticates requests carrying an "Authorization: Bearer"
+// token and attaches the resulting principal to both the Gin context and the
+// request context. Requests without a valid token are rejected with 401.
+func Middleware(v *Validator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		scheme, token, ok := strings.Cut(header, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+			c.Header("WWW-Authenticate", `Bearer`)
+			problem.Abort(c, problem.New(http.StatusUnauthorized, "missing bearer token"))
+			return
+		}
+
+		principal, err := v.Validate(c.Request.Context(), strings.TrimSpace(token))
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			problem.Abort(c, problem.New(http.StatusUnauthorized, err.Error()))
+			return
+		}
+
+		c.Set(ginPrincipalKey, principal)
+		c.Request = c.Request.WithContext(WithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+// RequireRole rejects authenticated requests whose principal lacks role with
+// 403. It is a no-op when no principal is attached, so routes stay open when
+// authentication is disabled.
+func RequireRole(role Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := GetPrincipal(c)
+		if principal != nil && !principal.HasRole(role) {
+			problem.Abort(c, problem.New(http.StatusForbidden, "requires role "+string(role)))
+			return
+		}
+		c.Next()
+	}
+}
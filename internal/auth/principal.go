@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Role string
+
+const (
+	RoleViewer Role = "viewer"
+	RoleEditor Role = "editor"
+	RoleAdmin  Role = "admin"
+)
+
+// roleRank orders roles so that a higher role satisfies any lower one.
+var roleRank = map[Role]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleAdmin:  3,
+}
+
+// Valid reports whether r is one of the known roles.
+func (r Role) Valid() bool {
+	_, ok := roleRank[r]
+	return ok
+}
+
+// Principal is the authenticated caller extracted from a bearer token.
+type Principal struct {
+	Subject string `json:"subject"`
+	Email   string `json:"email,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Issuer  string `json:"issuer,omitempty"`
+	Roles   []Role `json:"roles"`
+}
+
+// HasRole reports whether the principal holds role or any role above it.
+func (p *Principal) HasRole(role Role) bool {
+	if p == nil {
+		return false
+	}
+	for _, r := range p.Roles {
+		if roleRank[r] >= roleRank[role] {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+const ginPrincipalKey = "auth.principal"
+
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal stored by the middleware, or nil
+// when the request was not authenticated.
+func PrincipalFromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
+
+// GetPrincipal returns the principal attached to a Gin request, or nil.
+func GetPrincipal(c *gin.Context) *Principal {
+	if v, ok := c.Get(ginPrincipalKey); ok {
+		if p, ok := v.(*Principal); ok {
+			return p
+		}
+	}
+	return PrincipalFromContext(c.Request.Context())
+}
+
+// ParseRoleMap parses a comma-separated list of claim=role pairs, e.g.
+// "ff-admins=admin,ff-devs=editor".
+func ParseRoleMap(s string) (map[string]Role, error) {
+	roles := make(map[string]Role)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		claim, role, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid role mapping %q", pair)
+		}
+		r := Role(strings.TrimSpace(role))
+		if !r.Valid() {
+			return nil, fmt.Errorf("unknown role %q", role)
+		}
+		roles[strings.TrimSpace(claim)] = r
+	}
+	return roles, nil
+}
@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+type ValidatorConfig struct {
+	// Issuer and Audience are checked against the iss and aud claims when set.
+	Issuer   string
+	Audience string
+	// RolesClaim names the claim carrying group or role values. Defaults to "roles".
+	RolesClaim string
+	// RoleMap maps claim values to roles. When empty, claim values that
+	// already name a role are used as-is.
+	RoleMap map[string]Role
+	// Leeway tolerates clock skew when checking exp and nbf.
+	Leeway time.Duration
+}
+
+// Validator verifies bearer tokens against a key set and maps their claims
+// to a Principal.
+type Validator struct {
+	keys   *KeySet
+	config ValidatorConfig
+	parser *jwt.Parser
+}
+
+func NewValidator(keys *KeySet, config ValidatorConfig) *Validator {
+	if config.RolesClaim == "" {
+		config.RolesClaim = "roles"
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(config.Leeway),
+	}
+	if config.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		opts = append(opts, jwt.WithAudience(config.Audience))
+	}
+
+	return &Validator{
+		keys:   keys,
+		config: config,
+		parser: jwt.NewParser(opts...),
+	}
+}
+
+// Validate verifies the token signature and registered claims and returns
+// the principal it describes.
+func (v *Validator) Validate(ctx context.Context, tokenString string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+	_, err := v.parser.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return v.keys.Key(ctx, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	subject, _ := claims.GetSubject()
+	if subject == "" {
+		return nil, fmt.Errorf("%w: missing sub claim", ErrInvalidToken)
+	}
+	issuer, _ := claims.GetIssuer()
+
+	principal := &Principal{
+		Subject: subject,
+		Issuer:  issuer,
+		Roles:   v.mapRoles(claims[v.config.RolesClaim]),
+	}
+	principal.Email, _ = claims["email"].(string)
+	principal.Name, _ = claims["name"].(string)
+	return principal, nil
+}
+
+func (v *Validator) mapRoles(claim interface{}) []Role {
+	var values []string
+	switch c := claim.(type) {
+	case string:
+		values = []string{c}
+	case []interface{}:
+		for _, item := range c {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	seen := make(map[Role]bool)
+	roles := make([]Role, 0, len(values))
+	for _, value := range values {
+		role, ok := v.config.RoleMap[value]
+		if !ok && len(v.config.RoleMap) == 0 {
+			role = Role(value)
+			_, ok = roleRank[role]
+		}
+		if ok && !seen[role] {
+			seen[role] = true
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
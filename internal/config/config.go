@@ -0,0 +1,466 @@
+// Package config loads the server configuration. Settings come from, in
+// increasing order of precedence: built-in defaults, a YAML file, environment
+// variables and command-line flags.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"feature-flags/internal/auth"
+	"feature-flags/internal/gitops"
+	"feature-flags/internal/logging"
+	"feature-flags/internal/models"
+	"feature-flags/internal/tracing"
+)
+
+// Duration is a time.Duration written as a string such as "30s" in YAML and
+// when the configuration is printed.
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Backend selects where features are stored.
+type Backend string
+
+const BackendMongoDB Backend = "mongodb"
+
+// AuthMode selects how API callers are authenticated.
+type AuthMode string
+
+const (
+	// AuthModeNone leaves every route open.
+	AuthModeNone AuthMode = "none"
+	// AuthModeJWT requires an OIDC bearer token verified against Auth.JWKS.
+	AuthModeJWT AuthMode = "jwt"
+)
+
+type Config struct {
+	HTTP    HTTPConfig    `yaml:"http" json:"http"`
+	GRPC    GRPCConfig    `yaml:"grpc" json:"grpc"`
+	TLS     TLSConfig     `yaml:"tls" json:"tls"`
+	CORS    CORSConfig    `yaml:"cors" json:"cors"`
+	Storage StorageConfig `yaml:"storage" json:"storage"`
+	Auth    AuthConfig    `yaml:"auth" json:"auth"`
+	GitOps  GitOpsConfig  `yaml:"gitops" json:"gitops"`
+	Logging LoggingConfig `yaml:"logging" json:"logging"`
+	Tracing TracingConfig `yaml:"tracing" json:"tracing"`
+	Events  EventsConfig  `yaml:"events" json:"events"`
+	// KillSwitch disables features whose guard rules trip.
+	KillSwitch KillSwitchConfig `yaml:"kill_switch" json:"kill_switch"`
+	// Rollout advances progressive rollout plans.
+	Rollout RolloutConfig `yaml:"rollout" json:"rollout"`
+	// Metadata is only read from the config file.
+	Metadata MetadataConfig `yaml:"metadata" json:"metadata"`
+	// Entitlements is only read from the config file.
+	Entitlements EntitlementsConfig `yaml:"entitlements" json:"entitlements"`
+}
+
+type HTTPConfig struct {
+	Addr         string   `yaml:"addr" json:"addr"`
+	ReadTimeout  Duration `yaml:"read_timeout" json:"read_timeout"`
+	WriteTimeout Duration `yaml:"write_timeout" json:"write_timeout"`
+	IdleTimeout  Duration `yaml:"idle_timeout" json:"idle_timeout"`
+	// ShutdownTimeout is how long in-flight requests get to finish.
+	ShutdownTimeout Duration `yaml:"shutdown_timeout" json:"shutdown_timeout"`
+}
+
+type GRPCConfig struct {
+	Addr string `yaml:"addr" json:"addr"`
+	// WatchInterval is how often WatchFlags streams re-evaluate.
+	WatchInterval Duration `yaml:"watch_interval" json:"watch_interval"`
+}
+
+// TLSConfig enables TLS on both the HTTP and gRPC listeners when set.
+type TLSConfig struct {
+	CertFile string `yaml:"cert_file" json:"cert_file"`
+	KeyFile  string `yaml:"key_file" json:"key_file"`
+}
+
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != ""
+}
+
+// CORSConfig allows browser clients on other origins. CORS headers are only
+// sent when AllowedOrigins is set.
+type CORSConfig struct {
+	AllowedOrigins   []string `yaml:"allowed_origins" json:"allowed_origins"`
+	AllowedMethods   []string `yaml:"allowed_methods" json:"allowed_methods"`
+	AllowedHeaders   []string `yaml:"allowed_headers" json:"allowed_headers"`
+	AllowCredentials bool     `yaml:"allow_credentials" json:"allow_credentials"`
+	MaxAge           Duration `yaml:"max_age" json:"max_age"`
+}
+
+func (c CORSConfig) Enabled() bool {
+	return len(c.AllowedOrigins) > 0
+}
+
+type StorageConfig struct {
+	Backend Backend       `yaml:"backend" json:"backend"`
+	MongoDB MongoDBConfig `yaml:"mongodb" json:"mongodb"`
+}
+
+type MongoDBConfig struct {
+	URI            string   `yaml:"uri" json:"uri"`
+	Database       string   `yaml:"database" json:"database"`
+	ConnectTimeout Duration `yaml:"connect_timeout" json:"connect_timeout"`
+	// BootstrapTimeout bounds building indexes, running migrations and
+	// loading the dependency graph at startup.
+	BootstrapTimeout Duration `yaml:"bootstrap_timeout" json:"bootstrap_timeout"`
+}
+
+type AuthConfig struct {
+	// Mode defaults to jwt when JWKS is set and none otherwise, so setting
+	// JWKS alone enables authentication.
+	Mode AuthMode `yaml:"mode" json:"mode"`
+	// JWKS is a JWKS file path or URL.
+	JWKS       string               `yaml:"jwks" json:"jwks"`
+	Issuer     string               `yaml:"issuer" json:"issuer"`
+	Audience   string               `yaml:"audience" json:"audience"`
+	RolesClaim string               `yaml:"roles_claim" json:"roles_claim"`
+	RoleMap    map[string]auth.Role `yaml:"role_map" json:"role_map"`
+	// Leeway is the clock skew tolerated when checking token expiry.
+	Leeway Duration `yaml:"leeway" json:"leeway"`
+	// JWKSTimeout bounds loading the JWKS at startup.
+	JWKSTimeout Duration `yaml:"jwks_timeout" json:"jwks_timeout"`
+}
+
+// GitOpsConfig enables manifest sync when Dir is set.
+type GitOpsConfig struct {
+	Dir      string      `yaml:"dir" json:"dir"`
+	Mode     gitops.Mode `yaml:"mode" json:"mode"`
+	Interval Duration    `yaml:"interval" json:"interval"`
+	Prune    bool        `yaml:"prune" json:"prune"`
+}
+
+type LoggingConfig struct {
+	Level  string         `yaml:"level" json:"level"`
+	Format logging.Format `yaml:"format" json:"format"`
+}
+
+type TracingConfig struct {
+	Exporter    tracing.Exporter `yaml:"exporter" json:"exporter"`
+	File        string           `yaml:"file" json:"file"`
+	ServiceName string           `yaml:"service_name" json:"service_name"`
+}
+
+// EventsConfig controls exposure recording for experiments. Exposures are
+// queued in memory and written in batches; events arriving while the buffer
+// is full are dropped.
+type EventsConfig struct {
+	Exposures     bool     `yaml:"exposures" json:"exposures"`
+	BufferSize    int      `yaml:"buffer_size" json:"buffer_size"`
+	BatchSize     int      `yaml:"batch_size" json:"batch_size"`
+	FlushInterval Duration `yaml:"flush_interval" json:"flush_interval"`
+}
+
+// KillSwitchConfig controls the monitor that disables features whose guard
+// rules trip. Each trip is posted to WebhookURL when it is set.
+type KillSwitchConfig struct {
+	Enabled        bool     `yaml:"enabled" json:"enabled"`
+	Interval       Duration `yaml:"interval" json:"interval"`
+	WebhookURL     string   `yaml:"webhook_url" json:"webhook_url"`
+	WebhookTimeout Duration `yaml:"webhook_timeout" json:"webhook_timeout"`
+}
+
+// RolloutConfig controls the executor that advances rollout plans. Only
+// the instance holding the lease advances them; it must renew the lease
+// within LeaseTTL, so LeaseTTL must exceed Interval.
+type RolloutConfig struct {
+	Enabled  bool     `yaml:"enabled" json:"enabled"`
+	Interval Duration `yaml:"interval" json:"interval"`
+	LeaseTTL Duration `yaml:"lease_ttl" json:"lease_ttl"`
+}
+
+// MetadataConfig declares the custom attributes features may carry. With no
+// attributes declared, any string, number or boolean attribute is accepted.
+type MetadataConfig struct {
+	Attributes models.AttributeSchema `yaml:"attributes" json:"attributes"`
+}
+
+// EntitlementsConfig gates features by the plan of the tenant evaluating
+// them. Plans are listed from lowest to highest and must include every
+// feature type.
+type EntitlementsConfig struct {
+	Plans models.PlanHierarchy `yaml:"plans" json:"plans"`
+	// PlanAttribute and TenantAttribute are the evaluation context
+	// attributes holding the plan and the tenant ID.
+	PlanAttribute   string `yaml:"plan_attribute" json:"plan_attribute"`
+	TenantAttribute string `yaml:"tenant_attribute" json:"tenant_attribute"`
+}
+
+// Default returns the configuration used for settings that are not set
+// anywhere else. It has no database credentials.
+func Default() *Config {
+	return &Config{
+		HTTP: HTTPConfig{
+			Addr:            ":8080",
+			ReadTimeout:     Duration(15 * time.Second),
+			WriteTimeout:    Duration(30 * time.Second),
+			IdleTimeout:     Duration(2 * time.Minute),
+			ShutdownTimeout: Duration(5 * time.Second),
+		},
+		GRPC: GRPCConfig{
+			Addr: ":9090",
+		},
+		CORS: CORSConfig{
+			AllowedMethods: []string{"GET", "POST", "PUT", "DELETE"},
+			AllowedHeaders: []string{"Authorization", "Content-Type", "If-None-Match", logging.RequestIDHeader},
+			MaxAge:         Duration(12 * time.Hour),
+		},
+		Storage: StorageConfig{
+			Backend: BackendMongoDB,
+			MongoDB: MongoDBConfig{
+				URI:              "mongodb://localhost:27017",
+				Database:         "finbox",
+				ConnectTimeout:   Duration(10 * time.Second),
+				BootstrapTimeout: Duration(2 * time.Minute),
+			},
+		},
+		Auth: AuthConfig{
+			Leeway:      Duration(30 * time.Second),
+			JWKSTimeout: Duration(10 * time.Second),
+		},
+		GitOps: GitOpsConfig{
+			Mode:     gitops.ModeReconcile,
+			Interval: Duration(30 * time.Second),
+		},
+		Logging: LoggingConfig{
+			Level:  "info",
+			Format: logging.FormatJSON,
+		},
+		Tracing: TracingConfig{
+			Exporter:    tracing.ExporterNone,
+			ServiceName: "feature-flags",
+		},
+		Events: EventsConfig{
+			Exposures:     true,
+			BufferSize:    10000,
+			BatchSize:     500,
+			FlushInterval: Duration(time.Second),
+		},
+		KillSwitch: KillSwitchConfig{
+			Enabled:        true,
+			Interval:       Duration(15 * time.Second),
+			WebhookTimeout: Duration(5 * time.Second),
+		},
+		Rollout: RolloutConfig{
+			Enabled:  true,
+			Interval: Duration(30 * time.Second),
+			LeaseTTL: Duration(90 * time.Second),
+		},
+		Entitlements: EntitlementsConfig{
+			Plans:           models.DefaultPlanHierarchy(),
+			PlanAttribute:   "plan",
+			TenantAttribute: "tenantId",
+		},
+	}
+}
+
+// resolve fills in settings whose defaults depend on other settings.
+func (c *Config) resolve() {
+	if c.Auth.Mode == "" {
+		c.Auth.Mode = AuthModeNone
+		if c.Auth.JWKS != "" {
+			c.Auth.Mode = AuthModeJWT
+		}
+	}
+}
+
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.HTTP.Addr == "" {
+		fail("http.addr is required")
+	}
+	if c.GRPC.Addr == "" {
+		fail("grpc.addr is required")
+	}
+	if c.HTTP.ReadTimeout < 0 || c.HTTP.WriteTimeout < 0 || c.HTTP.IdleTimeout < 0 {
+		fail("http timeouts must not be negative")
+	}
+	if c.HTTP.ShutdownTimeout <= 0 {
+		fail("http.shutdown_timeout must be positive")
+	}
+	if c.GRPC.WatchInterval < 0 {
+		fail("grpc.watch_interval must not be negative")
+	}
+
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		fail("tls.cert_file and tls.key_file must be set together")
+	}
+	for _, file := range []string{c.TLS.CertFile, c.TLS.KeyFile} {
+		if file == "" {
+			continue
+		}
+		if _, err := os.Stat(file); err != nil {
+			fail("tls: %v", err)
+		}
+	}
+
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin != "*" && !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") {
+			fail("cors.allowed_origins: %q must be * or start with http:// or https://", origin)
+		}
+		if origin == "*" && c.CORS.AllowCredentials {
+			fail("cors.allow_credentials cannot be combined with the * origin")
+		}
+	}
+
+	switch c.Storage.Backend {
+	case BackendMongoDB:
+		uri := c.Storage.MongoDB.URI
+		if !strings.HasPrefix(uri, "mongodb://") && !strings.HasPrefix(uri, "mongodb+srv://") {
+			fail("storage.mongodb.uri must start with mongodb:// or mongodb+srv://")
+		}
+		if c.Storage.MongoDB.Database == "" {
+			fail("storage.mongodb.database is required")
+		}
+		if c.Storage.MongoDB.ConnectTimeout <= 0 {
+			fail("storage.mongodb.connect_timeout must be positive")
+		}
+		if c.Storage.MongoDB.BootstrapTimeout <= 0 {
+			fail("storage.mongodb.bootstrap_timeout must be positive")
+		}
+	default:
+		fail("storage.backend: unknown backend %q", c.Storage.Backend)
+	}
+
+	switch c.Auth.Mode {
+	case AuthModeNone:
+	case AuthModeJWT:
+		if c.Auth.JWKS == "" {
+			fail("auth.jwks is required in jwt mode")
+		}
+		if c.Auth.JWKSTimeout <= 0 {
+			fail("auth.jwks_timeout must be positive")
+		}
+	default:
+		fail("auth.mode: unknown mode %q", c.Auth.Mode)
+	}
+	for claim, role := range c.Auth.RoleMap {
+		if !role.Valid() {
+			fail("auth.role_map: unknown role %q for %q", role, claim)
+		}
+	}
+
+	switch c.GitOps.Mode {
+	case gitops.ModeReconcile, gitops.ModeDetect:
+	default:
+		fail("gitops.mode: unknown mode %q", c.GitOps.Mode)
+	}
+	if c.GitOps.Interval <= 0 {
+		fail("gitops.interval must be positive")
+	}
+
+	if err := (logging.Config{Level: c.Logging.Level, Format: c.Logging.Format}).Validate(); err != nil {
+		fail("logging: %v", err)
+	}
+
+	if c.Events.BufferSize <= 0 || c.Events.BatchSize <= 0 {
+		fail("events.buffer_size and events.batch_size must be positive")
+	}
+	if c.Events.FlushInterval <= 0 {
+		fail("events.flush_interval must be positive")
+	}
+
+	if c.KillSwitch.Interval <= 0 || c.KillSwitch.WebhookTimeout <= 0 {
+		fail("kill_switch.interval and kill_switch.webhook_timeout must be positive")
+	}
+	if url := c.KillSwitch.WebhookURL; url != "" && !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		fail("kill_switch.webhook_url must start with http:// or https://")
+	}
+
+	if c.Rollout.Interval <= 0 {
+		fail("rollout.interval must be positive")
+	}
+	if c.Rollout.LeaseTTL <= c.Rollout.Interval {
+		fail("rollout.lease_ttl must be longer than rollout.interval")
+	}
+
+	if err := c.Metadata.Attributes.Validate(); err != nil {
+		fail("metadata.attributes: %v", err)
+	}
+	if err := c.Entitlements.Plans.Validate(); err != nil {
+		fail("entitlements.plans: %v", err)
+	}
+	if c.Entitlements.PlanAttribute == "" || c.Entitlements.TenantAttribute == "" {
+		fail("entitlements.plan_attribute and entitlements.tenant_attribute are required")
+	}
+
+	switch c.Tracing.Exporter {
+	case tracing.ExporterNone, tracing.ExporterStdout, tracing.ExporterOTLP:
+	case tracing.ExporterOTLPFile:
+		if c.Tracing.File == "" {
+			fail("tracing.file is required for the %s exporter", c.Tracing.Exporter)
+		}
+	default:
+		fail("tracing.exporter: unknown exporter %q", c.Tracing.Exporter)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Redacted returns a copy that is safe to print: the password in the MongoDB
+// URI is masked, and so is the path of the kill switch webhook URL, which
+// often holds a token.
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	redacted.Storage.MongoDB.URI = redactURI(c.Storage.MongoDB.URI)
+	redacted.KillSwitch.WebhookURL = redactPath(c.KillSwitch.WebhookURL)
+	return &redacted
+}
+
+// redactPath masks everything after the host of a URL.
+func redactPath(url string) string {
+	scheme, rest, ok := strings.Cut(url, "://")
+	if !ok {
+		return url
+	}
+	host, _, hasPath := strings.Cut(rest, "/")
+	if !hasPath {
+		return url
+	}
+	return scheme + "://" + host + "/xxxxx"
+}
+
+// redactURI masks the password in a connection string. It does not use
+// net/url because MongoDB URIs may list several comma-separated hosts.
+func redactURI(uri string) string {
+	scheme, rest, ok := strings.Cut(uri, "://")
+	if !ok {
+		return uri
+	}
+	authority, path, hasPath := strings.Cut(rest, "/")
+	at := strings.LastIndex(authority, "@")
+	if at < 0 {
+		return uri
+	}
+	user, _, hasPassword := strings.Cut(authority[:at], ":")
+	if !hasPassword {
+		return uri
+	}
+	redacted := scheme + "://" + user + ":xxxxx" + authority[at:]
+	if hasPath {
+		redacted += "/" + path
+	}
+	return redacted
+}
@@ -0,0 +1,248 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"feature-flags/internal/auth"
+	"feature-flags/internal/gitops"
+	"feature-flags/internal/logging"
+	"feature-flags/internal/tracing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// setting is a value that can be set by both an environment variable and a
+// flag. set parses the same string form for both.
+type setting struct {
+	flag  string
+	env   string
+	usage string
+	set   func(c *Config, value string) error
+	// isBool lets the flag be given without a value, e.g. -gitops-prune.
+	isBool bool
+}
+
+// rawValue holds a flag's string until it is applied after the file and
+// environment.
+type rawValue struct {
+	value  string
+	isBool bool
+}
+
+func (v *rawValue) String() string     { return v.value }
+func (v *rawValue) Set(s string) error { v.value = s; return nil }
+func (v *rawValue) IsBoolFlag() bool   { return v.isBool }
+
+func stringSetting(flag, env, usage string, field func(c *Config) *string) setting {
+	return setting{flag: flag, env: env, usage: usage, set: func(c *Config, value string) error {
+		*field(c) = value
+		return nil
+	}}
+}
+
+func durationSetting(flag, env, usage string, field func(c *Config) *Duration) setting {
+	return setting{flag: flag, env: env, usage: usage, set: func(c *Config, value string) error {
+		return field(c).UnmarshalText([]byte(value))
+	}}
+}
+
+func boolSetting(flag, env, usage string, field func(c *Config) *bool) setting {
+	return setting{flag: flag, env: env, usage: usage, isBool: true, set: func(c *Config, value string) error {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		*field(c) = parsed
+		return nil
+	}}
+}
+
+func intSetting(flag, env, usage string, field func(c *Config) *int) setting {
+	return setting{flag: flag, env: env, usage: usage, set: func(c *Config, value string) error {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		*field(c) = parsed
+		return nil
+	}}
+}
+
+// listSetting parses a comma-separated list.
+func listSetting(flag, env, usage string, field func(c *Config) *[]string) setting {
+	return setting{flag: flag, env: env, usage: usage, set: func(c *Config, value string) error {
+		var list []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		*field(c) = list
+		return nil
+	}}
+}
+
+var settings = []setting{
+	stringSetting("http-addr", "HTTP_ADDR", "HTTP listen address", func(c *Config) *string { return &c.HTTP.Addr }),
+	durationSetting("http-read-timeout", "HTTP_READ_TIMEOUT", "HTTP request read timeout", func(c *Config) *Duration { return &c.HTTP.ReadTimeout }),
+	durationSetting("http-write-timeout", "HTTP_WRITE_TIMEOUT", "HTTP response write timeout", func(c *Config) *Duration { return &c.HTTP.WriteTimeout }),
+	durationSetting("http-idle-timeout", "HTTP_IDLE_TIMEOUT", "HTTP keep-alive idle timeout", func(c *Config) *Duration { return &c.HTTP.IdleTimeout }),
+	durationSetting("shutdown-timeout", "SHUTDOWN_TIMEOUT", "time in-flight requests get to finish on shutdown", func(c *Config) *Duration { return &c.HTTP.ShutdownTimeout }),
+
+	stringSetting("grpc-addr", "GRPC_ADDR", "gRPC listen address", func(c *Config) *string { return &c.GRPC.Addr }),
+	durationSetting("grpc-watch-interval", "GRPC_WATCH_INTERVAL", "how often WatchFlags streams re-evaluate", func(c *Config) *Duration { return &c.GRPC.WatchInterval }),
+
+	stringSetting("tls-cert-file", "TLS_CERT_FILE", "TLS certificate file for the HTTP and gRPC listeners", func(c *Config) *string { return &c.TLS.CertFile }),
+	stringSetting("tls-key-file", "TLS_KEY_FILE", "TLS private key file", func(c *Config) *string { return &c.TLS.KeyFile }),
+
+	listSetting("cors-allowed-origins", "CORS_ALLOWED_ORIGINS", "comma-separated origins allowed to call the API; empty disables CORS", func(c *Config) *[]string { return &c.CORS.AllowedOrigins }),
+	listSetting("cors-allowed-methods", "CORS_ALLOWED_METHODS", "comma-separated methods allowed in CORS requests", func(c *Config) *[]string { return &c.CORS.AllowedMethods }),
+	listSetting("cors-allowed-headers", "CORS_ALLOWED_HEADERS", "comma-separated headers allowed in CORS requests", func(c *Config) *[]string { return &c.CORS.AllowedHeaders }),
+	boolSetting("cors-allow-credentials", "CORS_ALLOW_CREDENTIALS", "allow credentialed CORS requests", func(c *Config) *bool { return &c.CORS.AllowCredentials }),
+	durationSetting("cors-max-age", "CORS_MAX_AGE", "how long browsers may cache preflight responses", func(c *Config) *Duration { return &c.CORS.MaxAge }),
+
+	{flag: "storage-backend", env: "STORAGE_BACKEND", usage: "storage backend (mongodb)", set: func(c *Config, value string) error {
+		c.Storage.Backend = Backend(value)
+		return nil
+	}},
+	stringSetting("mongodb-uri", "MONGODB_URI", "MongoDB connection string", func(c *Config) *string { return &c.Storage.MongoDB.URI }),
+	stringSetting("mongodb-database", "MONGODB_DATABASE", "MongoDB database name", func(c *Config) *string { return &c.Storage.MongoDB.Database }),
+	durationSetting("mongodb-connect-timeout", "MONGODB_CONNECT_TIMEOUT", "MongoDB connect timeout", func(c *Config) *Duration { return &c.Storage.MongoDB.ConnectTimeout }),
+	durationSetting("mongodb-bootstrap-timeout", "MONGODB_BOOTSTRAP_TIMEOUT", "timeout for building indexes, migrating and loading the dependency graph at startup", func(c *Config) *Duration { return &c.Storage.MongoDB.BootstrapTimeout }),
+
+	{flag: "auth-mode", env: "AUTH_MODE", usage: "authentication mode: none or jwt (default jwt when a JWKS is set)", set: func(c *Config, value string) error {
+		c.Auth.Mode = AuthMode(value)
+		return nil
+	}},
+	stringSetting("auth-jwks", "AUTH_JWKS", "JWKS file path or URL", func(c *Config) *string { return &c.Auth.JWKS }),
+	durationSetting("auth-jwks-timeout", "AUTH_JWKS_TIMEOUT", "timeout for loading the JWKS at startup", func(c *Config) *Duration { return &c.Auth.JWKSTimeout }),
+	stringSetting("auth-issuer", "AUTH_ISSUER", "required token issuer", func(c *Config) *string { return &c.Auth.Issuer }),
+	stringSetting("auth-audience", "AUTH_AUDIENCE", "required token audience", func(c *Config) *string { return &c.Auth.Audience }),
+	stringSetting("auth-roles-claim", "AUTH_ROLES_CLAIM", "token claim holding roles or groups", func(c *Config) *string { return &c.Auth.RolesClaim }),
+	{flag: "auth-role-map", env: "AUTH_ROLE_MAP", usage: "comma-separated claim=role pairs, e.g. ff-admins=admin", set: func(c *Config, value string) error {
+		roles, err := auth.ParseRoleMap(value)
+		if err != nil {
+			return err
+		}
+		c.Auth.RoleMap = roles
+		return nil
+	}},
+	durationSetting("auth-leeway", "AUTH_LEEWAY", "clock skew tolerated when checking token expiry", func(c *Config) *Duration { return &c.Auth.Leeway }),
+
+	stringSetting("gitops-dir", "GITOPS_DIR", "manifest directory; empty disables GitOps sync", func(c *Config) *string { return &c.GitOps.Dir }),
+	{flag: "gitops-mode", env: "GITOPS_MODE", usage: "GitOps mode: reconcile or detect", set: func(c *Config, value string) error {
+		c.GitOps.Mode = gitops.Mode(value)
+		return nil
+	}},
+	durationSetting("gitops-interval", "GITOPS_INTERVAL", "how often the manifest directory is synced", func(c *Config) *Duration { return &c.GitOps.Interval }),
+	boolSetting("gitops-prune", "GITOPS_PRUNE", "delete features missing from the manifests", func(c *Config) *bool { return &c.GitOps.Prune }),
+
+	stringSetting("log-level", "LOG_LEVEL", "log level: debug, info, warn or error", func(c *Config) *string { return &c.Logging.Level }),
+	{flag: "log-format", env: "LOG_FORMAT", usage: "log format: json or text", set: func(c *Config, value string) error {
+		c.Logging.Format = logging.Format(value)
+		return nil
+	}},
+
+	{flag: "otel-traces-exporter", env: "OTEL_TRACES_EXPORTER", usage: "trace exporter: none, stdout, otlpfile or otlp", set: func(c *Config, value string) error {
+		c.Tracing.Exporter = tracing.Exporter(value)
+		return nil
+	}},
+	stringSetting("otel-traces-file", "OTEL_TRACES_FILE", "file the otlpfile exporter appends to", func(c *Config) *string { return &c.Tracing.File }),
+	stringSetting("otel-service-name", "OTEL_SERVICE_NAME", "service name on exported spans", func(c *Config) *string { return &c.Tracing.ServiceName }),
+
+	boolSetting("events-exposures", "EVENTS_EXPOSURES", "record an exposure event whenever a variant is served", func(c *Config) *bool { return &c.Events.Exposures }),
+	intSetting("events-buffer-size", "EVENTS_BUFFER_SIZE", "exposure events that may wait to be written before new ones are dropped", func(c *Config) *int { return &c.Events.BufferSize }),
+	intSetting("events-batch-size", "EVENTS_BATCH_SIZE", "most exposure events written at once", func(c *Config) *int { return &c.Events.BatchSize }),
+	durationSetting("events-flush-interval", "EVENTS_FLUSH_INTERVAL", "longest an exposure event waits to be written", func(c *Config) *Duration { return &c.Events.FlushInterval }),
+
+	boolSetting("kill-switch-enabled", "KILL_SWITCH_ENABLED", "disable features whose guard rules trip", func(c *Config) *bool { return &c.KillSwitch.Enabled }),
+	durationSetting("kill-switch-interval", "KILL_SWITCH_INTERVAL", "how often guard rules are checked", func(c *Config) *Duration { return &c.KillSwitch.Interval }),
+	stringSetting("kill-switch-webhook-url", "KILL_SWITCH_WEBHOOK_URL", "URL each kill switch trip is posted to; empty only logs trips", func(c *Config) *string { return &c.KillSwitch.WebhookURL }),
+	durationSetting("kill-switch-webhook-timeout", "KILL_SWITCH_WEBHOOK_TIMEOUT", "timeout of kill switch webhook calls", func(c *Config) *Duration { return &c.KillSwitch.WebhookTimeout }),
+	boolSetting("rollout-enabled", "ROLLOUT_ENABLED", "advance rollout plans on this instance when it holds the lease", func(c *Config) *bool { return &c.Rollout.Enabled }),
+	durationSetting("rollout-interval", "ROLLOUT_INTERVAL", "how often rollout plans are checked", func(c *Config) *Duration { return &c.Rollout.Interval }),
+	durationSetting("rollout-lease-ttl", "ROLLOUT_LEASE_TTL", "how long rollout leadership lasts without renewal", func(c *Config) *Duration { return &c.Rollout.LeaseTTL }),
+}
+
+// Load builds the configuration from defaults, the YAML file named by the
+// -config flag or CONFIG_FILE, the environment and args, in that order of
+// precedence, and validates it. Empty environment variables are ignored.
+// With -h it returns flag.ErrHelp after printing usage to output.
+func Load(args []string, getenv func(string) string, output io.Writer) (*Config, error) {
+	fs := flag.NewFlagSet("feature-flags", flag.ContinueOnError)
+	fs.SetOutput(output)
+	configFile := fs.String("config", "", "YAML configuration file (env CONFIG_FILE)")
+	flags := make(map[string]setting, len(settings))
+	for _, s := range settings {
+		fs.Var(&rawValue{isBool: s.isBool}, s.flag, fmt.Sprintf("%s (env %s)", s.usage, s.env))
+		flags[s.flag] = s
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	cfg := Default()
+
+	path := *configFile
+	if path == "" {
+		path = getenv("CONFIG_FILE")
+	}
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, s := range settings {
+		if value := getenv(s.env); value != "" {
+			if err := s.set(cfg, value); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", s.env, err)
+			}
+		}
+	}
+
+	var flagErr error
+	fs.Visit(func(f *flag.Flag) {
+		s, ok := flags[f.Name]
+		if !ok || flagErr != nil {
+			return
+		}
+		if err := s.set(cfg, f.Value.String()); err != nil {
+			flagErr = fmt.Errorf("invalid -%s: %w", s.flag, err)
+		}
+	})
+	if flagErr != nil {
+		return nil, flagErr
+	}
+
+	cfg.resolve()
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return cfg, nil
+}
+
+// loadFile overlays the settings in a YAML file. Unknown keys are rejected
+// so that typos are not silently ignored.
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return nil
+}
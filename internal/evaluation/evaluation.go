@@ -0,0 +1,537 @@
+// Package evaluation resolves features for an evaluation context, following
+// the OpenFeature model of values, variants, reasons and error codes.
+package evaluation
+
+import (
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sort"
+
+	"feature-flags/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Reason explains how a value was resolved.
+type Reason string
+
+const (
+	ReasonStatic         Reason = "STATIC"
+	ReasonTargetingMatch Reason = "TARGETING_MATCH"
+	ReasonSplit          Reason = "SPLIT"
+	ReasonDisabled       Reason = "DISABLED"
+	ReasonDefault        Reason = "DEFAULT"
+	// ReasonOverride is reported when an override decided the value. The
+	// override metadata entry says whether it allowed or denied.
+	ReasonOverride Reason = "OVERRIDE"
+	ReasonError    Reason = "ERROR"
+)
+
+type ErrorCode string
+
+const (
+	ErrorFlagNotFound        ErrorCode = "FLAG_NOT_FOUND"
+	ErrorTypeMismatch        ErrorCode = "TYPE_MISMATCH"
+	ErrorTargetingKeyMissing ErrorCode = "TARGETING_KEY_MISSING"
+	ErrorInvalidContext      ErrorCode = "INVALID_CONTEXT"
+	ErrorParse               ErrorCode = "PARSE_ERROR"
+	ErrorGeneral             ErrorCode = "GENERAL"
+)
+
+// TargetingKey is the context attribute that identifies the subject of an
+// evaluation. Weighted variants are assigned by it.
+const TargetingKey = "targetingKey"
+
+// Context holds the attributes rules are matched against.
+type Context map[string]interface{}
+
+// Entitlements gates features by the plan of the account evaluating them: a
+// feature whose type ranks above the plan in the context is off. Contexts
+// without a plan are not gated.
+type Entitlements struct {
+	Plans models.PlanHierarchy
+	// PlanAttribute is the context attribute holding the plan.
+	PlanAttribute string
+}
+
+// Result is the outcome of evaluating one flag. Value is nil when the flag
+// is off and has no off variant; callers then use their own default.
+type Result struct {
+	Key          string                 `json:"key"`
+	Value        interface{}            `json:"value,omitempty"`
+	Variant      string                 `json:"variant,omitempty"`
+	Reason       Reason                 `json:"reason"`
+	ErrorCode    ErrorCode              `json:"errorCode,omitempty"`
+	ErrorDetails string                 `json:"errorDetails,omitempty"`
+	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// On reports whether the result counts as on for the features that depend
+// on it: resolved without error or fallback, and not to false.
+func (r Result) On() bool {
+	switch r.Reason {
+	case ReasonDisabled, ReasonDefault, ReasonError:
+		return false
+	case ReasonOverride:
+		if r.Metadata["override"] == string(models.OverrideActionDeny) {
+			return false
+		}
+	}
+	if value, ok := r.Value.(bool); ok {
+		return value
+	}
+	return true
+}
+
+// Snapshot is an immutable view of the features and dependency edges that
+// evaluation reads. Flags are addressed by key (see models.Feature.FlagKey).
+type Snapshot struct {
+	byKey        map[string][]*models.Feature
+	byID         map[primitive.ObjectID]*models.Feature
+	parents      map[primitive.ObjectID][]primitive.ObjectID
+	overrides    map[primitive.ObjectID]*overrideIndex
+	segments     map[string]*segmentIndex
+	entitlements *Entitlements
+}
+
+// overrideIndex looks up the overrides of one feature by key.
+type overrideIndex struct {
+	attributes []string
+	byKey      map[models.OverrideKey]*models.Override
+}
+
+func newOverrideIndex(overrides []models.Override) *overrideIndex {
+	index := &overrideIndex{byKey: make(map[models.OverrideKey]*models.Override, len(overrides))}
+	for i := range overrides {
+		o := &overrides[i]
+		if !slices.Contains(index.attributes, o.Attribute) {
+			index.attributes = append(index.attributes, o.Attribute)
+		}
+		index.byKey[o.Key()] = o
+	}
+	return index
+}
+
+// match returns the override for the context, preferring a deny when an
+// allow matches too.
+func (idx *overrideIndex) match(ctx Context) *models.Override {
+	var allow *models.Override
+	for _, attribute := range idx.attributes {
+		value, ok := ctx[attribute]
+		if !ok {
+			continue
+		}
+		values := []interface{}{value}
+		if list, ok := value.([]interface{}); ok {
+			values = list
+		}
+		for _, v := range values {
+			o := idx.byKey[models.OverrideKey{Attribute: attribute, Value: fmt.Sprint(v)}]
+			switch {
+			case o == nil:
+			case o.Action == models.OverrideActionDeny:
+				return o
+			case allow == nil:
+				allow = o
+			}
+		}
+	}
+	return allow
+}
+
+// segmentIndex looks up the included and excluded values of a segment.
+type segmentIndex struct {
+	segment  *models.Segment
+	included map[string]bool
+	excluded map[string]bool
+}
+
+func newSegmentIndex(segment *models.Segment) *segmentIndex {
+	index := &segmentIndex{
+		segment:  segment,
+		included: make(map[string]bool, len(segment.Included)),
+		excluded: make(map[string]bool, len(segment.Excluded)),
+	}
+	for _, value := range segment.Included {
+		index.included[value] = true
+	}
+	for _, value := range segment.Excluded {
+		index.excluded[value] = true
+	}
+	return index
+}
+
+// contains reports whether the context is in the segment: not excluded, and
+// either included or matching every rule.
+func (idx *segmentIndex) contains(ctx Context) bool {
+	attribute := idx.segment.Attribute
+	if attribute == "" {
+		attribute = TargetingKey
+	}
+	if value, ok := ctx[attribute]; ok {
+		values := []interface{}{value}
+		if list, ok := value.([]interface{}); ok {
+			values = list
+		}
+		included := false
+		for _, v := range values {
+			if idx.excluded[fmt.Sprint(v)] {
+				return false
+			}
+			included = included || idx.included[fmt.Sprint(v)]
+		}
+		if included {
+			return true
+		}
+	}
+
+	if len(idx.segment.Rules) == 0 {
+		return false
+	}
+	for _, rule := range idx.segment.Rules {
+		if !matches(rule, ctx) {
+			return false
+		}
+	}
+	return true
+}
+
+func NewSnapshot(features []*models.Feature, dependencies []models.FeatureDependency) *Snapshot {
+	s := &Snapshot{
+		byKey:     make(map[string][]*models.Feature, len(features)),
+		byID:      make(map[primitive.ObjectID]*models.Feature, len(features)),
+		parents:   make(map[primitive.ObjectID][]primitive.ObjectID),
+		overrides: make(map[primitive.ObjectID]*overrideIndex),
+	}
+	for _, f := range features {
+		s.byKey[f.FlagKey()] = append(s.byKey[f.FlagKey()], f)
+		s.byID[f.ID] = f
+		if len(f.Overrides) > 0 {
+			s.overrides[f.ID] = newOverrideIndex(f.Overrides)
+		}
+	}
+	for _, d := range dependencies {
+		s.parents[d.ChildID] = append(s.parents[d.ChildID], d.ParentID)
+	}
+	return s
+}
+
+// WithSegments makes segments available to rules that reference them by
+// key. Without it, or for keys it lacks, no context is in a segment. Call it
+// before the snapshot is shared.
+func (s *Snapshot) WithSegments(segments []*models.Segment) *Snapshot {
+	s.segments = make(map[string]*segmentIndex, len(segments))
+	for _, segment := range segments {
+		s.segments[segment.Key] = newSegmentIndex(segment)
+	}
+	return s
+}
+
+// WithEntitlements gates the snapshot's features by plan. Call it before the
+// snapshot is shared.
+func (s *Snapshot) WithEntitlements(e *Entitlements) *Snapshot {
+	s.entitlements = e
+	return s
+}
+
+// Evaluate resolves one flag.
+func (s *Snapshot) Evaluate(key string, ctx Context) Result {
+	matches := s.byKey[key]
+	switch len(matches) {
+	case 0:
+		return errorResult(key, ErrorFlagNotFound, fmt.Sprintf("flag %q not found", key))
+	case 1:
+		return s.evaluate(matches[0], ctx, make(map[primitive.ObjectID]*Result))
+	default:
+		return errorResult(key, ErrorGeneral, fmt.Sprintf("flag key %q matches %d features", key, len(matches)))
+	}
+}
+
+// EvaluateAll resolves every flag, sorted by key.
+func (s *Snapshot) EvaluateAll(ctx Context) []Result {
+	keys := make([]string, 0, len(s.byKey))
+	for key := range s.byKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	results := make([]Result, len(keys))
+	for i, key := range keys {
+		results[i] = s.Evaluate(key, ctx)
+	}
+	return results
+}
+
+// HasVariants reports whether the flag with key resolves to declared
+// variants rather than plain on and off.
+func (s *Snapshot) HasVariants(key string) bool {
+	matches := s.byKey[key]
+	return len(matches) == 1 && len(matches[0].Variants) > 0
+}
+
+// evaluate resolves f. Results are memoised per call so shared ancestors
+// are evaluated once; a nil entry marks a feature being evaluated, which
+// only a cycle can reach.
+func (s *Snapshot) evaluate(f *models.Feature, ctx Context, memo map[primitive.ObjectID]*Result) Result {
+	if r, ok := memo[f.ID]; ok {
+		if r == nil {
+			return errorResult(f.FlagKey(), ErrorGeneral, "cyclic dependency detected")
+		}
+		return *r
+	}
+	memo[f.ID] = nil
+
+	r := s.resolve(f, ctx, memo)
+	memo[f.ID] = &r
+	return r
+}
+
+func (s *Snapshot) resolve(f *models.Feature, ctx Context, memo map[primitive.ObjectID]*Result) Result {
+	var override *models.Override
+	if idx := s.overrides[f.ID]; idx != nil {
+		override = idx.match(ctx)
+	}
+	if override == nil && !f.IsEnabled {
+		return off(f, ReasonDisabled)
+	}
+
+	if r, ok := s.checkEntitlement(f, ctx); !ok {
+		return r
+	}
+
+	if override != nil && override.Action == models.OverrideActionDeny {
+		return overridden(off(f, ReasonOverride), override)
+	}
+
+	// A feature is only on where every parent is on, unless an override
+	// bypasses its dependencies.
+	if override == nil || !override.BypassDependencies {
+		for _, parentID := range s.parents[f.ID] {
+			parent, ok := s.byID[parentID]
+			if !ok {
+				continue
+			}
+			if !s.evaluate(parent, ctx, memo).On() {
+				r := off(f, ReasonDisabled)
+				r.Metadata["disabledBy"] = parent.Name
+				return r
+			}
+		}
+	}
+
+	if override != nil {
+		if override.Variant == "" {
+			return overridden(on(f, ctx, ReasonOverride), override)
+		}
+		variant := variantByName(f, override.Variant)
+		if variant == nil {
+			return errorResult(f.FlagKey(), ErrorParse, fmt.Sprintf("override variant %q not found", override.Variant))
+		}
+		return overridden(result(f, variant.Value, variant.Name, ReasonOverride), override)
+	}
+
+	for _, rule := range f.Rules {
+		if !s.matches(rule, ctx) {
+			return off(f, ReasonDefault)
+		}
+	}
+	if f.Rollout != nil && !rolledOut(f, ctx) {
+		r := off(f, ReasonDefault)
+		r.Metadata["rollout"] = f.Rollout.Percentage()
+		return r
+	}
+	reason := ReasonStatic
+	if len(f.Rules) > 0 {
+		reason = ReasonTargetingMatch
+	}
+	return on(f, ctx, reason)
+}
+
+// on is the result for a feature that is on for the context. Splits report
+// ReasonSplit, except under an override.
+func on(f *models.Feature, ctx Context, reason Reason) Result {
+	if len(f.Variants) == 0 {
+		return result(f, true, "on", reason)
+	}
+
+	if f.Weighted() {
+		targetingKey, ok := ctx[TargetingKey].(string)
+		if !ok || targetingKey == "" {
+			return errorResult(f.FlagKey(), ErrorTargetingKeyMissing, "weighted variants require a targeting key")
+		}
+		variant := split(f, targetingKey)
+		if reason != ReasonOverride {
+			reason = ReasonSplit
+		}
+		return result(f, variant.Value, variant.Name, reason)
+	}
+
+	variant := variantByName(f, f.DefaultVariant)
+	if variant == nil {
+		return errorResult(f.FlagKey(), ErrorParse, fmt.Sprintf("default variant %q not found", f.DefaultVariant))
+	}
+	return result(f, variant.Value, variant.Name, reason)
+}
+
+// checkEntitlement returns false with the result to serve when the plan in
+// the context is not entitled to f. Unknown plans are entitled to nothing.
+func (s *Snapshot) checkEntitlement(f *models.Feature, ctx Context) (Result, bool) {
+	// Features created before types were validated may have none.
+	if s.entitlements == nil || !f.Type.Valid() {
+		return Result{}, true
+	}
+	value, ok := ctx[s.entitlements.PlanAttribute]
+	if !ok {
+		return Result{}, true
+	}
+	plan, ok := value.(string)
+	if !ok {
+		return errorResult(f.FlagKey(), ErrorInvalidContext, fmt.Sprintf("%s must be a string", s.entitlements.PlanAttribute)), false
+	}
+	if !s.entitlements.Plans.Entitled(plan, f.Type) {
+		r := off(f, ReasonDisabled)
+		r.Metadata["requiredPlan"] = string(f.Type)
+		return r, false
+	}
+	return Result{}, true
+}
+
+// overridden records in r which override decided it.
+func overridden(r Result, o *models.Override) Result {
+	if r.Metadata != nil {
+		r.Metadata["override"] = string(o.Action)
+		r.Metadata["overrideAttribute"] = o.Attribute
+	}
+	return r
+}
+
+// off is the result for a feature that is disabled, gated by a parent, or
+// not targeted at the context.
+func off(f *models.Feature, reason Reason) Result {
+	if len(f.Variants) == 0 {
+		return result(f, false, "off", reason)
+	}
+	if variant := variantByName(f, f.OffVariant); variant != nil {
+		return result(f, variant.Value, variant.Name, reason)
+	}
+	return result(f, nil, "", reason)
+}
+
+func result(f *models.Feature, value interface{}, variant string, reason Reason) Result {
+	return Result{
+		Key:      f.FlagKey(),
+		Value:    value,
+		Variant:  variant,
+		Reason:   reason,
+		Metadata: map[string]interface{}{"featureId": f.ID.Hex()},
+	}
+}
+
+func errorResult(key string, code ErrorCode, details string) Result {
+	return Result{Key: key, Reason: ReasonError, ErrorCode: code, ErrorDetails: details}
+}
+
+func variantByName(f *models.Feature, name string) *models.Variant {
+	if name == "" {
+		return nil
+	}
+	for i := range f.Variants {
+		if f.Variants[i].Name == name {
+			return &f.Variants[i]
+		}
+	}
+	return nil
+}
+
+// split assigns the targeting key to a variant in proportion to the weights.
+// The same key always lands on the same variant of a flag. The flag is
+// hashed by key, which unlike the name stays the same when a manifest
+// renames the feature.
+func split(f *models.Feature, targetingKey string) models.Variant {
+	total := 0
+	for _, v := range f.Variants {
+		total += v.Weight
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(f.FlagKey() + "/" + targetingKey))
+	bucket := int(h.Sum32() % uint32(total))
+
+	for _, v := range f.Variants {
+		if bucket < v.Weight {
+			return v
+		}
+		bucket -= v.Weight
+	}
+	return f.Variants[len(f.Variants)-1]
+}
+
+// rolledOut reports whether the rollout plan of f has reached the context.
+// Contexts are bucketed by targeting key in steps of 0.01%, so each key
+// stays on as the percentage rises; contexts without one are only reached at
+// 100%. The flag key seeds the hash so that rollouts and weighted splits of
+// the same flag pick independently.
+func rolledOut(f *models.Feature, ctx Context) bool {
+	percentage := f.Rollout.Percentage()
+	if percentage >= 100 {
+		return true
+	}
+	targetingKey, ok := ctx[TargetingKey].(string)
+	if !ok || targetingKey == "" || percentage <= 0 {
+		return false
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte("rollout/" + f.FlagKey() + "/" + targetingKey))
+	return float64(h.Sum32()%10000) < percentage*100
+}
+
+// matches reports whether the context satisfies a rule of a feature, which
+// unlike the rules of a segment may reference segments.
+func (s *Snapshot) matches(rule models.TargetingRule, ctx Context) bool {
+	if !rule.Operator.Segment() {
+		return matches(rule, ctx)
+	}
+	found := false
+	for _, key := range rule.Values {
+		if idx := s.segments[key]; idx != nil && idx.contains(ctx) {
+			found = true
+			break
+		}
+	}
+	return found == (rule.Operator == models.RuleOperatorInSegment)
+}
+
+// matches reports whether the context satisfies an attribute rule. Values are compared
+// as strings; a list attribute matches in if any element is listed. A missing
+// attribute never matches in and always matches not_in.
+func matches(rule models.TargetingRule, ctx Context) bool {
+	found := false
+	if value, ok := ctx[rule.Attribute]; ok {
+		found = contains(rule.Values, value)
+	}
+	if rule.Operator == models.RuleOperatorNotIn {
+		return !found
+	}
+	return found
+}
+
+func contains(values []string, value interface{}) bool {
+	if list, ok := value.([]interface{}); ok {
+		for _, v := range list {
+			if contains(values, v) {
+				return true
+			}
+		}
+		return false
+	}
+
+	s := fmt.Sprint(value)
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
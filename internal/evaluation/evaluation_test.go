@@ -0,0 +1,341 @@
+package evaluation
+
+import (
+	"testing"
+
+	"feature-flags/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func feature(name string, enabled bool, targeting models.Targeting) *models.Feature {
+	return &models.Feature{ID: primitive.NewObjectID(), Name: name, Type: models.FeatureTypeBasic, IsEnabled: enabled, Targeting: targeting}
+}
+
+func TestSnapshot_Evaluate(t *testing.T) {
+	checkout := feature("checkout", true, models.Targeting{})
+	oneClick := feature("one-click", true, models.Targeting{
+		Rules: []models.TargetingRule{{Attribute: "country", Operator: models.RuleOperatorIn, Values: []string{"DE", "FR"}}},
+	})
+	theme := feature("theme", true, models.Targeting{
+		Variants:       []models.Variant{{Name: "dark", Value: "dark"}, {Name: "light", Value: "light"}},
+		DefaultVariant: "dark",
+		OffVariant:     "light",
+	})
+	legacy := feature("legacy", false, models.Targeting{})
+	wallet := feature("wallet", true, models.Targeting{})
+	snapshot := NewSnapshot(
+		[]*models.Feature{checkout, oneClick, theme, legacy, wallet},
+		[]models.FeatureDependency{
+			{ParentID: checkout.ID, ChildID: oneClick.ID},
+			{ParentID: oneClick.ID, ChildID: theme.ID},
+			{ParentID: legacy.ID, ChildID: wallet.ID},
+		},
+	)
+
+	r := snapshot.Evaluate("checkout", Context{})
+	assert.Equal(t, true, r.Value)
+	assert.Equal(t, ReasonStatic, r.Reason)
+	assert.Equal(t, checkout.ID.Hex(), r.Metadata["featureId"])
+
+	r = snapshot.Evaluate("one-click", Context{"country": "DE"})
+	assert.Equal(t, true, r.Value)
+	assert.Equal(t, ReasonTargetingMatch, r.Reason)
+
+	r = snapshot.Evaluate("one-click", Context{"country": "US"})
+	assert.Equal(t, false, r.Value)
+	assert.Equal(t, ReasonDefault, r.Reason)
+
+	// A child is gated by every ancestor, including targeting.
+	r = snapshot.Evaluate("theme", Context{"country": "DE"})
+	assert.Equal(t, "dark", r.Value)
+	r = snapshot.Evaluate("theme", Context{"country": "US"})
+	assert.Equal(t, "light", r.Value)
+	assert.Equal(t, ReasonDisabled, r.Reason)
+	assert.Equal(t, "one-click", r.Metadata["disabledBy"])
+
+	r = snapshot.Evaluate("legacy", Context{})
+	assert.Equal(t, false, r.Value)
+	assert.Equal(t, ReasonDisabled, r.Reason)
+	r = snapshot.Evaluate("wallet", Context{})
+	assert.Equal(t, ReasonDisabled, r.Reason)
+	assert.Equal(t, "legacy", r.Metadata["disabledBy"])
+
+	r = snapshot.Evaluate("missing", Context{})
+	assert.Equal(t, ReasonError, r.Reason)
+	assert.Equal(t, ErrorFlagNotFound, r.ErrorCode)
+
+	all := snapshot.EvaluateAll(Context{})
+	assert.Len(t, all, 5)
+	assert.Equal(t, "checkout", all[0].Key)
+}
+
+func TestSnapshot_EvaluateByKey(t *testing.T) {
+	checkout := feature("Checkout", true, models.Targeting{})
+	checkout.Key = "checkout"
+	legacy := feature("Legacy Flow", true, models.Targeting{})
+	snapshot := NewSnapshot([]*models.Feature{checkout, legacy}, nil)
+
+	r := snapshot.Evaluate("checkout", Context{})
+	assert.Equal(t, true, r.Value)
+	assert.Equal(t, "checkout", r.Key)
+	assert.Equal(t, ErrorFlagNotFound, snapshot.Evaluate("Checkout", Context{}).ErrorCode)
+
+	// Features without a key are evaluated by name.
+	assert.Equal(t, true, snapshot.Evaluate("Legacy Flow", Context{}).Value)
+
+	// Errors report the key too.
+	banner := feature("Banner", true, models.Targeting{
+		Variants: []models.Variant{{Name: "a", Value: "A", Weight: 50}, {Name: "b", Value: "B", Weight: 50}},
+	})
+	banner.Key = "banner"
+	r = NewSnapshot([]*models.Feature{banner}, nil).Evaluate("banner", Context{})
+	assert.Equal(t, ErrorTargetingKeyMissing, r.ErrorCode)
+	assert.Equal(t, "banner", r.Key)
+}
+
+func TestSnapshot_EvaluateSplit(t *testing.T) {
+	banner := feature("banner", true, models.Targeting{
+		Variants:   []models.Variant{{Name: "a", Value: "A", Weight: 50}, {Name: "b", Value: "B", Weight: 50}},
+		OffVariant: "a",
+	})
+	snapshot := NewSnapshot([]*models.Feature{banner}, nil)
+	assert.True(t, snapshot.HasVariants("banner"))
+	assert.False(t, snapshot.HasVariants("missing"))
+
+	r := snapshot.Evaluate("banner", Context{})
+	assert.Equal(t, ReasonError, r.Reason)
+	assert.Equal(t, ErrorTargetingKeyMissing, r.ErrorCode)
+
+	counts := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		key := primitive.NewObjectID().Hex()
+		r := snapshot.Evaluate("banner", Context{TargetingKey: key})
+		assert.Equal(t, ReasonSplit, r.Reason)
+		// Assignment is sticky per targeting key.
+		assert.Equal(t, r.Variant, snapshot.Evaluate("banner", Context{TargetingKey: key}).Variant)
+		counts[r.Variant]++
+	}
+	assert.InDelta(t, 500, counts["a"], 100)
+	assert.InDelta(t, 500, counts["b"], 100)
+
+	// Renaming the feature keeps every assignment.
+	renamed := *banner
+	renamed.Key = "banner"
+	renamed.Name = "Homepage banner"
+	renamedSnapshot := NewSnapshot([]*models.Feature{&renamed}, nil)
+	for i := 0; i < 100; i++ {
+		key := primitive.NewObjectID().Hex()
+		assert.Equal(t, snapshot.Evaluate("banner", Context{TargetingKey: key}).Variant,
+			renamedSnapshot.Evaluate("banner", Context{TargetingKey: key}).Variant)
+	}
+}
+
+func TestSnapshot_EvaluateRollout(t *testing.T) {
+	search := feature("search", true, models.Targeting{})
+	search.Rollout = &models.RolloutPlan{
+		Steps:  []models.RolloutStep{{Percentage: 10, Hold: "1h"}, {Percentage: 50, Hold: "1h"}, {Percentage: 100}},
+		Status: models.RolloutStatusActive,
+	}
+	suggest := feature("suggest", true, models.Targeting{})
+	snapshot := NewSnapshot([]*models.Feature{search, suggest}, []models.FeatureDependency{{ParentID: search.ID, ChildID: suggest.ID}})
+
+	r := snapshot.Evaluate("search", Context{})
+	assert.False(t, r.On(), "contexts without a targeting key wait for 100%")
+	assert.Equal(t, ReasonDefault, r.Reason)
+	assert.Equal(t, 10.0, r.Metadata["rollout"])
+
+	keys := make([]string, 1000)
+	reached := make(map[string]bool)
+	for i := range keys {
+		keys[i] = primitive.NewObjectID().Hex()
+		if snapshot.Evaluate("search", Context{TargetingKey: keys[i]}).On() {
+			reached[keys[i]] = true
+		}
+		// Dependents are only on where the rollout reached.
+		assert.Equal(t, reached[keys[i]], snapshot.Evaluate("suggest", Context{TargetingKey: keys[i]}).On())
+	}
+	assert.InDelta(t, 100, len(reached), 40)
+
+	// Raising the percentage keeps everyone already reached.
+	search.Rollout.Step = 1
+	count := 0
+	for _, key := range keys {
+		on := snapshot.Evaluate("search", Context{TargetingKey: key}).On()
+		if reached[key] {
+			assert.True(t, on)
+		}
+		if on {
+			count++
+		}
+	}
+	assert.InDelta(t, 500, count, 100)
+
+	search.Rollout.Status = models.RolloutStatusAborted
+	assert.False(t, snapshot.Evaluate("search", Context{TargetingKey: keys[0]}).On())
+	search.Rollout.Status = models.RolloutStatusCompleted
+	assert.True(t, snapshot.Evaluate("search", Context{}).On())
+}
+
+func TestSnapshot_EvaluateListAttributesAndNotIn(t *testing.T) {
+	beta := feature("beta", true, models.Targeting{Rules: []models.TargetingRule{
+		{Attribute: "groups", Operator: models.RuleOperatorIn, Values: []string{"testers"}},
+		{Attribute: "plan", Operator: models.RuleOperatorNotIn, Values: []string{"free"}},
+	}})
+	snapshot := NewSnapshot([]*models.Feature{beta}, nil)
+
+	assert.True(t, snapshot.Evaluate("beta", Context{"groups": []interface{}{"staff", "testers"}}).On())
+	assert.False(t, snapshot.Evaluate("beta", Context{"groups": []interface{}{"testers"}, "plan": "free"}).On())
+	assert.False(t, snapshot.Evaluate("beta", Context{}).On())
+}
+
+func TestSnapshot_EvaluateCycle(t *testing.T) {
+	a := feature("a", true, models.Targeting{})
+	b := feature("b", true, models.Targeting{})
+	snapshot := NewSnapshot([]*models.Feature{a, b}, []models.FeatureDependency{
+		{ParentID: a.ID, ChildID: b.ID},
+		{ParentID: b.ID, ChildID: a.ID},
+	})
+
+	r := snapshot.Evaluate("a", Context{})
+	assert.False(t, r.On())
+	assert.Equal(t, ReasonDisabled, r.Reason)
+}
+
+func TestSnapshot_EvaluateEntitlements(t *testing.T) {
+	search := feature("search", true, models.Targeting{})
+	reports := feature("reports", true, models.Targeting{})
+	reports.Type = models.FeatureTypePremium
+	export := feature("export", true, models.Targeting{})
+	audit := feature("audit", true, models.Targeting{})
+	audit.Type = models.FeatureTypeEnterprise
+	snapshot := NewSnapshot([]*models.Feature{search, reports, export, audit}, []models.FeatureDependency{
+		{ParentID: reports.ID, ChildID: export.ID},
+	}).WithEntitlements(&Entitlements{
+		Plans:         models.PlanHierarchy{"free", "basic", "premium", "enterprise"},
+		PlanAttribute: "plan",
+	})
+
+	on := func(key, plan string) bool {
+		return snapshot.Evaluate(key, Context{"plan": plan}).On()
+	}
+	assert.True(t, on("search", "basic"))
+	assert.False(t, on("reports", "basic"))
+	assert.True(t, on("reports", "premium"))
+	assert.True(t, on("audit", "enterprise"))
+	assert.False(t, on("audit", "premium"))
+	assert.False(t, on("search", "free"))
+	assert.False(t, on("search", "unknown"))
+
+	r := snapshot.Evaluate("reports", Context{"plan": "basic"})
+	assert.Equal(t, ReasonDisabled, r.Reason)
+	assert.Equal(t, "premium", r.Metadata["requiredPlan"])
+
+	// Dependents of a feature the plan lacks are off too.
+	assert.Equal(t, "reports", snapshot.Evaluate("export", Context{"plan": "basic"}).Metadata["disabledBy"])
+
+	// Contexts without a plan are not gated.
+	assert.True(t, snapshot.Evaluate("audit", Context{}).On())
+	assert.Equal(t, ErrorInvalidContext, snapshot.Evaluate("audit", Context{"plan": 3}).ErrorCode)
+}
+
+func TestSnapshot_EvaluateSegments(t *testing.T) {
+	staff := &models.Segment{
+		Key:      "staff",
+		Rules:    []models.TargetingRule{{Attribute: "email_domain", Operator: models.RuleOperatorIn, Values: []string{"example.com"}}},
+		Included: []string{"contractor-1"},
+		Excluded: []string{"intern-7"},
+	}
+	merchants := &models.Segment{Key: "beta-merchants", Attribute: "tenantId", Included: []string{"acme", "globex"}}
+	dashboard := feature("dashboard", true, models.Targeting{
+		Rules: []models.TargetingRule{{Operator: models.RuleOperatorInSegment, Values: []string{"staff", "beta-merchants"}}},
+	})
+	payouts := feature("payouts", true, models.Targeting{
+		Rules: []models.TargetingRule{
+			{Attribute: "country", Operator: models.RuleOperatorIn, Values: []string{"IN"}},
+			{Operator: models.RuleOperatorNotInSegment, Values: []string{"beta-merchants", "missing"}},
+		},
+	})
+	snapshot := NewSnapshot([]*models.Feature{dashboard, payouts}, nil).WithSegments([]*models.Segment{staff, merchants})
+
+	// Rules and the included list each put a context in a segment.
+	r := snapshot.Evaluate("dashboard", Context{"targetingKey": "user-1", "email_domain": "example.com"})
+	assert.Equal(t, ReasonTargetingMatch, r.Reason)
+	assert.True(t, r.On())
+	assert.True(t, snapshot.Evaluate("dashboard", Context{"targetingKey": "contractor-1"}).On())
+	assert.True(t, snapshot.Evaluate("dashboard", Context{"tenantId": "globex"}).On())
+
+	// Exclusion wins over rules.
+	r = snapshot.Evaluate("dashboard", Context{"targetingKey": "intern-7", "email_domain": "example.com"})
+	assert.Equal(t, ReasonDefault, r.Reason)
+
+	// A segment without rules holds only the contexts it includes.
+	assert.False(t, snapshot.Evaluate("dashboard", Context{"tenantId": "initech"}).On())
+
+	// Unknown segments contain no one.
+	assert.True(t, snapshot.Evaluate("payouts", Context{"country": "IN", "tenantId": "initech"}).On())
+	assert.False(t, snapshot.Evaluate("payouts", Context{"country": "IN", "tenantId": "acme"}).On())
+
+	// Segment edits apply to every feature using them.
+	merchants.Included = []string{"initech"}
+	snapshot = NewSnapshot([]*models.Feature{dashboard, payouts}, nil).WithSegments([]*models.Segment{staff, merchants})
+	assert.False(t, snapshot.Evaluate("payouts", Context{"country": "IN", "tenantId": "initech"}).On())
+	assert.True(t, snapshot.Evaluate("dashboard", Context{"tenantId": "initech"}).On())
+}
+
+func TestSnapshot_EvaluateOverrides(t *testing.T) {
+	checkout := feature("checkout", true, models.Targeting{})
+	beta := feature("beta", false, models.Targeting{
+		Variants:       []models.Variant{{Name: "grid", Value: "grid"}, {Name: "list", Value: "list"}},
+		DefaultVariant: "list",
+		OffVariant:     "list",
+	})
+	beta.Overrides = []models.Override{
+		{Attribute: "tenantId", Value: "acme", Action: models.OverrideActionAllow, Variant: "grid"},
+		{Attribute: "tenantId", Value: "globex", Action: models.OverrideActionAllow},
+		{Attribute: "userId", Value: "mallory", Action: models.OverrideActionDeny},
+	}
+	wallet := feature("wallet", true, models.Targeting{
+		Rules: []models.TargetingRule{{Attribute: "country", Operator: models.RuleOperatorIn, Values: []string{"DE"}}},
+	})
+	wallet.Overrides = []models.Override{
+		{Attribute: "tenantId", Value: "acme", Action: models.OverrideActionDeny},
+		{Attribute: "groups", Value: "staff", Action: models.OverrideActionAllow, BypassDependencies: true},
+	}
+	checkout.Overrides = []models.Override{{Attribute: "tenantId", Value: "initech", Action: models.OverrideActionDeny}}
+	snapshot := NewSnapshot([]*models.Feature{checkout, beta, wallet}, []models.FeatureDependency{
+		{ParentID: checkout.ID, ChildID: beta.ID},
+		{ParentID: checkout.ID, ChildID: wallet.ID},
+	})
+
+	// An allow override turns a disabled feature on.
+	r := snapshot.Evaluate("beta", Context{"tenantId": "acme"})
+	assert.Equal(t, "grid", r.Value)
+	assert.Equal(t, ReasonOverride, r.Reason)
+	assert.Equal(t, "allow", r.Metadata["override"])
+	assert.True(t, r.On())
+	assert.Equal(t, "list", snapshot.Evaluate("beta", Context{"tenantId": "globex"}).Value)
+	assert.Equal(t, ReasonDisabled, snapshot.Evaluate("beta", Context{"tenantId": "hooli"}).Reason)
+
+	// Deny wins over allow.
+	r = snapshot.Evaluate("beta", Context{"tenantId": "acme", "userId": "mallory"})
+	assert.Equal(t, "list", r.Value)
+	assert.Equal(t, ReasonOverride, r.Reason)
+	assert.Equal(t, "deny", r.Metadata["override"])
+	assert.False(t, r.On())
+
+	// Overrides take precedence over targeting rules.
+	assert.False(t, snapshot.Evaluate("wallet", Context{"tenantId": "acme", "country": "DE"}).On())
+	assert.True(t, snapshot.Evaluate("wallet", Context{"groups": []interface{}{"staff"}, "country": "US"}).On())
+
+	// Parents still gate an allow override unless it bypasses them.
+	r = snapshot.Evaluate("beta", Context{"tenantId": "acme", "userId": "bob"})
+	assert.True(t, r.On())
+	r = snapshot.Evaluate("beta", Context{"tenantId": []interface{}{"acme", "initech"}})
+	assert.Equal(t, ReasonDisabled, r.Reason)
+	assert.Equal(t, "checkout", r.Metadata["disabledBy"])
+	r = snapshot.Evaluate("wallet", Context{"tenantId": "initech", "groups": []interface{}{"staff"}})
+	assert.Equal(t, ReasonOverride, r.Reason)
+	assert.True(t, r.On())
+}
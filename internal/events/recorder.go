@@ -0,0 +1,160 @@
+// Package events records exposure events off the evaluation path. Record
+// queues an event without blocking and a background worker writes the queue
+// in batches.
+package events
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"feature-flags/internal/logging"
+	"feature-flags/internal/metrics"
+	"feature-flags/internal/models"
+)
+
+// drainTimeout bounds the final write when Run stops.
+const drainTimeout = 5 * time.Second
+
+type Config struct {
+	// BufferSize is how many events may wait to be written. Events recorded
+	// while the buffer is full are dropped.
+	BufferSize int
+	// BatchSize is the most events written at once.
+	BatchSize int
+	// FlushInterval is the longest an event waits for its batch to fill.
+	FlushInterval time.Duration
+}
+
+// Sink stores batches of exposures.
+type Sink interface {
+	InsertExposures(ctx context.Context, exposures []models.Exposure) error
+}
+
+// Status reports the recorder's counters since startup.
+type Status struct {
+	Queued    int    `json:"queued"`
+	Written   int64  `json:"written"`
+	Dropped   int64  `json:"dropped"`
+	Failed    int64  `json:"failed"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+type Recorder struct {
+	cfg   Config
+	sink  Sink
+	queue chan models.Exposure
+
+	written atomic.Int64
+	dropped atomic.Int64
+	failed  atomic.Int64
+
+	mu        sync.Mutex
+	lastError string
+}
+
+func NewRecorder(cfg Config, sink Sink) *Recorder {
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = 10000
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 500
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = time.Second
+	}
+	return &Recorder{
+		cfg:   cfg,
+		sink:  sink,
+		queue: make(chan models.Exposure, cfg.BufferSize),
+	}
+}
+
+// Record queues an exposure. It never blocks: when the buffer is full the
+// event is dropped and counted, so a slow database cannot delay
+// evaluations.
+func (r *Recorder) Record(exposure models.Exposure) {
+	select {
+	case r.queue <- exposure:
+	default:
+		r.dropped.Add(1)
+		metrics.ExposureEvents.WithLabelValues("dropped").Inc()
+	}
+}
+
+// Run writes queued events in batches of up to BatchSize, and at least
+// every FlushInterval, until ctx is cancelled. It then writes the events
+// still queued and returns. A batch that fails to write is dropped and
+// counted rather than retried, so a database outage cannot grow memory.
+func (r *Recorder) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]models.Exposure, 0, r.cfg.BatchSize)
+	for {
+		select {
+		case exposure := <-r.queue:
+			batch = append(batch, exposure)
+			if len(batch) == r.cfg.BatchSize {
+				batch = r.flush(ctx, batch)
+			}
+		case <-ticker.C:
+			batch = r.flush(ctx, batch)
+		case <-ctx.Done():
+			r.drain(batch)
+			return
+		}
+	}
+}
+
+// drain writes batch and everything still queued.
+func (r *Recorder) drain(batch []models.Exposure) {
+	ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+
+	for {
+		select {
+		case exposure := <-r.queue:
+			batch = append(batch, exposure)
+			if len(batch) == r.cfg.BatchSize {
+				batch = r.flush(ctx, batch)
+			}
+		default:
+			r.flush(ctx, batch)
+			return
+		}
+	}
+}
+
+// flush writes batch and returns it emptied for reuse.
+func (r *Recorder) flush(ctx context.Context, batch []models.Exposure) []models.Exposure {
+	if len(batch) == 0 {
+		return batch
+	}
+	if err := r.sink.InsertExposures(ctx, batch); err != nil {
+		r.failed.Add(int64(len(batch)))
+		metrics.ExposureEvents.WithLabelValues("failed").Add(float64(len(batch)))
+		r.mu.Lock()
+		r.lastError = err.Error()
+		r.mu.Unlock()
+		slog.ErrorContext(ctx, "failed to write exposure events", "count", len(batch), logging.Error(err))
+	} else {
+		r.written.Add(int64(len(batch)))
+		metrics.ExposureEvents.WithLabelValues("written").Add(float64(len(batch)))
+	}
+	return batch[:0]
+}
+
+func (r *Recorder) Status() Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return Status{
+		Queued:    len(r.queue),
+		Written:   r.written.Load(),
+		Dropped:   r.dropped.Load(),
+		Failed:    r.failed.Load(),
+		LastError: r.lastError,
+	}
+}
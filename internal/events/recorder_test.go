@@ -0,0 +1,104 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"feature-flags/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSink struct {
+	mu      sync.Mutex
+	batches [][]models.Exposure
+	err     error
+}
+
+func (s *fakeSink) InsertExposures(_ context.Context, exposures []models.Exposure) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.batches = append(s.batches, append([]models.Exposure(nil), exposures...))
+	return nil
+}
+
+func (s *fakeSink) sizes() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sizes := make([]int, len(s.batches))
+	for i, batch := range s.batches {
+		sizes[i] = len(batch)
+	}
+	return sizes
+}
+
+func exposure(i int) models.Exposure {
+	return models.Exposure{FlagKey: "checkout", Variant: "b", TargetingKey: fmt.Sprintf("user-%d", i)}
+}
+
+func TestRecorder_Batches(t *testing.T) {
+	sink := &fakeSink{}
+	r := NewRecorder(Config{BufferSize: 100, BatchSize: 3, FlushInterval: 50 * time.Millisecond}, sink)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		r.Run(ctx)
+		close(done)
+	}()
+
+	for i := 0; i < 7; i++ {
+		r.Record(exposure(i))
+	}
+	// Two full batches are written at once and the rest on the next tick.
+	require.Eventually(t, func() bool { return len(sink.sizes()) == 3 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []int{3, 3, 1}, sink.sizes())
+
+	// Events still queued are written on shutdown.
+	r.Record(exposure(7))
+	cancel()
+	<-done
+	assert.Equal(t, 8, sum(sink.sizes()))
+	assert.Equal(t, Status{Written: 8}, r.Status())
+}
+
+func TestRecorder_DropsWhenFull(t *testing.T) {
+	sink := &fakeSink{}
+	r := NewRecorder(Config{BufferSize: 2, BatchSize: 10, FlushInterval: time.Hour}, sink)
+	for i := 0; i < 5; i++ {
+		r.Record(exposure(i))
+	}
+	assert.Equal(t, Status{Queued: 2, Dropped: 3}, r.Status())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r.Run(ctx)
+	assert.Equal(t, []int{2}, sink.sizes())
+}
+
+func TestRecorder_Failures(t *testing.T) {
+	sink := &fakeSink{err: errors.New("connection refused")}
+	r := NewRecorder(Config{BufferSize: 10, BatchSize: 10, FlushInterval: time.Hour}, sink)
+	r.Record(exposure(0))
+	r.Record(exposure(1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r.Run(ctx)
+	assert.Equal(t, Status{Failed: 2, LastError: "connection refused"}, r.Status())
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
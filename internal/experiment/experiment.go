@@ -0,0 +1,106 @@
+// Package experiment computes the results of A/B experiments from exposure
+// and conversion events.
+package experiment
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"feature-flags/internal/models"
+)
+
+// Analyze counts, for each variant, the units first exposed to it and those
+// of them that converted at or after that exposure, and compares every other
+// variant with control. Results follow the order of variants; variants that
+// units were exposed to but that are no longer declared follow, sorted.
+// lastConversions holds the time of each unit's latest conversion.
+func Analyze(units []models.ExposedUnit, lastConversions map[string]time.Time, variants []string, control string, confidence float64) []models.VariantResult {
+	index := make(map[string]int, len(variants))
+	results := make([]models.VariantResult, 0, len(variants))
+	for _, variant := range variants {
+		index[variant] = len(results)
+		results = append(results, models.VariantResult{Variant: variant})
+	}
+	var undeclared []string
+	for _, unit := range units {
+		if _, ok := index[unit.Variant]; !ok {
+			index[unit.Variant] = -1
+			undeclared = append(undeclared, unit.Variant)
+		}
+	}
+	sort.Strings(undeclared)
+	for _, variant := range undeclared {
+		index[variant] = len(results)
+		results = append(results, models.VariantResult{Variant: variant})
+	}
+
+	for _, unit := range units {
+		r := &results[index[unit.Variant]]
+		r.Exposed++
+		if at, ok := lastConversions[unit.TargetingKey]; ok && !at.Before(unit.ExposedAt) {
+			r.Converted++
+		}
+	}
+
+	z := zScore(confidence)
+	var baseline *models.VariantResult
+	for i := range results {
+		r := &results[i]
+		if r.Exposed > 0 {
+			r.ConversionRate = float64(r.Converted) / float64(r.Exposed)
+		}
+		r.Lower, r.Upper = Wilson(r.Converted, r.Exposed, z)
+		if r.Variant == control {
+			baseline = r
+		}
+	}
+	if baseline == nil || baseline.Exposed == 0 {
+		return results
+	}
+	for i := range results {
+		if r := &results[i]; r != baseline && r.Exposed > 0 {
+			r.Difference = difference(baseline, r, z)
+		}
+	}
+	return results
+}
+
+// Wilson returns the Wilson score interval of a proportion at the
+// confidence level with the given z-score. Unlike the normal approximation
+// it stays within [0, 1] and is usable for small samples and rates near 0
+// or 1. Without trials the interval is [0, 1].
+func Wilson(successes, trials int, z float64) (lower, upper float64) {
+	if trials == 0 {
+		return 0, 1
+	}
+	n := float64(trials)
+	p := float64(successes) / n
+	z2 := z * z
+	center := (p + z2/(2*n)) / (1 + z2/n)
+	margin := z / (1 + z2/n) * math.Sqrt(p*(1-p)/n+z2/(4*n*n))
+	return math.Max(0, center-margin), math.Min(1, center+margin)
+}
+
+// difference compares variant with control: the interval uses the unpooled
+// standard error and the p-value the pooled one.
+func difference(control, variant *models.VariantResult, z float64) *models.Difference {
+	p1, n1 := control.ConversionRate, float64(control.Exposed)
+	p2, n2 := variant.ConversionRate, float64(variant.Exposed)
+	d := &models.Difference{Value: p2 - p1, Lower: p2 - p1, Upper: p2 - p1, PValue: 1}
+
+	if se := math.Sqrt(p1*(1-p1)/n1 + p2*(1-p2)/n2); se > 0 {
+		d.Lower, d.Upper = d.Value-z*se, d.Value+z*se
+	}
+	pooled := float64(control.Converted+variant.Converted) / (n1 + n2)
+	if se := math.Sqrt(pooled * (1 - pooled) * (1/n1 + 1/n2)); se > 0 {
+		d.PValue = math.Erfc(math.Abs(d.Value/se) / math.Sqrt2)
+	}
+	return d
+}
+
+// zScore returns the two-sided critical value of the standard normal
+// distribution for a confidence level, e.g. 1.96 for 0.95.
+func zScore(confidence float64) float64 {
+	return math.Sqrt2 * math.Erfinv(confidence)
+}
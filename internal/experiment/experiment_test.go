@@ -0,0 +1,71 @@
+package experiment
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"feature-flags/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWilson(t *testing.T) {
+	lower, upper := Wilson(50, 100, zScore(0.95))
+	assert.InDelta(t, 0.4038, lower, 0.0001)
+	assert.InDelta(t, 0.5962, upper, 0.0001)
+
+	lower, upper = Wilson(0, 20, zScore(0.95))
+	assert.Equal(t, 0.0, lower)
+	assert.InDelta(t, 0.1611, upper, 0.0001)
+
+	lower, upper = Wilson(0, 0, zScore(0.95))
+	assert.Equal(t, 0.0, lower)
+	assert.Equal(t, 1.0, upper)
+}
+
+func TestAnalyze(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	var units []models.ExposedUnit
+	conversions := make(map[string]time.Time)
+	expose := func(variant string, n, converted int) {
+		for i := 0; i < n; i++ {
+			key := fmt.Sprintf("%s-%d", variant, i)
+			units = append(units, models.ExposedUnit{TargetingKey: key, Variant: variant, ExposedAt: start})
+			if i < converted {
+				conversions[key] = start.Add(time.Hour)
+			}
+		}
+	}
+	expose("control", 1000, 100)
+	expose("treatment", 1000, 130)
+	expose("retired", 10, 1)
+	// Conversions before the exposure do not count.
+	units = append(units, models.ExposedUnit{TargetingKey: "early", Variant: "control", ExposedAt: start})
+	conversions["early"] = start.Add(-time.Hour)
+
+	results := Analyze(units, conversions, []string{"control", "treatment", "unused"}, "control", 0.95)
+	require.Len(t, results, 4)
+	assert.Equal(t, []string{"control", "treatment", "unused", "retired"},
+		[]string{results[0].Variant, results[1].Variant, results[2].Variant, results[3].Variant})
+
+	control := results[0]
+	assert.Equal(t, 1001, control.Exposed)
+	assert.Equal(t, 100, control.Converted)
+	assert.Nil(t, control.Difference)
+
+	treatment := results[1]
+	assert.Equal(t, 0.13, treatment.ConversionRate)
+	assert.Less(t, treatment.Lower, 0.13)
+	assert.Greater(t, treatment.Upper, 0.13)
+	require.NotNil(t, treatment.Difference)
+	assert.InDelta(t, 0.0301, treatment.Difference.Value, 0.0001)
+	assert.Greater(t, treatment.Difference.Lower, 0.0)
+	assert.Less(t, treatment.Difference.PValue, 0.05)
+
+	unused := results[2]
+	assert.Zero(t, unused.Exposed)
+	assert.Nil(t, unused.Difference)
+	assert.Equal(t, 1.0, unused.Upper)
+}
@@ -0,0 +1,276 @@
+// Package gitops keeps the server in sync with a directory of flag
+// manifests, such as a git checkout. The directory is the source of truth:
+// features declared in it are locked against API changes, drift is reported
+// and, in reconcile mode, corrected.
+package gitops
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"feature-flags/internal/logging"
+	"feature-flags/internal/manifest"
+	"feature-flags/internal/services"
+)
+
+type Mode string
+
+const (
+	// ModeReconcile applies the manifests whenever the server drifts.
+	ModeReconcile Mode = "reconcile"
+	// ModeDetect only reports drift.
+	ModeDetect Mode = "detect"
+)
+
+// ErrNoManifests is returned when the directory holds no manifest files.
+// Syncing an empty directory is refused so that a bad checkout cannot prune
+// every flag.
+var ErrNoManifests = errors.New("no manifest files found")
+
+type Config struct {
+	Dir      string
+	Interval time.Duration
+	Mode     Mode
+	// Prune deletes features and dependencies missing from the manifests.
+	Prune bool
+}
+
+// Reconciler is the part of the feature service the syncer drives.
+type Reconciler interface {
+	PlanManifest(ctx context.Context, m *manifest.Manifest, opts services.ManifestOptions) (*manifest.Plan, error)
+	ApplyManifest(ctx context.Context, m *manifest.Manifest, opts services.ManifestOptions) (*manifest.Plan, error)
+	SetManagedFeatures(keys []string)
+}
+
+// Status is the outcome of the most recent sync.
+type Status struct {
+	Dir      string   `json:"dir"`
+	Mode     Mode     `json:"mode"`
+	Prune    bool     `json:"prune"`
+	Revision string   `json:"revision,omitempty"`
+	Files    []string `json:"files"`
+	// Managed lists the flag keys of the features the manifests declare.
+	Managed []string `json:"managed"`
+	// InSync is true when the server matched the manifests after the last
+	// sync.
+	InSync bool `json:"in_sync"`
+	// Drift is the plan found by the last sync, before anything was applied.
+	Drift       *manifest.Plan `json:"drift,omitempty"`
+	LastSync    *time.Time     `json:"last_sync,omitempty"`
+	LastApplied *time.Time     `json:"last_applied,omitempty"`
+	LastError   string         `json:"last_error,omitempty"`
+}
+
+type Syncer struct {
+	cfg        Config
+	reconciler Reconciler
+	trigger    chan struct{}
+
+	// syncMu serialises syncs; mu guards status.
+	syncMu sync.Mutex
+	mu     sync.RWMutex
+	status Status
+}
+
+func NewSyncer(cfg Config, reconciler Reconciler) *Syncer {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 30 * time.Second
+	}
+	if cfg.Mode == "" {
+		cfg.Mode = ModeReconcile
+	}
+	return &Syncer{
+		cfg:        cfg,
+		reconciler: reconciler,
+		trigger:    make(chan struct{}, 1),
+		status: Status{
+			Dir:     cfg.Dir,
+			Mode:    cfg.Mode,
+			Prune:   cfg.Prune,
+			Files:   []string{},
+			Managed: []string{},
+		},
+	}
+}
+
+// Run syncs immediately and then every interval, or sooner when Trigger is
+// called, until ctx is cancelled.
+func (s *Syncer) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Sync(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "gitops sync failed", "dir", s.cfg.Dir, logging.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.trigger:
+		}
+	}
+}
+
+// Trigger asks Run to sync now, e.g. from a webhook after a git pull.
+func (s *Syncer) Trigger() {
+	select {
+	case s.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Sync loads the manifest directory, locks the features it declares, plans
+// it against the server and, in reconcile mode, applies the plan.
+func (s *Syncer) Sync(ctx context.Context) (Status, error) {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	err := s.sync(ctx)
+
+	s.mu.Lock()
+	now := time.Now()
+	s.status.LastSync = &now
+	s.status.LastError = ""
+	if err != nil {
+		s.status.LastError = err.Error()
+		s.status.InSync = false
+	}
+	status := s.status
+	s.mu.Unlock()
+
+	return status, err
+}
+
+func (s *Syncer) sync(ctx context.Context) error {
+	m, files, revision, err := LoadDir(s.cfg.Dir)
+	if err != nil {
+		// Keep the previous lock: a broken checkout must not unlock flags.
+		return err
+	}
+
+	managed := make([]string, len(m.Features))
+	for i, f := range m.Features {
+		managed[i] = f.FeatureKey()
+	}
+	sort.Strings(managed)
+	s.reconciler.SetManagedFeatures(managed)
+
+	s.mu.Lock()
+	s.status.Files = files
+	s.status.Revision = revision
+	s.status.Managed = managed
+	s.mu.Unlock()
+
+	opts := services.ManifestOptions{Prune: s.cfg.Prune, Sync: true}
+	plan, err := s.reconciler.PlanManifest(ctx, m, opts)
+	if err != nil {
+		return fmt.Errorf("failed to plan manifests: %w", err)
+	}
+
+	s.mu.Lock()
+	s.status.InSync = plan.Empty()
+	s.status.Drift = nil
+	if !plan.Empty() {
+		s.status.Drift = plan
+	}
+	s.mu.Unlock()
+
+	if plan.Empty() || s.cfg.Mode != ModeReconcile {
+		return nil
+	}
+
+	if _, err := s.reconciler.ApplyManifest(ctx, m, opts); err != nil {
+		return fmt.Errorf("failed to apply manifests: %w", err)
+	}
+	slog.InfoContext(ctx, "gitops reconciled", "dir", s.cfg.Dir, "revision", revision)
+
+	s.mu.Lock()
+	now := time.Now()
+	s.status.InSync = true
+	s.status.LastApplied = &now
+	s.mu.Unlock()
+	return nil
+}
+
+// Status returns the outcome of the most recent sync.
+func (s *Syncer) Status() Status {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.status
+}
+
+// LoadDir reads every .yaml, .yml and .json file under dir, skipping hidden
+// files and directories such as .git, and merges them into one manifest.
+// The revision is a hash of the file names and contents.
+func LoadDir(dir string) (*manifest.Manifest, []string, string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml", ".json":
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if len(files) == 0 {
+		return nil, nil, "", fmt.Errorf("%w in %s", ErrNoManifests, dir)
+	}
+	sort.Strings(files)
+
+	merged := &manifest.Manifest{}
+	hash := sha256.New()
+	relative := make([]string, len(files))
+	for i, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			rel = path
+		}
+		relative[i] = rel
+		fmt.Fprintf(hash, "%s\x00%d\x00", rel, len(data))
+		hash.Write(data)
+
+		// References between files are checked once everything is merged.
+		m, err := manifest.Decode(data)
+		if err != nil {
+			return nil, nil, "", fmt.Errorf("%s: %w", rel, err)
+		}
+		merged.Features = append(merged.Features, m.Features...)
+		merged.Dependencies = append(merged.Dependencies, m.Dependencies...)
+	}
+
+	if err := merged.Validate(); err != nil {
+		return nil, nil, "", err
+	}
+	return merged, relative, hex.EncodeToString(hash.Sum(nil))[:12], nil
+}
@@ -0,0 +1,220 @@
+package gitops
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"feature-flags/internal/manifest"
+	"feature-flags/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeReconciler keeps the server state as a manifest. Applying replaces it.
+type fakeReconciler struct {
+	mu      sync.Mutex
+	state   map[string]manifest.Feature
+	managed []string
+	applies int
+}
+
+func newFakeReconciler() *fakeReconciler {
+	return &fakeReconciler{state: make(map[string]manifest.Feature)}
+}
+
+func (f *fakeReconciler) PlanManifest(_ context.Context, m *manifest.Manifest, opts services.ManifestOptions) (*manifest.Plan, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.plan(m, opts), nil
+}
+
+func (f *fakeReconciler) plan(m *manifest.Manifest, opts services.ManifestOptions) *manifest.Plan {
+	plan := manifest.NewPlan()
+	declared := make(map[string]bool)
+	for _, want := range m.Features {
+		declared[want.Name] = true
+		have, ok := f.state[want.Name]
+		switch {
+		case !ok:
+			plan.Creates = append(plan.Creates, manifest.FeatureChange{Name: want.Name})
+		case have.Enabled != want.Enabled:
+			plan.Updates = append(plan.Updates, manifest.FeatureChange{Name: want.Name})
+		}
+	}
+	if opts.Prune {
+		for name := range f.state {
+			if !declared[name] {
+				plan.Deletes = append(plan.Deletes, manifest.FeatureChange{Name: name})
+			}
+		}
+	}
+	return plan
+}
+
+func (f *fakeReconciler) ApplyManifest(_ context.Context, m *manifest.Manifest, opts services.ManifestOptions) (*manifest.Plan, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	plan := f.plan(m, opts)
+	for _, want := range m.Features {
+		f.state[want.Name] = want
+	}
+	for _, c := range plan.Deletes {
+		delete(f.state, c.Name)
+	}
+	f.applies++
+	plan.Applied = true
+	return plan, nil
+}
+
+func (f *fakeReconciler) SetManagedFeatures(names []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.managed = names
+}
+
+func (f *fakeReconciler) enabled(name string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.state[name].Enabled
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+const checkoutManifest = `
+features:
+  - name: checkout
+    type: basic
+    enabled: true
+`
+
+const oneClickManifest = `
+features:
+  - name: one-click
+    type: premium
+    enabled: true
+dependencies:
+  - parent: checkout
+    child: one-click
+`
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "checkout.yaml"), checkoutManifest)
+	writeFile(t, filepath.Join(dir, "teams", "payments.yml"), oneClickManifest)
+	writeFile(t, filepath.Join(dir, ".git", "config.yaml"), "not: a manifest")
+	writeFile(t, filepath.Join(dir, "README.md"), "# flags")
+
+	m, files, revision, err := LoadDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"checkout.yaml", filepath.Join("teams", "payments.yml")}, files)
+	assert.Len(t, m.Features, 2)
+	assert.Equal(t, []manifest.Dependency{{Parent: "checkout", Child: "one-click"}}, m.Dependencies)
+	assert.Len(t, revision, 12)
+
+	// The revision follows the content.
+	writeFile(t, filepath.Join(dir, "checkout.yaml"), checkoutManifest+"    protected: true\n")
+	_, _, changed, err := LoadDir(dir)
+	require.NoError(t, err)
+	assert.NotEqual(t, revision, changed)
+
+	// Features declared in two files are rejected after merging.
+	writeFile(t, filepath.Join(dir, "dup.json"), `{"features": [{"name": "checkout", "type": "basic"}]}`)
+	_, _, _, err = LoadDir(dir)
+	assert.ErrorIs(t, err, manifest.ErrInvalid)
+
+	_, _, _, err = LoadDir(t.TempDir())
+	assert.ErrorIs(t, err, ErrNoManifests)
+}
+
+func TestSyncer_Reconcile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "flags.yaml"), checkoutManifest)
+
+	reconciler := newFakeReconciler()
+	syncer := NewSyncer(Config{Dir: dir}, reconciler)
+
+	status, err := syncer.Sync(context.Background())
+	require.NoError(t, err)
+	assert.True(t, status.InSync)
+	require.NotNil(t, status.Drift)
+	assert.Equal(t, "checkout", status.Drift.Creates[0].Name)
+	assert.NotNil(t, status.LastApplied)
+	assert.Equal(t, []string{"checkout"}, reconciler.managed)
+
+	// Nothing to do on the next pass.
+	status, err = syncer.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, status.Drift)
+	assert.Equal(t, 1, reconciler.applies)
+
+	// Drift on the server is corrected.
+	reconciler.state["checkout"] = manifest.Feature{Name: "checkout", Enabled: false}
+	status, err = syncer.Sync(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, status.Drift)
+	assert.Len(t, status.Drift.Updates, 1)
+	assert.True(t, reconciler.enabled("checkout"))
+
+	// A broken manifest keeps the previous lock and reports the error.
+	writeFile(t, filepath.Join(dir, "flags.yaml"), "features: [")
+	status, err = syncer.Sync(context.Background())
+	require.Error(t, err)
+	assert.False(t, status.InSync)
+	assert.NotEmpty(t, status.LastError)
+	assert.Equal(t, []string{"checkout"}, reconciler.managed)
+}
+
+func TestSyncer_DetectOnly(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "flags.yaml"), checkoutManifest)
+
+	reconciler := newFakeReconciler()
+	reconciler.state["legacy"] = manifest.Feature{Name: "legacy"}
+	syncer := NewSyncer(Config{Dir: dir, Mode: ModeDetect, Prune: true}, reconciler)
+
+	status, err := syncer.Sync(context.Background())
+	require.NoError(t, err)
+	assert.False(t, status.InSync)
+	require.NotNil(t, status.Drift)
+	assert.Len(t, status.Drift.Creates, 1)
+	assert.Len(t, status.Drift.Deletes, 1)
+	assert.Equal(t, 0, reconciler.applies)
+	assert.Nil(t, status.LastApplied)
+}
+
+func TestSyncer_RunPollsDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "flags.yaml"), checkoutManifest)
+
+	reconciler := newFakeReconciler()
+	syncer := NewSyncer(Config{Dir: dir, Interval: 10 * time.Millisecond}, reconciler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		syncer.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	require.Eventually(t, func() bool { return reconciler.enabled("checkout") }, time.Second, 5*time.Millisecond)
+
+	writeFile(t, filepath.Join(dir, "payments.yaml"), oneClickManifest)
+	require.Eventually(t, func() bool { return reconciler.enabled("one-click") }, time.Second, 5*time.Millisecond)
+
+	status := syncer.Status()
+	assert.Equal(t, []string{"checkout", "one-click"}, status.Managed)
+	assert.Equal(t, []string{"flags.yaml", "payments.yaml"}, status.Files)
+}
@@ -0,0 +1,239 @@
+package grpcserver
+
+import (
+	"encoding/json"
+	"feature-flags/internal/evaluation"
+	"feature-flags/internal/models"
+
+	pb "feature-flags/api/featureflags/v1"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toFeature(f *models.Feature) *pb.Feature {
+	return &pb.Feature{
+		Id:        f.ID.Hex(),
+		Key:       f.Key,
+		Name:      f.Name,
+		Type:      string(f.Type),
+		Enabled:   f.IsEnabled,
+		Protected: f.Protected,
+		Targeting: toTargeting(&f.Targeting),
+		Metadata:  toMetadata(&f.Metadata),
+		Version:   f.Version,
+		CreatedBy: f.CreatedBy,
+		Overrides: toOverrides(f.Overrides),
+		CreatedAt: timestamppb.New(f.CreatedAt),
+		UpdatedAt: timestamppb.New(f.UpdatedAt),
+	}
+}
+
+func toFeatures(features []*models.Feature) []*pb.Feature {
+	out := make([]*pb.Feature, len(features))
+	for i, f := range features {
+		out[i] = toFeature(f)
+	}
+	return out
+}
+
+func toTargeting(t *models.Targeting) *pb.Targeting {
+	if t == nil {
+		return nil
+	}
+	out := &pb.Targeting{DefaultVariant: t.DefaultVariant, OffVariant: t.OffVariant}
+	for _, rule := range t.Rules {
+		out.Rules = append(out.Rules, &pb.TargetingRule{
+			Attribute: rule.Attribute,
+			Operator:  string(rule.Operator),
+			Values:    rule.Values,
+		})
+	}
+	for _, variant := range t.Variants {
+		out.Variants = append(out.Variants, &pb.Variant{
+			Name:   variant.Name,
+			Value:  toValue(variant.Value),
+			Weight: int32(variant.Weight),
+		})
+	}
+	return out
+}
+
+func fromTargeting(t *pb.Targeting) models.Targeting {
+	var out models.Targeting
+	if t == nil {
+		return out
+	}
+	out.DefaultVariant = t.DefaultVariant
+	out.OffVariant = t.OffVariant
+	for _, rule := range t.Rules {
+		out.Rules = append(out.Rules, models.TargetingRule{
+			Attribute: rule.Attribute,
+			Operator:  models.RuleOperator(rule.Operator),
+			Values:    rule.Values,
+		})
+	}
+	for _, variant := range t.Variants {
+		out.Variants = append(out.Variants, models.Variant{
+			Name:   variant.Name,
+			Value:  variant.Value.AsInterface(),
+			Weight: int(variant.Weight),
+		})
+	}
+	return out
+}
+
+func toMetadata(m *models.Metadata) *pb.Metadata {
+	out := &pb.Metadata{
+		Description: m.Description,
+		Tags:        m.Tags,
+		Owner:       m.Owner,
+		Maintainer:  m.Maintainer,
+	}
+	for _, link := range m.Links {
+		out.Links = append(out.Links, &pb.Link{Title: link.Title, Url: link.URL})
+	}
+	if m.Attributes != nil {
+		out.Attributes, _ = structpb.NewStruct(m.Attributes)
+	}
+	return out
+}
+
+func fromMetadata(m *pb.Metadata) models.Metadata {
+	var out models.Metadata
+	if m == nil {
+		return out
+	}
+	out.Description = m.Description
+	out.Tags = m.Tags
+	out.Owner = m.Owner
+	out.Maintainer = m.Maintainer
+	for _, link := range m.Links {
+		out.Links = append(out.Links, models.Link{Title: link.Title, URL: link.Url})
+	}
+	if m.Attributes != nil {
+		out.Attributes = m.Attributes.AsMap()
+	}
+	return out
+}
+
+func toOverrides(overrides []models.Override) []*pb.Override {
+	var out []*pb.Override
+	for _, o := range overrides {
+		out = append(out, &pb.Override{
+			Attribute:          o.Attribute,
+			Value:              o.Value,
+			Action:             string(o.Action),
+			Variant:            o.Variant,
+			BypassDependencies: o.BypassDependencies,
+			Reason:             o.Reason,
+			CreatedBy:          o.CreatedBy,
+			CreatedAt:          timestamppb.New(o.CreatedAt),
+		})
+	}
+	return out
+}
+
+// fromOverrides converts overrides to add. Their creator and creation time
+// are set by the service.
+func fromOverrides(overrides []*pb.Override) []models.Override {
+	out := make([]models.Override, len(overrides))
+	for i, o := range overrides {
+		out[i] = models.Override{
+			Attribute:          o.Attribute,
+			Value:              o.Value,
+			Action:             models.OverrideAction(o.Action),
+			Variant:            o.Variant,
+			BypassDependencies: o.BypassDependencies,
+			Reason:             o.Reason,
+		}
+	}
+	return out
+}
+
+func toChangeRequest(cr *models.ChangeRequest) *pb.ChangeRequest {
+	out := &pb.ChangeRequest{
+		Id:            cr.ID.Hex(),
+		Action:        string(cr.Action),
+		FeatureId:     cr.FeatureID.Hex(),
+		Targeting:     toTargeting(cr.Targeting),
+		Status:        string(cr.Status),
+		RequestedBy:   cr.RequestedBy,
+		ReviewedBy:    cr.ReviewedBy,
+		ReviewComment: cr.ReviewComment,
+		CreatedAt:     timestamppb.New(cr.CreatedAt),
+		UpdatedAt:     timestamppb.New(cr.UpdatedAt),
+		Overrides:     toOverrides(cr.Overrides),
+	}
+	if cr.ChildID != nil {
+		out.ChildId = cr.ChildID.Hex()
+	}
+	if cr.ReviewedAt != nil {
+		out.ReviewedAt = timestamppb.New(*cr.ReviewedAt)
+	}
+	for _, c := range cr.Diff.Features {
+		out.Changes = append(out.Changes, &pb.FeatureChange{
+			FeatureId: c.FeatureID.Hex(),
+			Name:      c.Name,
+			Field:     c.Field,
+			From:      toValue(c.From),
+			To:        toValue(c.To),
+		})
+	}
+	for _, d := range cr.Diff.Dependencies {
+		out.DependencyChanges = append(out.DependencyChanges, toDependency(d.ParentID, d.ChildID))
+	}
+	return out
+}
+
+func toDependency(parentID, childID primitive.ObjectID) *pb.Dependency {
+	return &pb.Dependency{ParentId: parentID.Hex(), ChildId: childID.Hex()}
+}
+
+func toResult(r evaluation.Result) *pb.EvaluationResult {
+	out := &pb.EvaluationResult{
+		Key:          r.Key,
+		Variant:      r.Variant,
+		Reason:       string(r.Reason),
+		ErrorCode:    string(r.ErrorCode),
+		ErrorDetails: r.ErrorDetails,
+	}
+	if r.Value != nil {
+		out.Value = toValue(r.Value)
+	}
+	if r.Metadata != nil {
+		out.Metadata, _ = structpb.NewStruct(r.Metadata)
+	}
+	return out
+}
+
+func toResults(results []evaluation.Result) *pb.EvaluateAllResponse {
+	out := &pb.EvaluateAllResponse{Results: make([]*pb.EvaluationResult, len(results))}
+	for i, r := range results {
+		out.Results[i] = toResult(r)
+	}
+	return out
+}
+
+func fromContext(ctx *structpb.Struct) evaluation.Context {
+	if ctx == nil {
+		return evaluation.Context{}
+	}
+	return evaluation.Context(ctx.AsMap())
+}
+
+// toValue converts through JSON, so anything the REST API can encode,
+// such as the targeting in a change request diff, converts the same way.
+func toValue(v interface{}) *structpb.Value {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return structpb.NewNullValue()
+	}
+	var value structpb.Value
+	if err := protojson.Unmarshal(data, &value); err != nil {
+		return structpb.NewNullValue()
+	}
+	return &value
+}
@@ -0,0 +1,46 @@
+package grpcserver
+
+import (
+	"testing"
+
+	"feature-flags/internal/evaluation"
+	"feature-flags/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTargetingRoundTrip(t *testing.T) {
+	targeting := models.Targeting{
+		Rules: []models.TargetingRule{{Attribute: "country", Operator: models.RuleOperatorIn, Values: []string{"DE"}}},
+		Variants: []models.Variant{
+			{Name: "grid", Value: map[string]interface{}{"columns": 3.0}, Weight: 1},
+			{Name: "list", Value: "list", Weight: 3},
+		},
+		OffVariant: "list",
+	}
+	assert.Equal(t, targeting, fromTargeting(toTargeting(&targeting)))
+	assert.Equal(t, models.Targeting{}, fromTargeting(nil))
+}
+
+func TestMetadataRoundTrip(t *testing.T) {
+	metadata := models.Metadata{
+		Description: "New checkout flow",
+		Tags:        []string{"checkout", "q3"},
+		Owner:       "payments",
+		Maintainer:  "payments@example.com",
+		Links:       []models.Link{{Title: "Epic", URL: "https://example.com/PAY-1"}},
+		Attributes:  map[string]interface{}{"jira_project": "PAY", "revenue_impacting": true},
+	}
+	assert.Equal(t, metadata, fromMetadata(toMetadata(&metadata)))
+	assert.Equal(t, models.Metadata{}, fromMetadata(nil))
+}
+
+func TestToResult(t *testing.T) {
+	r := toResult(evaluation.Result{Key: "theme", Reason: evaluation.ReasonDisabled, Metadata: map[string]interface{}{"disabledBy": "checkout"}})
+	assert.Nil(t, r.Value)
+	assert.Equal(t, "DISABLED", r.Reason)
+	assert.Equal(t, "checkout", r.Metadata.AsMap()["disabledBy"])
+
+	r = toResult(evaluation.Result{Key: "limit", Value: int32(10), Reason: evaluation.ReasonStatic})
+	assert.Equal(t, 10.0, r.Value.GetNumberValue())
+}
@@ -0,0 +1,493 @@
+// Package grpcserver serves the gRPC API defined in api/featureflags/v1 on
+// top of the same FeatureService as the REST handlers.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"feature-flags/internal/auth"
+	"feature-flags/internal/logging"
+	"feature-flags/internal/models"
+	"feature-flags/internal/services"
+	"log/slog"
+	"sync"
+	"time"
+
+	pb "feature-flags/api/featureflags/v1"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultWatchInterval is how often WatchFlags re-evaluates flags.
+const DefaultWatchInterval = 5 * time.Second
+
+// MethodRoles is the role each RPC requires, matching the REST routes.
+var MethodRoles = map[string]auth.Role{
+	pb.FeatureFlags_Evaluate_FullMethodName:             auth.RoleViewer,
+	pb.FeatureFlags_EvaluateAll_FullMethodName:          auth.RoleViewer,
+	pb.FeatureFlags_WatchFlags_FullMethodName:           auth.RoleViewer,
+	pb.FeatureFlags_GetFeature_FullMethodName:           auth.RoleViewer,
+	pb.FeatureFlags_ListFeatures_FullMethodName:         auth.RoleViewer,
+	pb.FeatureFlags_GetDependencies_FullMethodName:      auth.RoleViewer,
+	pb.FeatureFlags_ListDependencies_FullMethodName:     auth.RoleViewer,
+	pb.FeatureFlags_GetEntitlements_FullMethodName:      auth.RoleViewer,
+	pb.FeatureFlags_CreateFeature_FullMethodName:        auth.RoleEditor,
+	pb.FeatureFlags_EnableFeature_FullMethodName:        auth.RoleEditor,
+	pb.FeatureFlags_DisableFeature_FullMethodName:       auth.RoleEditor,
+	pb.FeatureFlags_SetTargeting_FullMethodName:         auth.RoleEditor,
+	pb.FeatureFlags_SetMetadata_FullMethodName:          auth.RoleEditor,
+	pb.FeatureFlags_AddOverrides_FullMethodName:         auth.RoleEditor,
+	pb.FeatureFlags_RemoveOverrides_FullMethodName:      auth.RoleEditor,
+	pb.FeatureFlags_AddDependency_FullMethodName:        auth.RoleEditor,
+	pb.FeatureFlags_RemoveDependency_FullMethodName:     auth.RoleEditor,
+	pb.FeatureFlags_SetProtected_FullMethodName:         auth.RoleAdmin,
+	pb.FeatureFlags_ListChangeRequests_FullMethodName:   auth.RoleViewer,
+	pb.FeatureFlags_GetChangeRequest_FullMethodName:     auth.RoleViewer,
+	pb.FeatureFlags_ApproveChangeRequest_FullMethodName: auth.RoleAdmin,
+	pb.FeatureFlags_RejectChangeRequest_FullMethodName:  auth.RoleAdmin,
+}
+
+type Server struct {
+	pb.UnimplementedFeatureFlagsServer
+	featureService *services.FeatureService
+	watchInterval  time.Duration
+	// closed is closed by Close to end WatchFlags streams.
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewServer returns the gRPC service implementation. watchInterval is how
+// often WatchFlags streams re-evaluate; zero means DefaultWatchInterval.
+func NewServer(featureService *services.FeatureService, watchInterval time.Duration) *Server {
+	if watchInterval <= 0 {
+		watchInterval = DefaultWatchInterval
+	}
+	return &Server{
+		featureService: featureService,
+		watchInterval:  watchInterval,
+		closed:         make(chan struct{}),
+	}
+}
+
+// Close ends every open WatchFlags stream with codes.Unavailable, so clients
+// reconnect to another instance. Streams only end when their client leaves
+// otherwise, so call it before grpc.Server.GracefulStop, which waits for
+// them.
+func (s *Server) Close() {
+	s.closeOnce.Do(func() { close(s.closed) })
+}
+
+// NewGRPCServer returns a grpc.Server with the service and the auth
+// interceptors registered, plus any extra options such as TLS credentials.
+// validator is nil when authentication is off.
+func NewGRPCServer(server *Server, validator *auth.Validator, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(
+			logging.UnaryServerInterceptor(),
+			auth.UnaryServerInterceptor(validator, MethodRoles),
+		),
+		grpc.ChainStreamInterceptor(
+			logging.StreamServerInterceptor(),
+			auth.StreamServerInterceptor(validator, MethodRoles),
+		),
+	)
+	s := grpc.NewServer(opts...)
+	pb.RegisterFeatureFlagsServer(s, server)
+	return s
+}
+
+func (s *Server) Evaluate(ctx context.Context, req *pb.EvaluateRequest) (*pb.EvaluationResult, error) {
+	result, err := s.featureService.Evaluate(ctx, req.Key, fromContext(req.Context))
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toResult(result), nil
+}
+
+func (s *Server) EvaluateAll(ctx context.Context, req *pb.EvaluateAllRequest) (*pb.EvaluateAllResponse, error) {
+	results, err := s.featureService.EvaluateAll(ctx, fromContext(req.Context))
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toResults(results), nil
+}
+
+// WatchFlags polls rather than subscribing to writes, so changes made
+// through any server instance, or directly in the database, are picked up.
+// The stream ends when the client leaves or Close is called.
+func (s *Server) WatchFlags(req *pb.WatchFlagsRequest, stream pb.FeatureFlags_WatchFlagsServer) error {
+	ctx := stream.Context()
+	evalCtx := fromContext(req.Context)
+
+	ticker := time.NewTicker(s.watchInterval)
+	defer ticker.Stop()
+
+	var last *pb.EvaluateAllResponse
+	for {
+		results, err := s.featureService.EvaluateAll(ctx, evalCtx)
+		if err != nil {
+			return toStatus(ctx, err)
+		}
+		if current := toResults(results); !proto.Equal(current, last) {
+			if err := stream.Send(current); err != nil {
+				return err
+			}
+			last = current
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.closed:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Server) GetFeature(ctx context.Context, req *pb.GetFeatureRequest) (*pb.Feature, error) {
+	id, err := s.featureID(ctx, "", req.Id, req.Key)
+	if err != nil {
+		return nil, err
+	}
+	feature, err := s.featureService.GetFeatureStatus(ctx, id)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toFeature(feature), nil
+}
+
+func (s *Server) ListFeatures(ctx context.Context, req *pb.ListFeaturesRequest) (*pb.ListFeaturesResponse, error) {
+	filter := models.FeatureFilter{
+		Type:  models.FeatureType(req.GetType()),
+		Tags:  req.GetTags(),
+		Owner: req.GetOwner(),
+	}
+	if req.Enabled != nil {
+		enabled := req.GetEnabled()
+		filter.Enabled = &enabled
+	}
+
+	features, err := s.featureService.ListFeatures(ctx, filter)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &pb.ListFeaturesResponse{Features: toFeatures(features)}, nil
+}
+
+func (s *Server) GetDependencies(ctx context.Context, req *pb.GetDependenciesRequest) (*pb.GetDependenciesResponse, error) {
+	id, err := objectID("feature id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+	parents, children, err := s.featureService.GetDependencies(ctx, id)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &pb.GetDependenciesResponse{ParentIds: hexIDs(parents), ChildIds: hexIDs(children)}, nil
+}
+
+func (s *Server) ListDependencies(ctx context.Context, _ *pb.ListDependenciesRequest) (*pb.ListDependenciesResponse, error) {
+	dependencies, err := s.featureService.ListDependencies(ctx)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	resp := &pb.ListDependenciesResponse{}
+	for _, d := range dependencies {
+		resp.Dependencies = append(resp.Dependencies, toDependency(d.ParentID, d.ChildID))
+	}
+	return resp, nil
+}
+
+func (s *Server) GetEntitlements(ctx context.Context, req *pb.GetEntitlementsRequest) (*pb.GetEntitlementsResponse, error) {
+	entitlements, err := s.featureService.Entitlements(ctx, req.TenantId, req.Plan)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &pb.GetEntitlementsResponse{
+		TenantId: entitlements.TenantID,
+		Plan:     entitlements.Plan,
+		Override: entitlements.Override,
+		Features: toFeatures(entitlements.Features),
+	}, nil
+}
+
+func (s *Server) CreateFeature(ctx context.Context, req *pb.CreateFeatureRequest) (*pb.Feature, error) {
+	if req.Name == "" || req.Type == "" {
+		return nil, status.Error(codes.InvalidArgument, "name and type are required")
+	}
+	feature := &models.Feature{
+		Key:       req.Key,
+		Name:      req.Name,
+		Type:      models.FeatureType(req.Type),
+		IsEnabled: req.Enabled,
+		Protected: req.Protected,
+		Targeting: fromTargeting(req.Targeting),
+		Metadata:  fromMetadata(req.Metadata),
+	}
+	if err := s.featureService.CreateFeature(ctx, feature); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toFeature(feature), nil
+}
+
+func (s *Server) EnableFeature(ctx context.Context, req *pb.EnableFeatureRequest) (*pb.MutationResponse, error) {
+	id, err := s.featureID(ctx, "", req.Id, req.Key)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.featureService.EnableFeature(ctx, id); err != nil {
+		return pending(ctx, err)
+	}
+	feature, err := s.featureService.GetFeatureStatus(ctx, id)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &pb.MutationResponse{Result: &pb.MutationResponse_Feature{Feature: toFeature(feature)}}, nil
+}
+
+func (s *Server) DisableFeature(ctx context.Context, req *pb.DisableFeatureRequest) (*pb.DisableFeatureResponse, error) {
+	id, err := s.featureID(ctx, "", req.Id, req.Key)
+	if err != nil {
+		return nil, err
+	}
+	if req.DryRun {
+		cascade, err := s.featureService.PreviewDisable(ctx, id)
+		if err != nil {
+			return nil, toStatus(ctx, err)
+		}
+		return &pb.DisableFeatureResponse{Cascade: toFeatures(cascade)}, nil
+	}
+	if err := s.featureService.DisableFeature(ctx, id); err != nil {
+		var p *services.PendingChangeError
+		if errors.As(err, &p) {
+			return &pb.DisableFeatureResponse{ChangeRequest: toChangeRequest(p.ChangeRequest)}, nil
+		}
+		return nil, toStatus(ctx, err)
+	}
+	return &pb.DisableFeatureResponse{}, nil
+}
+
+func (s *Server) SetTargeting(ctx context.Context, req *pb.SetTargetingRequest) (*pb.MutationResponse, error) {
+	id, err := objectID("feature id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+	feature, err := s.featureService.SetTargeting(ctx, id, fromTargeting(req.Targeting))
+	if err != nil {
+		return pending(ctx, err)
+	}
+	return &pb.MutationResponse{Result: &pb.MutationResponse_Feature{Feature: toFeature(feature)}}, nil
+}
+
+func (s *Server) SetMetadata(ctx context.Context, req *pb.SetMetadataRequest) (*pb.Feature, error) {
+	id, err := objectID("feature id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+	feature, err := s.featureService.SetMetadata(ctx, id, fromMetadata(req.Metadata))
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toFeature(feature), nil
+}
+
+func (s *Server) AddOverrides(ctx context.Context, req *pb.AddOverridesRequest) (*pb.MutationResponse, error) {
+	id, err := objectID("feature id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+	feature, err := s.featureService.AddOverrides(ctx, id, fromOverrides(req.Overrides))
+	if err != nil {
+		return pending(ctx, err)
+	}
+	return &pb.MutationResponse{Result: &pb.MutationResponse_Feature{Feature: toFeature(feature)}}, nil
+}
+
+func (s *Server) RemoveOverrides(ctx context.Context, req *pb.RemoveOverridesRequest) (*pb.MutationResponse, error) {
+	id, err := objectID("feature id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]models.OverrideKey, len(req.Overrides))
+	for i, key := range req.Overrides {
+		keys[i] = models.OverrideKey{Attribute: key.Attribute, Value: key.Value}
+	}
+	feature, err := s.featureService.RemoveOverrides(ctx, id, keys)
+	if err != nil {
+		return pending(ctx, err)
+	}
+	return &pb.MutationResponse{Result: &pb.MutationResponse_Feature{Feature: toFeature(feature)}}, nil
+}
+
+func (s *Server) AddDependency(ctx context.Context, req *pb.AddDependencyRequest) (*pb.AddDependencyResponse, error) {
+	parentID, err := s.featureID(ctx, "parent_", req.ParentId, req.ParentKey)
+	if err != nil {
+		return nil, err
+	}
+	childID, err := s.featureID(ctx, "child_", req.ChildId, req.ChildKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.featureService.AddChild(ctx, parentID, childID); err != nil {
+		var p *services.PendingChangeError
+		if errors.As(err, &p) {
+			return &pb.AddDependencyResponse{ChangeRequest: toChangeRequest(p.ChangeRequest)}, nil
+		}
+		return nil, toStatus(ctx, err)
+	}
+	return &pb.AddDependencyResponse{}, nil
+}
+
+func (s *Server) RemoveDependency(ctx context.Context, req *pb.RemoveDependencyRequest) (*pb.RemoveDependencyResponse, error) {
+	parentID, err := objectID("parent_id", req.ParentId)
+	if err != nil {
+		return nil, err
+	}
+	childID, err := objectID("child_id", req.ChildId)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.featureService.RemoveChild(ctx, parentID, childID); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &pb.RemoveDependencyResponse{}, nil
+}
+
+func (s *Server) SetProtected(ctx context.Context, req *pb.SetProtectedRequest) (*pb.Feature, error) {
+	id, err := objectID("feature id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+	feature, err := s.featureService.SetProtected(ctx, id, req.Protected)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toFeature(feature), nil
+}
+
+func (s *Server) ListChangeRequests(ctx context.Context, req *pb.ListChangeRequestsRequest) (*pb.ListChangeRequestsResponse, error) {
+	requests, err := s.featureService.ListChangeRequests(ctx, models.ChangeRequestStatus(req.Status))
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	resp := &pb.ListChangeRequestsResponse{}
+	for _, cr := range requests {
+		resp.ChangeRequests = append(resp.ChangeRequests, toChangeRequest(cr))
+	}
+	return resp, nil
+}
+
+func (s *Server) GetChangeRequest(ctx context.Context, req *pb.GetChangeRequestRequest) (*pb.ChangeRequest, error) {
+	id, err := objectID("change request id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+	cr, err := s.featureService.GetChangeRequest(ctx, id)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toChangeRequest(cr), nil
+}
+
+func (s *Server) ApproveChangeRequest(ctx context.Context, req *pb.ReviewChangeRequestRequest) (*pb.ChangeRequest, error) {
+	id, err := objectID("change request id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+	cr, err := s.featureService.ApproveChangeRequest(ctx, id, req.Comment)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toChangeRequest(cr), nil
+}
+
+func (s *Server) RejectChangeRequest(ctx context.Context, req *pb.ReviewChangeRequestRequest) (*pb.ChangeRequest, error) {
+	id, err := objectID("change request id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+	cr, err := s.featureService.RejectChangeRequest(ctx, id, req.Comment)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toChangeRequest(cr), nil
+}
+
+// pending turns a *services.PendingChangeError into a successful response
+// carrying the change request; other errors become a status.
+func pending(ctx context.Context, err error) (*pb.MutationResponse, error) {
+	var p *services.PendingChangeError
+	if errors.As(err, &p) {
+		return &pb.MutationResponse{Result: &pb.MutationResponse_ChangeRequest{ChangeRequest: toChangeRequest(p.ChangeRequest)}}, nil
+	}
+	return nil, toStatus(ctx, err)
+}
+
+// toStatus maps service errors to the gRPC codes matching the REST status
+// codes. Unexpected errors are logged with their cause chain and the
+// request ID, and only reported as internal.
+func toStatus(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, services.ErrInvalidTargeting), errors.Is(err, services.ErrInvalidKey),
+		errors.Is(err, services.ErrInvalidMetadata), errors.Is(err, services.ErrInvalidFeatureType),
+		errors.Is(err, services.ErrInvalidPlan), errors.Is(err, services.ErrInvalidOverride):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, services.ErrDependencyExists), errors.Is(err, services.ErrFeatureExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, services.ErrConflict), errors.Is(err, services.ErrCycle), errors.Is(err, services.ErrParentDisabled),
+		errors.Is(err, services.ErrOverrideLimit):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, services.ErrSelfApproval), errors.Is(err, services.ErrReviewerRequired):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		slog.ErrorContext(ctx, "request failed", logging.Error(err))
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+// featureID returns the feature given by exactly one of the <prefix>id and
+// <prefix>key fields of a request.
+func (s *Server) featureID(ctx context.Context, prefix, id, key string) (primitive.ObjectID, error) {
+	switch {
+	case id != "" && key != "":
+		return primitive.NilObjectID, status.Errorf(codes.InvalidArgument, "give %sid or %skey, not both", prefix, prefix)
+	case key != "":
+		feature, err := s.featureService.GetFeatureByKey(ctx, key)
+		if err != nil {
+			return primitive.NilObjectID, toStatus(ctx, err)
+		}
+		return feature.ID, nil
+	}
+	field := prefix + "id"
+	if prefix == "" {
+		field = "feature id"
+	}
+	return objectID(field, id)
+}
+
+func objectID(field, hex string) (primitive.ObjectID, error) {
+	id, err := primitive.ObjectIDFromHex(hex)
+	if err != nil {
+		return id, status.Error(codes.InvalidArgument, "invalid "+field)
+	}
+	return id, nil
+}
+
+func hexIDs(ids []primitive.ObjectID) []string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = id.Hex()
+	}
+	return out
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"context"
+	"feature-flags/internal/models"
+	"feature-flags/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ChangeRequestHandler struct {
+	featureService *services.FeatureService
+}
+
+func NewChangeRequestHandler(featureService *services.FeatureService) *ChangeRequestHandler {
+	return &ChangeRequestHandler{
+		featureService: featureService,
+	}
+}
+
+type ReviewChangeRequestRequest struct {
+	Comment string `json:"comment"`
+}
+
+// ListChangeRequests godoc
+// @Summary List change requests
+// @Description List change requests for protected features, newest first
+// @Tags change-requests
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filter by status (pending, approved, rejected)"
+// @Success 200 {array} models.ChangeRequest
+// @Failure 500 {object} problem.Problem
+// @Router /api/change-requests [get]
+func (h *ChangeRequestHandler) ListChangeRequests(c *gin.Context) {
+	status := models.ChangeRequestStatus(c.Query("status"))
+
+	requests, err := h.featureService.ListChangeRequests(c.Request.Context(), status)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
+
+// GetChangeRequest godoc
+// @Summary Get a change request
+// @Description Get a change request and its diff by ID
+// @Tags change-requests
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Change request ID"
+// @Success 200 {object} models.ChangeRequest
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/change-requests/{id} [get]
+func (h *ChangeRequestHandler) GetChangeRequest(c *gin.Context) {
+	requestID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		respondBadRequest(c, "invalid change request id")
+		return
+	}
+
+	request, err := h.featureService.GetChangeRequest(c.Request.Context(), requestID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, request)
+}
+
+// ApproveChangeRequest godoc
+// @Summary Approve a change request
+// @Description Approve a pending change request and apply it. Requesters cannot approve their own requests.
+// @Tags change-requests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Change request ID"
+// @Param review body ReviewChangeRequestRequest false "Review comment"
+// @Success 200 {object} models.ChangeRequest
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/change-requests/{id}/approve [post]
+func (h *ChangeRequestHandler) ApproveChangeRequest(c *gin.Context) {
+	h.review(c, h.featureService.ApproveChangeRequest)
+}
+
+// RejectChangeRequest godoc
+// @Summary Reject a change request
+// @Description Reject a pending change request without applying it
+// @Tags change-requests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Change request ID"
+// @Param review body ReviewChangeRequestRequest false "Review comment"
+// @Success 200 {object} models.ChangeRequest
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/change-requests/{id}/reject [post]
+func (h *ChangeRequestHandler) RejectChangeRequest(c *gin.Context) {
+	h.review(c, h.featureService.RejectChangeRequest)
+}
+
+type reviewFunc func(ctx context.Context, id primitive.ObjectID, comment string) (*models.ChangeRequest, error)
+
+func (h *ChangeRequestHandler) review(c *gin.Context, fn reviewFunc) {
+	requestID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		respondBadRequest(c, "invalid change request id")
+		return
+	}
+
+	var req ReviewChangeRequestRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBadRequest(c, err.Error())
+			return
+		}
+	}
+
+	request, err := fn(c.Request.Context(), requestID, req.Comment)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, request)
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"feature-flags/internal/logging"
+	"feature-flags/internal/manifest"
+	"feature-flags/internal/problem"
+	"feature-flags/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// problemTypes maps service errors to problem types, most specific first.
+var problemTypes = []struct {
+	err    error
+	status int
+	name   string
+	title  string
+}{
+	{services.ErrCycle, http.StatusUnprocessableEntity, "dependency-cycle", "Dependency cycle"},
+	{services.ErrParentDisabled, http.StatusUnprocessableEntity, "parent-disabled", "Parent feature disabled"},
+	{services.ErrOverrideLimit, http.StatusUnprocessableEntity, "override-limit", "Too many overrides"},
+	{services.ErrDependencyExists, http.StatusConflict, "dependency-exists", "Dependency already exists"},
+	{services.ErrFeatureExists, http.StatusConflict, "feature-exists", "Feature key already exists"},
+	{services.ErrSegmentExists, http.StatusConflict, "segment-exists", "Segment key already exists"},
+	{services.ErrSegmentInUse, http.StatusConflict, "segment-in-use", "Segment in use"},
+	{services.ErrFeatureManaged, http.StatusConflict, "feature-managed", "Feature managed by GitOps sync"},
+	{services.ErrChangeRequestClosed, http.StatusConflict, "change-request-closed", "Change request no longer pending"},
+	{services.ErrRolloutState, http.StatusConflict, "rollout-state", "Rollout plan cannot make this change"},
+	{services.ErrManifestProtected, http.StatusConflict, "manifest-protected", "Manifest changes protected features"},
+	{services.ErrApprovalsDisabled, http.StatusConflict, "approvals-disabled", "Protection needs authentication"},
+	{services.ErrConcurrentChange, http.StatusConflict, "concurrent-change", "Changed concurrently"},
+	{services.ErrConflict, http.StatusConflict, "conflict", "Conflict"},
+	{services.ErrNotFound, http.StatusNotFound, "not-found", "Not found"},
+	{services.ErrInvalidTargeting, http.StatusBadRequest, "invalid-targeting", "Invalid targeting"},
+	{services.ErrInvalidKey, http.StatusBadRequest, "invalid-key", "Invalid key"},
+	{services.ErrInvalidMetadata, http.StatusBadRequest, "invalid-metadata", "Invalid metadata"},
+	{services.ErrInvalidFeatureType, http.StatusBadRequest, "invalid-feature-type", "Invalid feature type"},
+	{services.ErrInvalidPlan, http.StatusBadRequest, "invalid-plan", "Invalid plan"},
+	{services.ErrInvalidOverride, http.StatusBadRequest, "invalid-override", "Invalid override"},
+	{services.ErrInvalidSegment, http.StatusBadRequest, "invalid-segment", "Invalid segment"},
+	{services.ErrInvalidEvent, http.StatusBadRequest, "invalid-event", "Invalid event"},
+	{services.ErrInvalidExperiment, http.StatusBadRequest, "invalid-experiment", "Invalid experiment query"},
+	{services.ErrInvalidGuard, http.StatusBadRequest, "invalid-guard", "Invalid guard rule"},
+	{services.ErrInvalidSignal, http.StatusBadRequest, "invalid-signal", "Invalid signal"},
+	{services.ErrInvalidRollout, http.StatusBadRequest, "invalid-rollout", "Invalid rollout plan"},
+	{manifest.ErrInvalid, http.StatusBadRequest, "invalid-manifest", "Invalid manifest"},
+	{services.ErrSelfApproval, http.StatusForbidden, "self-approval", "Self-approval not allowed"},
+	{services.ErrReviewerRequired, http.StatusForbidden, "reviewer-required", "Reviewer required"},
+}
+
+// respondError responds to a service error. A *services.PendingChangeError
+// gets 202 with the change request; other errors get a problem response
+// with the status of their kind, or a 500 as from respondInternalError. The
+// problem for a *services.PartialApplyError lists the changes that landed
+// as "applied".
+func respondError(c *gin.Context, err error) {
+	var pending *services.PendingChangeError
+	if errors.As(err, &pending) {
+		c.JSON(http.StatusAccepted, pending.ChangeRequest)
+		return
+	}
+
+	p := toProblem(err)
+	if p == nil {
+		_ = c.Error(err)
+		p = internalProblem(c)
+	}
+	var partial *services.PartialApplyError
+	if errors.As(err, &partial) {
+		p.With("applied", partial.Applied)
+	}
+	problem.Write(c, p)
+}
+
+// toProblem returns the problem for a service error, or nil if err is not
+// caused by the request.
+func toProblem(err error) *problem.Problem {
+	for _, t := range problemTypes {
+		if !errors.Is(err, t.err) {
+			continue
+		}
+		p := problem.Typed(t.status, t.name, t.title, err.Error())
+
+		var cycle *services.CycleError
+		if errors.As(err, &cycle) {
+			p.With("cycle_path", hexIDs(cycle.Path))
+		}
+		var parentDisabled *services.ParentDisabledError
+		if errors.As(err, &parentDisabled) {
+			p.With("parent_ids", hexIDs(parentDisabled.ParentIDs))
+		}
+		var overrideLimit *services.OverrideLimitError
+		if errors.As(err, &overrideLimit) {
+			p.With("limit", overrideLimit.Limit).With("count", overrideLimit.Count)
+		}
+		var segmentInUse *services.SegmentInUseError
+		if errors.As(err, &segmentInUse) {
+			p.With("features", segmentInUse.Features)
+		}
+		return p
+	}
+	return nil
+}
+
+// respondInternalError responds with 500 and attaches err to the context so
+// the logging middleware records it once, with its cause chain and the
+// request ID.
+func respondInternalError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	problem.Write(c, internalProblem(c))
+}
+
+// internalProblem is the problem for an unexpected error. The error itself
+// may describe the database or other internals, so the detail only gives
+// the request ID to find it in the logs.
+func internalProblem(c *gin.Context) *problem.Problem {
+	detail := "internal error"
+	if id := c.Writer.Header().Get(logging.RequestIDHeader); id != "" {
+		detail += " (request ID " + id + ")"
+	}
+	return problem.New(http.StatusInternalServerError, detail)
+}
+
+func respondBadRequest(c *gin.Context, detail string) {
+	problem.Write(c, problem.New(http.StatusBadRequest, detail))
+}
+
+func hexIDs(ids []primitive.ObjectID) []string {
+	hex := make([]string, len(ids))
+	for i, id := range ids {
+		hex[i] = id.Hex()
+	}
+	return hex
+}
@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"feature-flags/internal/models"
+	"feature-flags/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExperimentHandler struct {
+	featureService *services.FeatureService
+}
+
+func NewExperimentHandler(featureService *services.FeatureService) *ExperimentHandler {
+	return &ExperimentHandler{
+		featureService: featureService,
+	}
+}
+
+type TrackConversionsRequest struct {
+	Conversions []models.Conversion `json:"conversions" binding:"required"`
+}
+
+type TrackConversionsResponse struct {
+	Accepted int `json:"accepted" example:"2"`
+}
+
+// TrackConversions godoc
+// @Summary Track conversion events
+// @Description Record that contexts completed a goal, e.g. a purchase. Conversions are matched to exposures by targeting key. Conversions without a timestamp get the current time; those timestamped more than 5 minutes in the future are rejected. At most 1000 may be sent at once.
+// @Tags experiments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param conversions body TrackConversionsRequest true "Conversions to record"
+// @Success 202 {object} TrackConversionsResponse
+// @Failure 400 {object} problem.Problem "Missing event or targeting key, future timestamp, or too many conversions"
+// @Failure 500 {object} problem.Problem
+// @Router /api/events/conversions [post]
+func (h *ExperimentHandler) TrackConversions(c *gin.Context) {
+	var req TrackConversionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.featureService.TrackConversions(c.Request.Context(), req.Conversions); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, TrackConversionsResponse{Accepted: len(req.Conversions)})
+}
+
+// GetExperimentResults godoc
+// @Summary Get experiment results
+// @Description Compare the conversion rates of a flag's variants for a conversion event. Only contexts assigned by a weighted split count, each with the variant of its first exposure; a context converted if it sent the event at or after that exposure. Every variant but the control gets the difference from the control, with its confidence interval and p-value.
+// @Tags experiments
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "Flag key"
+// @Param event query string true "Conversion event"
+// @Param control query string false "Control variant; defaults to the default variant, or the first"
+// @Param since query string false "Only events at or after this RFC 3339 time"
+// @Param until query string false "Only events before this RFC 3339 time"
+// @Param confidence query number false "Confidence level of the intervals" default(0.95)
+// @Success 200 {object} models.ExperimentResults
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem "Flag not found"
+// @Failure 500 {object} problem.Problem
+// @Router /api/experiments/{key}/results [get]
+func (h *ExperimentHandler) GetExperimentResults(c *gin.Context) {
+	since, until, ok := timeRange(c)
+	if !ok {
+		return
+	}
+	opts := services.ExperimentOptions{Control: c.Query("control"), Since: since, Until: until}
+	if value, ok := c.GetQuery("confidence"); ok {
+		confidence, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			respondBadRequest(c, "invalid confidence")
+			return
+		}
+		opts.Confidence = confidence
+	}
+
+	results, err := h.featureService.ExperimentResults(c.Request.Context(), c.Param("key"), c.Query("event"), opts)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// ExportEvents godoc
+// @Summary Export raw events
+// @Description Stream exposure or conversion events, oldest first, as CSV with a header row or as JSON Lines
+// @Tags experiments
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Security BearerAuth
+// @Param type query string true "Events to export" Enums(exposures, conversions)
+// @Param format query string false "Output format" Enums(csv, jsonl) default(csv)
+// @Param flag query string false "Only exposures to this flag"
+// @Param event query string false "Only conversions for this event"
+// @Param since query string false "Only events at or after this RFC 3339 time"
+// @Param until query string false "Only events before this RFC 3339 time"
+// @Success 200 {string} string "Events"
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/events/export [get]
+func (h *ExperimentHandler) ExportEvents(c *gin.Context) {
+	since, until, ok := timeRange(c)
+	if !ok {
+		return
+	}
+	filter := models.EventFilter{FlagKey: c.Query("flag"), Event: c.Query("event"), Since: since, Until: until}
+
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "jsonl" {
+		respondBadRequest(c, "format must be csv or jsonl")
+		return
+	}
+	kind := c.Query("type")
+
+	var export func(w eventWriter) error
+	switch kind {
+	case "exposures":
+		export = func(w eventWriter) error {
+			w.header("flag_key", "variant", "targeting_key", "reason", "timestamp")
+			return h.featureService.ExportExposures(c.Request.Context(), filter, func(e *models.Exposure) error {
+				return w.write(e, e.FlagKey, e.Variant, e.TargetingKey, e.Reason, formatTime(e.Timestamp))
+			})
+		}
+	case "conversions":
+		export = func(w eventWriter) error {
+			w.header("event", "targeting_key", "value", "timestamp")
+			return h.featureService.ExportConversions(c.Request.Context(), filter, func(e *models.Conversion) error {
+				return w.write(e, e.Event, e.TargetingKey, strconv.FormatFloat(e.Value, 'f', -1, 64), formatTime(e.Timestamp))
+			})
+		}
+	default:
+		respondBadRequest(c, "type must be exposures or conversions")
+		return
+	}
+
+	w := &streamWriter{c: c, format: format, filename: fmt.Sprintf("%s.%s", kind, format)}
+	err := export(w)
+	if err == nil && !w.started {
+		// No events: still send the headers, and the CSV header row.
+		err = w.start()
+	}
+	if w.csv != nil {
+		w.csv.Flush()
+		if err == nil {
+			err = w.csv.Error()
+		}
+	}
+	if err == nil {
+		return
+	}
+	if !w.started {
+		respondError(c, err)
+		return
+	}
+	// The status line is already sent; record the error and cut the
+	// response short so the client sees a truncated export.
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// eventWriter writes events as rows; v is the event for JSON Lines and
+// fields its columns for CSV.
+type eventWriter interface {
+	header(columns ...string)
+	write(v interface{}, fields ...string) error
+}
+
+// streamWriter sends the response headers on the first event, so errors
+// before any event is read still get a problem response.
+type streamWriter struct {
+	c        *gin.Context
+	format   string
+	filename string
+	columns  []string
+	started  bool
+	csv      *csv.Writer
+	json     *json.Encoder
+}
+
+func (w *streamWriter) header(columns ...string) {
+	w.columns = columns
+}
+
+func (w *streamWriter) start() error {
+	w.started = true
+	w.c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", w.filename))
+	if w.format == "jsonl" {
+		w.c.Header("Content-Type", "application/x-ndjson")
+		w.c.Status(http.StatusOK)
+		w.json = json.NewEncoder(w.c.Writer)
+		return nil
+	}
+	w.c.Header("Content-Type", "text/csv; charset=utf-8")
+	w.c.Status(http.StatusOK)
+	w.csv = csv.NewWriter(w.c.Writer)
+	return w.csv.Write(w.columns)
+}
+
+func (w *streamWriter) write(v interface{}, fields ...string) error {
+	if !w.started {
+		if err := w.start(); err != nil {
+			return err
+		}
+	}
+	if w.json != nil {
+		return w.json.Encode(v)
+	}
+	return w.csv.Write(fields)
+}
+
+// timeRange parses the since and until query parameters, responding 400
+// when either is not an RFC 3339 time.
+func timeRange(c *gin.Context) (since, until time.Time, ok bool) {
+	for _, param := range []struct {
+		name string
+		dst  *time.Time
+	}{{"since", &since}, {"until", &until}} {
+		value := c.Query(param.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			respondBadRequest(c, fmt.Sprintf("invalid %s: must be an RFC 3339 time", param.name))
+			return time.Time{}, time.Time{}, false
+		}
+		*param.dst = parsed
+	}
+	return since, until, true
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
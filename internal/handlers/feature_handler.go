@@ -4,10 +4,10 @@ import (
 	"feature-flags/internal/models"
 	"feature-flags/internal/services"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type FeatureHandler struct {
@@ -21,19 +21,50 @@ func NewFeatureHandler(featureService *services.FeatureService) *FeatureHandler
 }
 
 type CreateFeatureRequest struct {
+	// Key defaults to one derived from Name.
+	Key       string             `json:"key" example:"one-click-checkout"`
 	Name      string             `json:"name" binding:"required"`
-	Type      models.FeatureType `json:"type" binding:"required"`
+	Type      models.FeatureType `json:"type" binding:"required" enums:"basic,premium,enterprise"`
 	IsEnabled bool               `json:"is_enabled"`
+	Protected bool               `json:"protected"`
+	models.Targeting
+	models.Metadata
 }
 
+// AddOverridesRequest adds up to models.MaxOverrides overrides at once.
+type AddOverridesRequest struct {
+	Overrides []models.Override `json:"overrides" binding:"required,min=1"`
+}
+
+type RemoveOverridesRequest struct {
+	Overrides []models.OverrideKey `json:"overrides" binding:"required,min=1,dive"`
+}
+
+type SetProtectionRequest struct {
+	Protected bool `json:"protected"`
+}
+
+// AddDependencyRequest names each feature by ID or by key.
 type AddDependencyRequest struct {
-	ParentID string `json:"parent_id" binding:"required"`
-	ChildID  string `json:"child_id" binding:"required"`
+	ParentID  string `json:"parent_id"`
+	ParentKey string `json:"parent_key"`
+	ChildID   string `json:"child_id"`
+	ChildKey  string `json:"child_key"`
+}
+
+type FeatureDependenciesResponse struct {
+	Parents  []primitive.ObjectID `json:"parents"`
+	Children []primitive.ObjectID `json:"children"`
 }
 
-// ErrorResponse represents an error response
-type ErrorResponse struct {
-	Error string `json:"error" example:"error message"`
+// MessageResponse confirms a change that has no resource to return.
+type MessageResponse struct {
+	Message string `json:"message" example:"feature enabled successfully"`
+}
+
+type DisablePreviewResponse struct {
+	DryRun   bool              `json:"dry_run"`
+	Features []*models.Feature `json:"features"`
 }
 
 // CreateFeature godoc
@@ -42,26 +73,32 @@ type ErrorResponse struct {
 // @Tags features
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param feature body CreateFeatureRequest true "Feature to create"
 // @Success 201 {object} models.Feature
-// @Failure 400 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Failure 409 {object} problem.Problem "Key already exists, the feature is managed by GitOps sync, or it is protected while authentication is off"
 // @Router /api/features [post]
 func (h *FeatureHandler) CreateFeature(c *gin.Context) {
 	var req CreateFeatureRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBadRequest(c, err.Error())
 		return
 	}
 
 	feature := &models.Feature{
+		Key:       req.Key,
 		Name:      req.Name,
 		Type:      req.Type,
 		IsEnabled: req.IsEnabled,
+		Protected: req.Protected,
+		Targeting: req.Targeting,
+		Metadata:  req.Metadata,
 	}
 
 	if err := h.featureService.CreateFeature(c.Request.Context(), feature); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -70,36 +107,38 @@ func (h *FeatureHandler) CreateFeature(c *gin.Context) {
 
 // AddDependency godoc
 // @Summary Add a dependency between features
-// @Description Add a parent-child dependency between two features
+// @Description Add a parent-child dependency between two features, each given by ID or by key
 // @Tags features
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param dependency body AddDependencyRequest true "Dependency to add"
-// @Success 201 {object} ErrorResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Success 201 {object} MessageResponse
+// @Success 202 {object} models.ChangeRequest "Pending approval (protected feature)"
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Failure 404 {object} problem.Problem "Feature not found"
+// @Failure 409 {object} problem.Problem "Dependency already exists, or a feature is managed by GitOps sync"
+// @Failure 422 {object} problem.Problem "Dependency would create a cycle; cycle_path lists it"
 // @Router /api/features/dependencies [post]
 func (h *FeatureHandler) AddDependency(c *gin.Context) {
 	var req AddDependencyRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBadRequest(c, err.Error())
 		return
 	}
 
-	parentID, err := primitive.ObjectIDFromHex(req.ParentID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid parent_id"})
+	parentID, ok := h.dependencyEnd(c, "parent", req.ParentID, req.ParentKey)
+	if !ok {
 		return
 	}
-
-	childID, err := primitive.ObjectIDFromHex(req.ChildID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid child_id"})
+	childID, ok := h.dependencyEnd(c, "child", req.ChildID, req.ChildKey)
+	if !ok {
 		return
 	}
 
 	if err := h.featureService.AddChild(c.Request.Context(), parentID, childID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -112,21 +151,24 @@ func (h *FeatureHandler) AddDependency(c *gin.Context) {
 // @Tags features
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Feature ID"
-// @Success 200 {object} ErrorResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Success 200 {object} MessageResponse
+// @Success 202 {object} models.ChangeRequest "Pending approval (protected feature)"
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Failure 404 {object} problem.Problem "Feature not found"
+// @Failure 409 {object} problem.Problem "Feature is managed by GitOps sync"
+// @Failure 422 {object} problem.Problem "A parent is disabled; parent_ids lists them"
 // @Router /api/features/{id}/enable [post]
 func (h *FeatureHandler) EnableFeature(c *gin.Context) {
-	id := c.Param("id")
-	featureID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid feature id"})
+	featureID, ok := h.featureID(c)
+	if !ok {
 		return
 	}
 
 	if err := h.featureService.EnableFeature(c.Request.Context(), featureID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -135,25 +177,38 @@ func (h *FeatureHandler) EnableFeature(c *gin.Context) {
 
 // DisableFeature godoc
 // @Summary Disable a feature
-// @Description Disable a feature by ID
+// @Description Disable a feature by ID, cascading to every enabled feature that depends on it. With dry_run=true the cascade is returned without disabling anything.
 // @Tags features
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Feature ID"
-// @Success 200 {object} ErrorResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Param dry_run query bool false "Only report the features that would be disabled"
+// @Success 200 {object} MessageResponse
+// @Success 202 {object} models.ChangeRequest "Pending approval (protected feature)"
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Failure 404 {object} problem.Problem "Feature not found"
+// @Failure 409 {object} problem.Problem "Feature is managed by GitOps sync"
 // @Router /api/features/{id}/disable [post]
 func (h *FeatureHandler) DisableFeature(c *gin.Context) {
-	id := c.Param("id")
-	featureID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid feature id"})
+	featureID, ok := h.featureID(c)
+	if !ok {
+		return
+	}
+
+	if c.Query("dry_run") == "true" {
+		cascade, err := h.featureService.PreviewDisable(c.Request.Context(), featureID)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, DisablePreviewResponse{DryRun: true, Features: cascade})
 		return
 	}
 
 	if err := h.featureService.DisableFeature(c.Request.Context(), featureID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -166,29 +221,537 @@ func (h *FeatureHandler) DisableFeature(c *gin.Context) {
 // @Tags features
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Feature ID"
 // @Success 200 {object} models.Feature
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
 // @Router /api/features/{id} [get]
 func (h *FeatureHandler) GetFeatureStatus(c *gin.Context) {
+	featureID, ok := h.featureID(c)
+	if !ok {
+		return
+	}
+
+	feature, err := h.featureService.GetFeatureStatus(c.Request.Context(), featureID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, feature)
+}
+
+// SetTargeting godoc
+// @Summary Set feature targeting
+// @Description Replace the targeting rules and variants used when the feature is evaluated
+// @Tags features
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Feature ID"
+// @Param targeting body models.Targeting true "Targeting rules and variants"
+// @Success 200 {object} models.Feature
+// @Success 202 {object} models.ChangeRequest "Pending approval (protected feature)"
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Failure 409 {object} problem.Problem "Feature is managed by GitOps sync"
+// @Router /api/features/{id}/targeting [put]
+func (h *FeatureHandler) SetTargeting(c *gin.Context) {
 	id := c.Param("id")
 	featureID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid feature id"})
+		respondBadRequest(c, "invalid feature id")
+		return
+	}
+
+	var req models.Targeting
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	feature, err := h.featureService.SetTargeting(c.Request.Context(), featureID, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, feature)
+}
+
+// SetMetadata godoc
+// @Summary Set feature metadata
+// @Description Replace the description, tags, owner, maintainer, links and custom attributes of a feature. Metadata does not affect evaluation, so the change applies at once even to protected or GitOps-managed features and the feature's version is unchanged.
+// @Tags features
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Feature ID"
+// @Param metadata body models.Metadata true "Feature metadata"
+// @Success 200 {object} models.Feature
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/features/{id}/metadata [put]
+func (h *FeatureHandler) SetMetadata(c *gin.Context) {
+	featureID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		respondBadRequest(c, "invalid feature id")
+		return
+	}
+
+	var req models.Metadata
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	feature, err := h.featureService.SetMetadata(c.Request.Context(), featureID, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, feature)
+}
+
+// ListOverrides godoc
+// @Summary List feature overrides
+// @Description List the allow and deny overrides of a feature
+// @Tags features
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Feature ID"
+// @Success 200 {array} models.Override
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/features/{id}/overrides [get]
+func (h *FeatureHandler) ListOverrides(c *gin.Context) {
+	featureID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		respondBadRequest(c, "invalid feature id")
 		return
 	}
 
 	feature, err := h.featureService.GetFeatureStatus(c.Request.Context(), featureID)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			c.JSON(http.StatusNotFound, gin.H{"error": "feature not found"})
+		respondError(c, err)
+		return
+	}
+
+	overrides := feature.Overrides
+	if overrides == nil {
+		overrides = []models.Override{}
+	}
+	c.JSON(http.StatusOK, overrides)
+}
+
+// AddOverrides godoc
+// @Summary Add feature overrides
+// @Description Turn a feature on (allow) or off (deny) for the evaluation contexts whose attribute has a given value, ahead of the enabled state, rules and splits. Overrides replace existing ones with the same attribute and value. All are applied or none: an invalid override or exceeding the per-feature limit fails the request.
+// @Tags features
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Feature ID"
+// @Param overrides body AddOverridesRequest true "Overrides to add"
+// @Success 200 {object} models.Feature
+// @Success 202 {object} models.ChangeRequest "Pending approval (protected feature)"
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 422 {object} problem.Problem "The feature would exceed its override limit; limit and count give the numbers"
+// @Failure 409 {object} problem.Problem "The feature changed while the overrides were applied"
+// @Failure 500 {object} problem.Problem
+// @Router /api/features/{id}/overrides [post]
+func (h *FeatureHandler) AddOverrides(c *gin.Context) {
+	featureID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		respondBadRequest(c, "invalid feature id")
+		return
+	}
+
+	var req AddOverridesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	feature, err := h.featureService.AddOverrides(c.Request.Context(), featureID, req.Overrides)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, feature)
+}
+
+// RemoveOverrides godoc
+// @Summary Remove feature overrides
+// @Description Remove the overrides with the given attributes and values. Overrides the feature does not have are ignored.
+// @Tags features
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Feature ID"
+// @Param overrides body RemoveOverridesRequest true "Overrides to remove"
+// @Success 200 {object} models.Feature
+// @Success 202 {object} models.ChangeRequest "Pending approval (protected feature)"
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem "The feature changed while the overrides were applied"
+// @Failure 500 {object} problem.Problem
+// @Router /api/features/{id}/overrides/remove [post]
+func (h *FeatureHandler) RemoveOverrides(c *gin.Context) {
+	featureID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		respondBadRequest(c, "invalid feature id")
+		return
+	}
+
+	var req RemoveOverridesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	feature, err := h.featureService.RemoveOverrides(c.Request.Context(), featureID, req.Overrides)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, feature)
+}
+
+// SetProtection godoc
+// @Summary Set feature protection
+// @Description Mark a feature as protected. Enabling, disabling or adding dependencies to a protected feature creates a change request that must be approved. Approving needs an authenticated reviewer, so with authentication off features can be unprotected but not protected.
+// @Tags features
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Feature ID"
+// @Param protection body SetProtectionRequest true "Protection setting"
+// @Success 200 {object} models.Feature
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Failure 409 {object} problem.Problem "Feature is managed by GitOps sync, or protecting it while authentication is off"
+// @Router /api/features/{id}/protection [put]
+func (h *FeatureHandler) SetProtection(c *gin.Context) {
+	id := c.Param("id")
+	featureID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		respondBadRequest(c, "invalid feature id")
+		return
+	}
+
+	var req SetProtectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	feature, err := h.featureService.SetProtected(c.Request.Context(), featureID, req.Protected)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, feature)
+}
+
+// SetGuard godoc
+// @Summary Set feature guard rule
+// @Description Turn the feature off automatically, with its full disable cascade, when the metric over the signals reported for it in the last window reaches the threshold. error_rate is errors / (errors + successes) and needs a threshold in (0, 1]; error_count needs a threshold of at least 1. Windows are at most 24h. Trips of protected features create a change request instead. Setting a rule clears when it last tripped. GitOps-managed features cannot have guards.
+// @Tags features
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Feature ID"
+// @Param guard body models.GuardRule true "Guard rule"
+// @Success 200 {object} models.Feature
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem "Feature is managed by GitOps sync"
+// @Failure 500 {object} problem.Problem
+// @Router /api/features/{id}/guard [put]
+func (h *FeatureHandler) SetGuard(c *gin.Context) {
+	featureID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		respondBadRequest(c, "invalid feature id")
+		return
+	}
+
+	var req models.GuardRule
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	feature, err := h.featureService.SetGuard(c.Request.Context(), featureID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, feature)
+}
+
+// RemoveGuard godoc
+// @Summary Remove feature guard rule
+// @Description Stop turning the feature off automatically
+// @Tags features
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Feature ID"
+// @Success 200 {object} models.Feature
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem "Feature is managed by GitOps sync"
+// @Failure 500 {object} problem.Problem
+// @Router /api/features/{id}/guard [delete]
+func (h *FeatureHandler) RemoveGuard(c *gin.Context) {
+	featureID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		respondBadRequest(c, "invalid feature id")
+		return
+	}
+
+	feature, err := h.featureService.SetGuard(c.Request.Context(), featureID, nil)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, feature)
+}
+
+// ListFeatures godoc
+// @Summary List features
+// @Description List feature flags ordered by name, optionally filtered by type, enabled state, tags, owner, segment and guard
+// @Tags features
+// @Produce json
+// @Security BearerAuth
+// @Param type query string false "Only features of this type"
+// @Param enabled query bool false "Only enabled (true) or disabled (false) features"
+// @Param tag query []string false "Only features with this tag; repeat to require several" collectionFormat(multi)
+// @Param owner query string false "Only features owned by this team"
+// @Param segment query string false "Only features whose rules reference this segment"
+// @Param guarded query bool false "Only features with a guard rule"
+// @Success 200 {array} models.Feature
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/features [get]
+func (h *FeatureHandler) ListFeatures(c *gin.Context) {
+	filter := models.FeatureFilter{
+		Type:    models.FeatureType(c.Query("type")),
+		Tags:    c.QueryArray("tag"),
+		Owner:   c.Query("owner"),
+		Segment: c.Query("segment"),
+	}
+	if value, ok := c.GetQuery("enabled"); ok {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			respondBadRequest(c, "invalid enabled")
+			return
+		}
+		filter.Enabled = &enabled
+	}
+	if value, ok := c.GetQuery("guarded"); ok {
+		guarded, err := strconv.ParseBool(value)
+		if err != nil {
+			respondBadRequest(c, "invalid guarded")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		filter.Guarded = guarded
+	}
+
+	features, err := h.featureService.ListFeatures(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, feature)
+	c.JSON(http.StatusOK, features)
+}
+
+// ListDependencies godoc
+// @Summary List dependencies
+// @Description List every parent-child dependency edge
+// @Tags features
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.FeatureDependency
+// @Failure 500 {object} problem.Problem
+// @Router /api/features/dependencies [get]
+func (h *FeatureHandler) ListDependencies(c *gin.Context) {
+	dependencies, err := h.featureService.ListDependencies(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dependencies)
+}
+
+// GetFeatureDependencies godoc
+// @Summary Get feature dependencies
+// @Description Get the direct parents and children of a feature
+// @Tags features
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Feature ID"
+// @Success 200 {object} FeatureDependenciesResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/features/{id}/dependencies [get]
+func (h *FeatureHandler) GetFeatureDependencies(c *gin.Context) {
+	id := c.Param("id")
+	featureID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		respondBadRequest(c, "invalid feature id")
+		return
+	}
+
+	parents, children, err := h.featureService.GetDependencies(c.Request.Context(), featureID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, FeatureDependenciesResponse{Parents: parents, Children: children})
+}
+
+// RemoveDependency godoc
+// @Summary Remove a dependency between features
+// @Description Remove a parent-child dependency between two features
+// @Tags features
+// @Produce json
+// @Security BearerAuth
+// @Param parent_id query string true "Parent feature ID"
+// @Param child_id query string true "Child feature ID"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Failure 409 {object} problem.Problem "Feature is managed by GitOps sync"
+// @Router /api/features/dependencies [delete]
+func (h *FeatureHandler) RemoveDependency(c *gin.Context) {
+	parentID, err := primitive.ObjectIDFromHex(c.Query("parent_id"))
+	if err != nil {
+		respondBadRequest(c, "invalid parent_id")
+		return
+	}
+
+	childID, err := primitive.ObjectIDFromHex(c.Query("child_id"))
+	if err != nil {
+		respondBadRequest(c, "invalid child_id")
+		return
+	}
+
+	if err := h.featureService.RemoveChild(c.Request.Context(), parentID, childID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "dependency removed successfully"})
+}
+
+// GetFeatureByKey godoc
+// @Summary Get feature status by key
+// @Description Get the status of a feature by its key
+// @Tags features
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "Feature key"
+// @Success 200 {object} models.Feature
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/features/key/{key} [get]
+func (h *FeatureHandler) GetFeatureByKey(c *gin.Context) {
+	h.GetFeatureStatus(c)
+}
+
+// EnableFeatureByKey godoc
+// @Summary Enable a feature by key
+// @Description Enable a feature by its key
+// @Tags features
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "Feature key"
+// @Success 200 {object} MessageResponse
+// @Success 202 {object} models.ChangeRequest "Pending approval (protected feature)"
+// @Failure 404 {object} problem.Problem "Feature not found"
+// @Failure 409 {object} problem.Problem "Feature is managed by GitOps sync"
+// @Failure 422 {object} problem.Problem "A parent is disabled; parent_ids lists them"
+// @Failure 500 {object} problem.Problem
+// @Router /api/features/key/{key}/enable [post]
+func (h *FeatureHandler) EnableFeatureByKey(c *gin.Context) {
+	h.EnableFeature(c)
+}
+
+// DisableFeatureByKey godoc
+// @Summary Disable a feature by key
+// @Description Disable a feature by its key, cascading to every enabled feature that depends on it. With dry_run=true the cascade is returned without disabling anything.
+// @Tags features
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "Feature key"
+// @Param dry_run query bool false "Only report the features that would be disabled"
+// @Success 200 {object} MessageResponse
+// @Success 202 {object} models.ChangeRequest "Pending approval (protected feature)"
+// @Failure 404 {object} problem.Problem "Feature not found"
+// @Failure 409 {object} problem.Problem "Feature is managed by GitOps sync"
+// @Failure 500 {object} problem.Problem
+// @Router /api/features/key/{key}/disable [post]
+func (h *FeatureHandler) DisableFeatureByKey(c *gin.Context) {
+	h.DisableFeature(c)
+}
+
+// featureID returns the feature addressed by the route: the :key parameter
+// on key routes, otherwise :id. It responds and returns false if there is
+// no such feature.
+func (h *FeatureHandler) featureID(c *gin.Context) (primitive.ObjectID, bool) {
+	if key := c.Param("key"); key != "" {
+		return h.featureIDByKey(c, key)
+	}
+	featureID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		respondBadRequest(c, "invalid feature id")
+		return primitive.NilObjectID, false
+	}
+	return featureID, true
+}
+
+func (h *FeatureHandler) featureIDByKey(c *gin.Context, key string) (primitive.ObjectID, bool) {
+	feature, err := h.featureService.GetFeatureByKey(c.Request.Context(), key)
+	if err != nil {
+		respondError(c, err)
+		return primitive.NilObjectID, false
+	}
+	return feature.ID, true
+}
+
+// dependencyEnd returns the feature given by exactly one of the <end>_id
+// and <end>_key fields of a dependency request.
+func (h *FeatureHandler) dependencyEnd(c *gin.Context, end, id, key string) (primitive.ObjectID, bool) {
+	switch {
+	case id != "" && key != "":
+		respondBadRequest(c, "give "+end+"_id or "+end+"_key, not both")
+		return primitive.NilObjectID, false
+	case key != "":
+		return h.featureIDByKey(c, key)
+	}
+	featureID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		respondBadRequest(c, "invalid "+end+"_id")
+		return primitive.NilObjectID, false
+	}
+	return featureID, true
 }
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"feature-flags/internal/gitops"
+	"feature-flags/internal/problem"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GitOpsHandler struct {
+	syncer *gitops.Syncer
+}
+
+// NewGitOpsHandler returns a handler for the GitOps sync routes. syncer is
+// nil when sync mode is off.
+func NewGitOpsHandler(syncer *gitops.Syncer) *GitOpsHandler {
+	return &GitOpsHandler{
+		syncer: syncer,
+	}
+}
+
+// GetStatus godoc
+// @Summary Get GitOps sync status
+// @Description Get the manifest revision, managed features and drift found by the last sync
+// @Tags gitops
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} gitops.Status
+// @Failure 404 {object} problem.Problem
+// @Router /api/gitops/status [get]
+func (h *GitOpsHandler) GetStatus(c *gin.Context) {
+	if h.syncer == nil {
+		problem.Write(c, problem.New(http.StatusNotFound, "gitops sync is not enabled"))
+		return
+	}
+	c.JSON(http.StatusOK, h.syncer.Status())
+}
+
+// Sync godoc
+// @Summary Sync now
+// @Description Reload the manifest directory and reconcile immediately, e.g. from a webhook after a git pull
+// @Tags gitops
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} gitops.Status
+// @Failure 404 {object} problem.Problem
+// @Failure 422 {object} gitops.Status "Sync failed; see last_error"
+// @Router /api/gitops/sync [post]
+func (h *GitOpsHandler) Sync(c *gin.Context) {
+	if h.syncer == nil {
+		problem.Write(c, problem.New(http.StatusNotFound, "gitops sync is not enabled"))
+		return
+	}
+
+	status, err := h.syncer.Sync(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, status)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
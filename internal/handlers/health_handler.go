@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"feature-flags/internal/health"
+	"feature-flags/internal/services"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthHandler struct {
+	checker        *health.Checker
+	featureService *services.FeatureService
+}
+
+func NewHealthHandler(checker *health.Checker, featureService *services.FeatureService) *HealthHandler {
+	return &HealthHandler{
+		checker:        checker,
+		featureService: featureService,
+	}
+}
+
+// HealthResponse is returned by the liveness probe.
+type HealthResponse struct {
+	Status string `json:"status" example:"ok"`
+}
+
+// DebugStatusResponse reports the state of a running server.
+type DebugStatusResponse struct {
+	Build        health.BuildInfo      `json:"build"`
+	StartedAt    time.Time             `json:"started_at"`
+	Uptime       string                `json:"uptime" example:"26h3m12s"`
+	Backend      string                `json:"backend" example:"mongodb"`
+	Features     int64                 `json:"features"`
+	Dependencies int64                 `json:"dependencies"`
+	Readiness    health.Readiness      `json:"readiness"`
+	Workers      []health.WorkerStatus `json:"workers"`
+}
+
+// Healthz godoc
+// @Summary Liveness probe
+// @Description Report that the process is up. Does not check dependencies; use /readyz for that.
+// @Tags health
+// @Produce json
+// @Success 200 {object} HealthResponse
+// @Router /healthz [get]
+func (h *HealthHandler) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, HealthResponse{Status: "ok"})
+}
+
+// Readyz godoc
+// @Summary Readiness probe
+// @Description Ping MongoDB and check that required indexes exist. Fails with 503 while the server is shutting down.
+// @Tags health
+// @Produce json
+// @Success 200 {object} health.Readiness
+// @Failure 503 {object} health.Readiness
+// @Router /readyz [get]
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	readiness := h.checker.Ready(c.Request.Context())
+	if !readiness.Ready {
+		c.JSON(http.StatusServiceUnavailable, readiness)
+		return
+	}
+	c.JSON(http.StatusOK, readiness)
+}
+
+// DebugStatus godoc
+// @Summary Get server diagnostics
+// @Description Get build info, uptime, storage backend, flag and dependency counts, readiness and background worker status. Only served when authentication is on, since it requires the admin role.
+// @Tags health
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} DebugStatusResponse
+// @Failure 500 {object} problem.Problem
+// @Router /debug/status [get]
+func (h *HealthHandler) DebugStatus(c *gin.Context) {
+	ctx := c.Request.Context()
+	features, dependencies, err := h.featureService.Counts(ctx)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, DebugStatusResponse{
+		Build:        health.Build(),
+		StartedAt:    h.checker.Started(),
+		Uptime:       h.checker.Uptime().Round(time.Second).String(),
+		Backend:      "mongodb",
+		Features:     features,
+		Dependencies: dependencies,
+		Readiness:    h.checker.Ready(ctx),
+		Workers:      h.checker.Workers(),
+	})
+}
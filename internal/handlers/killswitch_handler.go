@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"feature-flags/internal/models"
+	"feature-flags/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type KillSwitchHandler struct {
+	featureService *services.FeatureService
+}
+
+func NewKillSwitchHandler(featureService *services.FeatureService) *KillSwitchHandler {
+	return &KillSwitchHandler{
+		featureService: featureService,
+	}
+}
+
+type ReportSignalsRequest struct {
+	Signals []models.Signal `json:"signals" binding:"required"`
+}
+
+type ReportSignalsResponse struct {
+	Accepted int `json:"accepted" example:"1"`
+}
+
+// ReportSignals godoc
+// @Summary Report error signals
+// @Description Report how many calls made under a flag failed and succeeded, for the flag's guard rule. Signals without a timestamp get the current time; those timestamped more than 5 minutes in the future are rejected. At most 1000 may be sent at once.
+// @Tags kill-switch
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param signals body ReportSignalsRequest true "Signals to record"
+// @Success 202 {object} ReportSignalsResponse
+// @Failure 400 {object} problem.Problem "Missing flag key or counts, future timestamp, or too many signals"
+// @Failure 500 {object} problem.Problem
+// @Router /api/signals [post]
+func (h *KillSwitchHandler) ReportSignals(c *gin.Context) {
+	var req ReportSignalsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.featureService.ReportSignals(c.Request.Context(), req.Signals); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, ReportSignalsResponse{Accepted: len(req.Signals)})
+}
+
+// ListAuditLog godoc
+// @Summary List the audit trail
+// @Description List safety actions taken on features, such as kill switch trips, rollout steps and rollout aborts, newest first
+// @Tags kill-switch
+// @Produce json
+// @Security BearerAuth
+// @Param feature query string false "Only entries for the feature with this key"
+// @Param action query string false "Only entries with this action" Enums(kill_switch, rollout_step, rollout_abort)
+// @Param limit query int false "Most entries to return, at most 1000" default(100)
+// @Success 200 {array} models.AuditEntry
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/audit [get]
+func (h *KillSwitchHandler) ListAuditLog(c *gin.Context) {
+	filter := models.AuditFilter{
+		FeatureKey: c.Query("feature"),
+		Action:     models.AuditAction(c.Query("action")),
+	}
+	if value, ok := c.GetQuery("limit"); ok {
+		limit, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || limit < 1 {
+			respondBadRequest(c, "invalid limit")
+			return
+		}
+		filter.Limit = limit
+	}
+
+	entries, err := h.featureService.ListAuditLog(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
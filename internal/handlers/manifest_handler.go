@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"feature-flags/internal/manifest"
+	"feature-flags/internal/services"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ManifestHandler struct {
+	featureService *services.FeatureService
+}
+
+func NewManifestHandler(featureService *services.FeatureService) *ManifestHandler {
+	return &ManifestHandler{
+		featureService: featureService,
+	}
+}
+
+// ExportManifest godoc
+// @Summary Export the flag manifest
+// @Description Export every feature and dependency as a declarative manifest
+// @Tags manifest
+// @Produce json
+// @Produce application/yaml
+// @Security BearerAuth
+// @Param format query string false "yaml (default) or json"
+// @Success 200 {object} manifest.Manifest
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/manifest [get]
+func (h *ManifestHandler) ExportManifest(c *gin.Context) {
+	format := c.DefaultQuery("format", "yaml")
+
+	m, err := h.featureService.ExportManifest(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	data, err := m.Marshal(format)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	contentType := "application/yaml"
+	if format == "json" {
+		contentType = "application/json"
+	}
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// PlanManifest godoc
+// @Summary Plan a manifest
+// @Description Compare a YAML or JSON manifest with the server and report the creates, updates, deletes, dependency changes and cascaded disables applying it would cause
+// @Tags manifest
+// @Accept json
+// @Accept application/yaml
+// @Produce json
+// @Security BearerAuth
+// @Param prune query bool false "Delete features and dependencies missing from the manifest"
+// @Param manifest body manifest.Manifest true "Manifest"
+// @Success 200 {object} manifest.Plan
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /api/manifest/plan [post]
+func (h *ManifestHandler) PlanManifest(c *gin.Context) {
+	m, opts, ok := h.bind(c)
+	if !ok {
+		return
+	}
+
+	plan, err := h.featureService.PlanManifest(c.Request.Context(), m, opts)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+// ApplyManifest godoc
+// @Summary Apply a manifest
+// @Description Make the server match a YAML or JSON manifest. Features are matched by key, so a manifest can rename them. All changes are applied in a single transaction. Without transaction support (a standalone MongoDB) a failed change leaves the changes before it in place, and the error lists them under "applied". Manifests cannot change protected features, delete them or change their dependencies; use the feature endpoints, which file change requests. GitOps sync is exempt.
+// @Tags manifest
+// @Accept json
+// @Accept application/yaml
+// @Produce json
+// @Security BearerAuth
+// @Param prune query bool false "Delete features and dependencies missing from the manifest"
+// @Param manifest body manifest.Manifest true "Manifest"
+// @Success 200 {object} manifest.Plan
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Failure 409 {object} problem.Problem "Manifest changes a feature managed by GitOps sync or a protected feature, or protects a feature while authentication is off"
+// @Router /api/manifest/apply [post]
+func (h *ManifestHandler) ApplyManifest(c *gin.Context) {
+	m, opts, ok := h.bind(c)
+	if !ok {
+		return
+	}
+
+	plan, err := h.featureService.ApplyManifest(c.Request.Context(), m, opts)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+func (h *ManifestHandler) bind(c *gin.Context) (*manifest.Manifest, services.ManifestOptions, bool) {
+	opts := services.ManifestOptions{Prune: c.Query("prune") == "true"}
+
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, 10<<20))
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return nil, opts, false
+	}
+
+	m, err := manifest.Parse(data)
+	if err != nil {
+		respondBadRequest(c, err.Error())
+		return nil, opts, false
+	}
+	return m, opts, true
+}
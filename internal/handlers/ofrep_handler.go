@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"feature-flags/internal/evaluation"
+	"feature-flags/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OFREPHandler serves the OpenFeature Remote Evaluation Protocol, so any
+// OpenFeature SDK with an OFREP provider can evaluate flags against this
+// service.
+type OFREPHandler struct {
+	featureService *services.FeatureService
+}
+
+func NewOFREPHandler(featureService *services.FeatureService) *OFREPHandler {
+	return &OFREPHandler{
+		featureService: featureService,
+	}
+}
+
+// OFREPRequest carries the evaluation context. The targetingKey attribute
+// identifies the subject for weighted variants.
+type OFREPRequest struct {
+	Context evaluation.Context `json:"context"`
+}
+
+// OFREPBulkResponse is the result of evaluating every flag.
+type OFREPBulkResponse struct {
+	Flags []evaluation.Result `json:"flags"`
+}
+
+// EvaluateFlag godoc
+// @Summary Evaluate a flag
+// @Description Resolve a flag by key for an evaluation context (OFREP single flag evaluation)
+// @Tags ofrep
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "Flag key"
+// @Param request body OFREPRequest true "Evaluation context"
+// @Success 200 {object} evaluation.Result
+// @Failure 400 {object} evaluation.Result
+// @Failure 404 {object} evaluation.Result
+// @Failure 500 {object} problem.Problem
+// @Router /ofrep/v1/evaluate/flags/{key} [post]
+func (h *OFREPHandler) EvaluateFlag(c *gin.Context) {
+	key := c.Param("key")
+
+	req, ok := h.bind(c, key)
+	if !ok {
+		return
+	}
+
+	result, err := h.featureService.Evaluate(c.Request.Context(), key, req.Context)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	switch result.ErrorCode {
+	case "":
+		c.JSON(http.StatusOK, result)
+	case evaluation.ErrorFlagNotFound:
+		c.JSON(http.StatusNotFound, result)
+	default:
+		c.JSON(http.StatusBadRequest, result)
+	}
+}
+
+// EvaluateFlags godoc
+// @Summary Evaluate all flags
+// @Description Resolve every flag for an evaluation context (OFREP bulk evaluation). Send the returned ETag as If-None-Match to get 304 when nothing changed.
+// @Tags ofrep
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body OFREPRequest true "Evaluation context"
+// @Param If-None-Match header string false "ETag of a previous response"
+// @Success 200 {object} OFREPBulkResponse
+// @Success 304 "Not modified"
+// @Failure 400 {object} evaluation.Result
+// @Failure 500 {object} problem.Problem
+// @Router /ofrep/v1/evaluate/flags [post]
+func (h *OFREPHandler) EvaluateFlags(c *gin.Context) {
+	req, ok := h.bind(c, "")
+	if !ok {
+		return
+	}
+
+	results, err := h.featureService.EvaluateAll(c.Request.Context(), req.Context)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	body, err := json.Marshal(OFREPBulkResponse{Flags: results})
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Header("ETag", etag)
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
+
+// bind reads the evaluation context. An empty body is an empty context.
+func (h *OFREPHandler) bind(c *gin.Context, key string) (OFREPRequest, bool) {
+	var req OFREPRequest
+	if c.Request.ContentLength == 0 {
+		return req, true
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, evaluation.Result{
+			Key:          key,
+			Reason:       evaluation.ReasonError,
+			ErrorCode:    evaluation.ErrorInvalidContext,
+			ErrorDetails: err.Error(),
+		})
+		return req, false
+	}
+	return req, true
+}
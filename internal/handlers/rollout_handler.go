@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"feature-flags/internal/models"
+	"feature-flags/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RolloutHandler struct {
+	featureService *services.FeatureService
+}
+
+func NewRolloutHandler(featureService *services.FeatureService) *RolloutHandler {
+	return &RolloutHandler{
+		featureService: featureService,
+	}
+}
+
+// SetRolloutRequest lists the steps of a plan, from the first percentage to
+// 100.
+type SetRolloutRequest struct {
+	Steps []models.RolloutStep `json:"steps" binding:"required"`
+}
+
+// SetRollout godoc
+// @Summary Start a rollout plan
+// @Description Release the feature gradually: while enabled it is only on for the percentage of targeting keys of the current step, and the plan moves to the next step once the step's hold has passed. Percentages must rise to 100 over 2 to 20 steps, and every step but the last needs a hold. Starting a plan replaces any plan the feature had and begins at the first step. Plans of disabled features do not advance. Protected features get a change request, and the plan starts once it is approved. GitOps-managed features cannot have plans.
+// @Tags rollouts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Feature ID"
+// @Param plan body SetRolloutRequest true "Rollout steps"
+// @Success 200 {object} models.Feature
+// @Success 202 {object} models.ChangeRequest "Pending approval (protected feature)"
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem "Feature is managed by GitOps sync, or changed while the plan was set"
+// @Failure 500 {object} problem.Problem
+// @Router /api/features/{id}/rollout [put]
+func (h *RolloutHandler) SetRollout(c *gin.Context) {
+	featureID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		respondBadRequest(c, "invalid feature id")
+		return
+	}
+
+	var req SetRolloutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	feature, err := h.featureService.SetRollout(c.Request.Context(), featureID, &models.RolloutPlan{Steps: req.Steps})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, feature)
+}
+
+// RemoveRollout godoc
+// @Summary Remove the rollout plan
+// @Description Remove the feature's rollout plan, so while enabled it is on for every context again. Protected features get a change request.
+// @Tags rollouts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Feature ID"
+// @Success 200 {object} models.Feature
+// @Success 202 {object} models.ChangeRequest "Pending approval (protected feature)"
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem "Feature is managed by GitOps sync, or changed while the plan was set"
+// @Failure 500 {object} problem.Problem
+// @Router /api/features/{id}/rollout [delete]
+func (h *RolloutHandler) RemoveRollout(c *gin.Context) {
+	h.change(c, func(ctx context.Context, id primitive.ObjectID) (*models.Feature, error) {
+		return h.featureService.SetRollout(ctx, id, nil)
+	})
+}
+
+// PauseRollout godoc
+// @Summary Pause the rollout plan
+// @Description Keep an active plan at its current step until it is resumed. Time spent paused does not count towards the step's hold.
+// @Tags rollouts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Feature ID"
+// @Success 200 {object} models.Feature
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem "No plan, or the plan is not active"
+// @Failure 500 {object} problem.Problem
+// @Router /api/features/{id}/rollout/pause [post]
+func (h *RolloutHandler) PauseRollout(c *gin.Context) {
+	h.change(c, h.featureService.PauseRollout)
+}
+
+// ResumeRollout godoc
+// @Summary Resume the rollout plan
+// @Description Let a paused plan advance again once what remained of the current step's hold has passed
+// @Tags rollouts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Feature ID"
+// @Success 200 {object} models.Feature
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem "No plan, or the plan is not paused"
+// @Failure 500 {object} problem.Problem
+// @Router /api/features/{id}/rollout/resume [post]
+func (h *RolloutHandler) ResumeRollout(c *gin.Context) {
+	h.change(c, h.featureService.ResumeRollout)
+}
+
+// AbortRollout godoc
+// @Summary Abort the rollout plan
+// @Description Roll an active or paused plan back to 0%, then disable the feature and every enabled feature that depends on it, as the disable endpoint does. If the cascade is protected the plan stays at 0% while the disable waits for approval, and the change request is returned. The abort is recorded in the audit trail.
+// @Tags rollouts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Feature ID"
+// @Success 200 {object} models.Feature
+// @Success 202 {object} models.ChangeRequest "Disable pending approval (protected feature)"
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem "No plan, the plan is finished, or a feature is managed by GitOps sync"
+// @Failure 500 {object} problem.Problem
+// @Router /api/features/{id}/rollout/abort [post]
+func (h *RolloutHandler) AbortRollout(c *gin.Context) {
+	h.change(c, h.featureService.AbortRollout)
+}
+
+// change applies a change to the rollout plan of the feature in the path
+// and responds with the feature.
+func (h *RolloutHandler) change(c *gin.Context, apply func(context.Context, primitive.ObjectID) (*models.Feature, error)) {
+	featureID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		respondBadRequest(c, "invalid feature id")
+		return
+	}
+
+	feature, err := apply(c.Request.Context(), featureID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, feature)
+}
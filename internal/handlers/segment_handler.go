@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"feature-flags/internal/models"
+	"feature-flags/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SegmentHandler struct {
+	featureService *services.FeatureService
+}
+
+func NewSegmentHandler(featureService *services.FeatureService) *SegmentHandler {
+	return &SegmentHandler{
+		featureService: featureService,
+	}
+}
+
+// SegmentRequest describes a segment. Name defaults to the key.
+type SegmentRequest struct {
+	Name        string                 `json:"name" example:"Internal employees"`
+	Description string                 `json:"description"`
+	Rules       []models.TargetingRule `json:"rules"`
+	// Attribute is the context attribute Included and Excluded list values
+	// of, the targeting key by default.
+	Attribute string   `json:"attribute" example:"userId"`
+	Included  []string `json:"included"`
+	Excluded  []string `json:"excluded"`
+}
+
+type CreateSegmentRequest struct {
+	Key string `json:"key" binding:"required" example:"internal-employees"`
+	SegmentRequest
+}
+
+func (r *SegmentRequest) segment() *models.Segment {
+	return &models.Segment{
+		Name:        r.Name,
+		Description: r.Description,
+		Rules:       r.Rules,
+		Attribute:   r.Attribute,
+		Included:    r.Included,
+		Excluded:    r.Excluded,
+	}
+}
+
+// ListSegments godoc
+// @Summary List segments
+// @Description List every segment, ordered by key
+// @Tags segments
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.Segment
+// @Failure 500 {object} problem.Problem
+// @Router /api/segments [get]
+func (h *SegmentHandler) ListSegments(c *gin.Context) {
+	segments, err := h.featureService.ListSegments(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, segments)
+}
+
+// CreateSegment godoc
+// @Summary Create a segment
+// @Description Create a reusable segment that targeting rules reference by key with the in_segment and not_in_segment operators
+// @Tags segments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param segment body CreateSegmentRequest true "Segment to create"
+// @Success 201 {object} models.Segment
+// @Failure 400 {object} problem.Problem "Invalid key, rules or lists"
+// @Failure 409 {object} problem.Problem "Key already exists"
+// @Failure 500 {object} problem.Problem
+// @Router /api/segments [post]
+func (h *SegmentHandler) CreateSegment(c *gin.Context) {
+	var req CreateSegmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	segment := req.segment()
+	segment.Key = req.Key
+	if err := h.featureService.CreateSegment(c.Request.Context(), segment); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, segment)
+}
+
+// GetSegment godoc
+// @Summary Get a segment
+// @Tags segments
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "Segment key"
+// @Success 200 {object} models.Segment
+// @Failure 404 {object} problem.Problem "Segment not found"
+// @Failure 500 {object} problem.Problem
+// @Router /api/segments/{key} [get]
+func (h *SegmentHandler) GetSegment(c *gin.Context) {
+	segment, err := h.featureService.GetSegment(c.Request.Context(), c.Param("key"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, segment)
+}
+
+// UpdateSegment godoc
+// @Summary Replace a segment
+// @Description Replace the name, description, rules and lists of a segment. The change applies at once to every feature whose rules reference it, including protected features.
+// @Tags segments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "Segment key"
+// @Param segment body SegmentRequest true "New segment"
+// @Success 200 {object} models.Segment
+// @Failure 400 {object} problem.Problem "Invalid rules or lists"
+// @Failure 404 {object} problem.Problem "Segment not found"
+// @Failure 409 {object} problem.Problem "The segment changed while the update was applied"
+// @Failure 500 {object} problem.Problem
+// @Router /api/segments/{key} [put]
+func (h *SegmentHandler) UpdateSegment(c *gin.Context) {
+	var req SegmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	segment, err := h.featureService.UpdateSegment(c.Request.Context(), c.Param("key"), req.segment())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, segment)
+}
+
+// DeleteSegment godoc
+// @Summary Delete a segment
+// @Description Delete a segment that no targeting rule references
+// @Tags segments
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "Segment key"
+// @Success 200 {object} MessageResponse
+// @Failure 404 {object} problem.Problem "Segment not found"
+// @Failure 409 {object} problem.Problem "Segment in use; features lists the keys of the features referencing it"
+// @Failure 500 {object} problem.Problem
+// @Router /api/segments/{key} [delete]
+func (h *SegmentHandler) DeleteSegment(c *gin.Context) {
+	if err := h.featureService.DeleteSegment(c.Request.Context(), c.Param("key")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "segment deleted successfully"})
+}
+
+// ListSegmentFeatures godoc
+// @Summary List the features using a segment
+// @Description List the features whose targeting rules reference the segment, ordered by name
+// @Tags segments
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "Segment key"
+// @Success 200 {array} models.Feature
+// @Failure 404 {object} problem.Problem "Segment not found"
+// @Failure 500 {object} problem.Problem
+// @Router /api/segments/{key}/features [get]
+func (h *SegmentHandler) ListSegmentFeatures(c *gin.Context) {
+	features, err := h.featureService.SegmentUsage(c.Request.Context(), c.Param("key"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, features)
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"feature-flags/internal/models"
+	"feature-flags/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TenantHandler struct {
+	featureService *services.FeatureService
+}
+
+func NewTenantHandler(featureService *services.FeatureService) *TenantHandler {
+	return &TenantHandler{
+		featureService: featureService,
+	}
+}
+
+type SetTenantPlanRequest struct {
+	Plan   string `json:"plan" binding:"required" example:"enterprise"`
+	Reason string `json:"reason" example:"enterprise trial until 2026-12-31"`
+}
+
+// ListTenantPlans godoc
+// @Summary List tenant plan overrides
+// @Description List every tenant whose plan is overridden, ordered by tenant ID
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.TenantPlan
+// @Failure 500 {object} problem.Problem
+// @Router /api/tenants [get]
+func (h *TenantHandler) ListTenantPlans(c *gin.Context) {
+	plans, err := h.featureService.ListTenantPlans(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, plans)
+}
+
+// GetTenantPlan godoc
+// @Summary Get a tenant's plan override
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param tenant path string true "Tenant ID"
+// @Success 200 {object} models.TenantPlan
+// @Failure 404 {object} problem.Problem "The tenant has no override"
+// @Failure 500 {object} problem.Problem
+// @Router /api/tenants/{tenant}/plan [get]
+func (h *TenantHandler) GetTenantPlan(c *gin.Context) {
+	plan, err := h.featureService.GetTenantPlan(c.Request.Context(), c.Param("tenant"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+// SetTenantPlan godoc
+// @Summary Override a tenant's plan
+// @Description Put a tenant on a plan regardless of the plan its evaluation contexts carry. The override applies to evaluations whose context names the tenant.
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param tenant path string true "Tenant ID"
+// @Param plan body SetTenantPlanRequest true "Plan override"
+// @Success 200 {object} models.TenantPlan
+// @Failure 400 {object} problem.Problem "Unknown plan"
+// @Failure 500 {object} problem.Problem
+// @Router /api/tenants/{tenant}/plan [put]
+func (h *TenantHandler) SetTenantPlan(c *gin.Context) {
+	var req SetTenantPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBadRequest(c, err.Error())
+		return
+	}
+
+	plan := &models.TenantPlan{TenantID: c.Param("tenant"), Plan: req.Plan, Reason: req.Reason}
+	if err := h.featureService.SetTenantPlan(c.Request.Context(), plan); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+// DeleteTenantPlan godoc
+// @Summary Remove a tenant's plan override
+// @Description Remove a tenant's plan override, so the plan in its evaluation contexts applies again
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param tenant path string true "Tenant ID"
+// @Success 200 {object} MessageResponse
+// @Failure 404 {object} problem.Problem "The tenant has no override"
+// @Failure 500 {object} problem.Problem
+// @Router /api/tenants/{tenant}/plan [delete]
+func (h *TenantHandler) DeleteTenantPlan(c *gin.Context) {
+	if err := h.featureService.DeleteTenantPlan(c.Request.Context(), c.Param("tenant")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "tenant plan override removed successfully"})
+}
+
+// GetEntitlements godoc
+// @Summary List a tenant's entitlements
+// @Description List every feature the tenant's plan entitles it to, whether or not the feature is enabled. The tenant's plan override is used when it has one; otherwise the plan query parameter is required.
+// @Tags tenants
+// @Produce json
+// @Security BearerAuth
+// @Param tenant path string true "Tenant ID"
+// @Param plan query string false "Plan of tenants without an override"
+// @Success 200 {object} models.Entitlements
+// @Failure 400 {object} problem.Problem "Missing or unknown plan"
+// @Failure 500 {object} problem.Problem
+// @Router /api/tenants/{tenant}/entitlements [get]
+func (h *TenantHandler) GetEntitlements(c *gin.Context) {
+	entitlements, err := h.featureService.Entitlements(c.Request.Context(), c.Param("tenant"), c.Query("plan"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entitlements)
+}
@@ -0,0 +1,162 @@
+// Package health tracks what the liveness, readiness and diagnostics
+// endpoints report: dependency checks, shutdown state, background workers
+// and build information.
+package health
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CheckTimeout bounds each readiness check.
+const CheckTimeout = 2 * time.Second
+
+// Version is the release version, set at build time with
+// -ldflags "-X feature-flags/internal/health.Version=v1.2.3". Without it the
+// module version from the build info is reported.
+var Version string
+
+type CheckResult struct {
+	Name  string `json:"name"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// Readiness is the outcome of running every readiness check.
+type Readiness struct {
+	Ready  bool          `json:"ready"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// WorkerStatus describes a background worker such as the GitOps syncer.
+type WorkerStatus struct {
+	Name    string      `json:"name"`
+	Running bool        `json:"running"`
+	Detail  interface{} `json:"detail,omitempty"`
+}
+
+type BuildInfo struct {
+	Version   string `json:"version"`
+	GoVersion string `json:"go_version"`
+	Revision  string `json:"revision,omitempty"`
+	Time      string `json:"time,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+}
+
+type check struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+type worker struct {
+	name   string
+	status func() WorkerStatus
+}
+
+type Checker struct {
+	started  time.Time
+	draining atomic.Bool
+
+	mu      sync.RWMutex
+	checks  []check
+	workers []worker
+}
+
+func NewChecker() *Checker {
+	return &Checker{started: time.Now()}
+}
+
+// AddCheck registers a readiness check. Checks run in registration order.
+func (c *Checker) AddCheck(name string, fn func(ctx context.Context) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, check{name: name, fn: fn})
+}
+
+// AddWorker registers a background worker. status is called on every
+// diagnostics request; the returned Name is ignored.
+func (c *Checker) AddWorker(name string, status func() WorkerStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.workers = append(c.workers, worker{name: name, status: status})
+}
+
+// Drain makes readiness fail from now on, so load balancers stop sending
+// traffic while in-flight requests finish.
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// Ready runs every check. A draining server is not ready, and its checks
+// are skipped.
+func (c *Checker) Ready(ctx context.Context) Readiness {
+	if c.draining.Load() {
+		return Readiness{Checks: []CheckResult{{Name: "shutdown", Error: "server is shutting down"}}}
+	}
+
+	c.mu.RLock()
+	checks := c.checks
+	c.mu.RUnlock()
+
+	readiness := Readiness{Ready: true, Checks: make([]CheckResult, 0, len(checks))}
+	for _, check := range checks {
+		result := CheckResult{Name: check.name, OK: true}
+		checkCtx, cancel := context.WithTimeout(ctx, CheckTimeout)
+		if err := check.fn(checkCtx); err != nil {
+			result.OK = false
+			result.Error = err.Error()
+			readiness.Ready = false
+		}
+		cancel()
+		readiness.Checks = append(readiness.Checks, result)
+	}
+	return readiness
+}
+
+func (c *Checker) Workers() []WorkerStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	statuses := make([]WorkerStatus, len(c.workers))
+	for i, w := range c.workers {
+		statuses[i] = w.status()
+		statuses[i].Name = w.name
+	}
+	return statuses
+}
+
+func (c *Checker) Started() time.Time {
+	return c.started
+}
+
+func (c *Checker) Uptime() time.Duration {
+	return time.Since(c.started)
+}
+
+// Build reports the version and VCS information embedded by the Go
+// toolchain.
+func Build() BuildInfo {
+	info := BuildInfo{Version: Version}
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	info.GoVersion = bi.GoVersion
+	if info.Version == "" {
+		info.Version = bi.Main.Version
+	}
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Revision = setting.Value
+		case "vcs.time":
+			info.Time = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+	return info
+}
@@ -0,0 +1,68 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReady(t *testing.T) {
+	c := NewChecker()
+	assert.True(t, c.Ready(context.Background()).Ready)
+
+	c.AddCheck("mongodb", func(ctx context.Context) error { return nil })
+	c.AddCheck("indexes", func(ctx context.Context) error {
+		return errors.New("missing indexes: change_requests.status_created_at")
+	})
+
+	readiness := c.Ready(context.Background())
+	assert.False(t, readiness.Ready)
+	assert.Equal(t, []CheckResult{
+		{Name: "mongodb", OK: true},
+		{Name: "indexes", Error: "missing indexes: change_requests.status_created_at"},
+	}, readiness.Checks)
+}
+
+func TestReady_CheckTimeout(t *testing.T) {
+	c := NewChecker()
+	c.AddCheck("mongodb", func(ctx context.Context) error {
+		_, ok := ctx.Deadline()
+		assert.True(t, ok)
+		return nil
+	})
+	assert.True(t, c.Ready(context.Background()).Ready)
+}
+
+func TestDrain(t *testing.T) {
+	c := NewChecker()
+	called := false
+	c.AddCheck("mongodb", func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	c.Drain()
+	readiness := c.Ready(context.Background())
+	assert.False(t, readiness.Ready)
+	assert.False(t, called)
+	assert.Equal(t, "shutdown", readiness.Checks[0].Name)
+}
+
+func TestWorkers(t *testing.T) {
+	c := NewChecker()
+	c.AddWorker("gitops", func() WorkerStatus {
+		return WorkerStatus{Running: true, Detail: "in sync"}
+	})
+
+	assert.Equal(t, []WorkerStatus{{Name: "gitops", Running: true, Detail: "in sync"}}, c.Workers())
+}
+
+func TestBuild(t *testing.T) {
+	assert.NotEmpty(t, Build().GoVersion)
+
+	Version = "v1.2.3"
+	defer func() { Version = "" }()
+	assert.Equal(t, "v1.2.3", Build().Version)
+}
@@ -0,0 +1,191 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"feature-flags/internal/manifest"
+	"feature-flags/internal/models"
+)
+
+// flagsmithDocument is a Flagsmith environment document
+// (GET /api/v1/environment-document/), which holds a single environment.
+type flagsmithDocument struct {
+	Name          string                  `json:"name"`
+	FeatureStates []flagsmithFeatureState `json:"feature_states"`
+	Project       struct {
+		Segments []flagsmithSegment `json:"segments"`
+	} `json:"project"`
+	IdentityOverrides []json.RawMessage `json:"identity_overrides"`
+}
+
+type flagsmithFeatureState struct {
+	Feature struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"feature"`
+	Enabled                        bool              `json:"enabled"`
+	FeatureStateValue              interface{}       `json:"feature_state_value"`
+	MultivariateFeatureStateValues []json.RawMessage `json:"multivariate_feature_state_values"`
+}
+
+type flagsmithSegment struct {
+	Name          string                  `json:"name"`
+	Rules         []flagsmithRule         `json:"rules"`
+	FeatureStates []flagsmithFeatureState `json:"feature_states"`
+}
+
+type flagsmithOverride struct {
+	segment string
+	rules   []flagsmithRule
+	enabled bool
+}
+
+type flagsmithRule struct {
+	Type       string               `json:"type"`
+	Rules      []flagsmithRule      `json:"rules"`
+	Conditions []flagsmithCondition `json:"conditions"`
+}
+
+type flagsmithCondition struct {
+	Operator string `json:"operator"`
+	Property string `json:"property_"`
+	Value    string `json:"value"`
+}
+
+func importFlagsmith(data []byte, opts Options, report *Report) (*manifest.Manifest, error) {
+	var doc flagsmithDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	env := doc.Name
+	if opts.Environment != "" && env != "" && opts.Environment != env {
+		return nil, fmt.Errorf("environment %q not found, document is for %q", opts.Environment, env)
+	}
+	if env == "" {
+		env = opts.Environment
+	}
+	report.Environment = env
+
+	// Segment overrides, by feature, in priority order.
+	overrides := make(map[string][]flagsmithOverride)
+	for _, segment := range doc.Project.Segments {
+		for _, fs := range segment.FeatureStates {
+			overrides[fs.Feature.Name] = append(overrides[fs.Feature.Name], flagsmithOverride{
+				segment: segment.Name,
+				rules:   segment.Rules,
+				enabled: fs.Enabled,
+			})
+		}
+	}
+
+	m := &manifest.Manifest{Features: []manifest.Feature{}, Dependencies: []manifest.Dependency{}}
+	for _, fs := range doc.FeatureStates {
+		name := fs.Feature.Name
+		if fs.FeatureStateValue != nil {
+			report.skip(name, "value", "remote config value %v is not supported", fs.FeatureStateValue)
+		}
+		if len(fs.MultivariateFeatureStateValues) > 0 {
+			report.skip(name, "variations", "%d multivariate values are not supported", len(fs.MultivariateFeatureStateValues))
+		}
+
+		feature := manifest.Feature{Name: name, Type: opts.Type, Enabled: fs.Enabled}
+		for _, override := range overrides[name] {
+			item := "segment " + override.segment
+			if override.enabled == fs.Enabled {
+				continue
+			}
+			if !override.enabled {
+				report.skip(name, item, "disabling the feature for a segment is not supported")
+				continue
+			}
+			if feature.Rules != nil {
+				report.skip(name, item, "only one segment override can be imported")
+				continue
+			}
+			rules, ok := flagsmithRules(name, item, override.rules, report)
+			if !ok {
+				continue
+			}
+			feature.Enabled = true
+			feature.Rules = rules
+		}
+		m.Features = append(m.Features, feature)
+	}
+
+	if len(doc.IdentityOverrides) > 0 {
+		report.skip("", "identity overrides", "%d identity overrides are not supported", len(doc.IdentityOverrides))
+	}
+	return m, nil
+}
+
+// flagsmithRules translates segment rules. ALL rules map directly onto
+// rules that must all match; an ANY rule is only translatable when its
+// conditions are equality checks on one property, which become one in rule.
+func flagsmithRules(flag, item string, segmentRules []flagsmithRule, report *Report) ([]models.TargetingRule, bool) {
+	var rules []models.TargetingRule
+	for _, r := range segmentRules {
+		switch r.Type {
+		case "ALL":
+			for _, c := range r.Conditions {
+				rule, ok := flagsmithConditionRule(c)
+				if !ok {
+					report.skip(flag, item, "condition %s %s is not supported", c.Property, c.Operator)
+					return nil, false
+				}
+				rules = append(rules, rule)
+			}
+		case "ANY":
+			rule, ok := flagsmithAnyRule(r.Conditions)
+			if !ok {
+				report.skip(flag, item, "ANY rule across properties or operators is not supported")
+				return nil, false
+			}
+			if len(r.Conditions) > 0 {
+				rules = append(rules, rule)
+			}
+		default:
+			report.skip(flag, item, "%s rule is not supported", r.Type)
+			return nil, false
+		}
+
+		nested, ok := flagsmithRules(flag, item, r.Rules, report)
+		if !ok {
+			return nil, false
+		}
+		rules = append(rules, nested...)
+	}
+	return rules, true
+}
+
+func flagsmithConditionRule(c flagsmithCondition) (models.TargetingRule, bool) {
+	switch c.Operator {
+	case "EQUAL":
+		return models.TargetingRule{Attribute: c.Property, Operator: models.RuleOperatorIn, Values: []string{c.Value}}, true
+	case "NOT_EQUAL":
+		return models.TargetingRule{Attribute: c.Property, Operator: models.RuleOperatorNotIn, Values: []string{c.Value}}, true
+	case "IN":
+		return models.TargetingRule{Attribute: c.Property, Operator: models.RuleOperatorIn, Values: splitList(c.Value)}, true
+	default:
+		return models.TargetingRule{}, false
+	}
+}
+
+func flagsmithAnyRule(conditions []flagsmithCondition) (models.TargetingRule, bool) {
+	rule := models.TargetingRule{Operator: models.RuleOperatorIn}
+	for _, c := range conditions {
+		condition, ok := flagsmithConditionRule(c)
+		if !ok || condition.Operator != models.RuleOperatorIn {
+			return models.TargetingRule{}, false
+		}
+		if rule.Attribute != "" && rule.Attribute != c.Property {
+			return models.TargetingRule{}, false
+		}
+		rule.Attribute = c.Property
+		rule.Values = append(rule.Values, condition.Values...)
+	}
+	sort.Strings(rule.Values)
+	return rule, true
+}
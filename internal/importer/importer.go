@@ -0,0 +1,162 @@
+// Package importer converts flag exports from other feature flag systems
+// (Unleash, LaunchDarkly and Flagsmith) into manifests, reporting anything
+// that has no equivalent in this service.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"feature-flags/internal/manifest"
+	"feature-flags/internal/models"
+)
+
+type Source string
+
+const (
+	SourceUnleash      Source = "unleash"
+	SourceLaunchDarkly Source = "launchdarkly"
+	SourceFlagsmith    Source = "flagsmith"
+)
+
+var ErrUnknownSource = errors.New("unknown import source")
+
+type Options struct {
+	// Environment selects which environment's state is imported. Empty picks
+	// "production" if the export has it, otherwise the first environment by
+	// name.
+	Environment string
+	// Type is given to every imported feature. Defaults to basic.
+	Type models.FeatureType
+}
+
+// Issue is something in the export that was not translated.
+type Issue struct {
+	Flag   string `json:"flag,omitempty"`
+	Item   string `json:"item"`
+	Reason string `json:"reason"`
+}
+
+// Report summarises an import.
+type Report struct {
+	Source       Source  `json:"source"`
+	Environment  string  `json:"environment"`
+	Features     int     `json:"features"`
+	Dependencies int     `json:"dependencies"`
+	Untranslated []Issue `json:"untranslated"`
+}
+
+func (r *Report) skip(flag, item, reason string, args ...interface{}) {
+	r.Untranslated = append(r.Untranslated, Issue{Flag: flag, Item: item, Reason: fmt.Sprintf(reason, args...)})
+}
+
+// String prints the report, one untranslated item per line.
+func (r *Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Imported %d features and %d dependencies from %s (environment %q).\n",
+		r.Features, r.Dependencies, r.Source, r.Environment)
+	if len(r.Untranslated) == 0 {
+		return b.String()
+	}
+	fmt.Fprintf(&b, "%d items were not translated:\n", len(r.Untranslated))
+	for _, issue := range r.Untranslated {
+		if issue.Flag == "" {
+			fmt.Fprintf(&b, "  %s: %s\n", issue.Item, issue.Reason)
+		} else {
+			fmt.Fprintf(&b, "  %s: %s: %s\n", issue.Flag, issue.Item, issue.Reason)
+		}
+	}
+	return b.String()
+}
+
+// Import converts an export from source into a validated manifest.
+func Import(source Source, data []byte, opts Options) (*manifest.Manifest, *Report, error) {
+	if opts.Type == "" {
+		opts.Type = models.FeatureTypeBasic
+	}
+
+	report := &Report{Source: source, Untranslated: []Issue{}}
+	var (
+		m   *manifest.Manifest
+		err error
+	)
+	switch source {
+	case SourceUnleash:
+		m, err = importUnleash(data, opts, report)
+	case SourceLaunchDarkly:
+		m, err = importLaunchDarkly(data, opts, report)
+	case SourceFlagsmith:
+		m, err = importFlagsmith(data, opts, report)
+	default:
+		return nil, nil, fmt.Errorf("%w %q", ErrUnknownSource, source)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s export: %w", source, err)
+	}
+
+	sort.Slice(m.Features, func(i, j int) bool { return m.Features[i].Name < m.Features[j].Name })
+	sort.Slice(m.Dependencies, func(i, j int) bool {
+		if m.Dependencies[i].Parent != m.Dependencies[j].Parent {
+			return m.Dependencies[i].Parent < m.Dependencies[j].Parent
+		}
+		return m.Dependencies[i].Child < m.Dependencies[j].Child
+	})
+	if err := m.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	report.Features = len(m.Features)
+	report.Dependencies = len(m.Dependencies)
+	return m, report, nil
+}
+
+// pickEnvironment returns the environment to import and reports the others
+// as skipped.
+func pickEnvironment(requested string, available []string, report *Report) (string, error) {
+	sort.Strings(available)
+	env := requested
+	if env == "" {
+		env = "production"
+		if !contains(available, env) && len(available) > 0 {
+			env = available[0]
+		}
+	}
+	if len(available) > 0 && !contains(available, env) {
+		return "", fmt.Errorf("environment %q not found, export has %s", env, strings.Join(available, ", "))
+	}
+
+	var others []string
+	for _, name := range available {
+		if name != env {
+			others = append(others, name)
+		}
+	}
+	if len(others) > 0 {
+		report.skip("", "environments", "only %q is imported, skipped %s", env, strings.Join(others, ", "))
+	}
+	report.Environment = env
+	return env, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// splitList splits a comma separated parameter, as used by Unleash
+// strategies and Flagsmith IN conditions.
+func splitList(s string) []string {
+	var values []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
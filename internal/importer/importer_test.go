@@ -0,0 +1,201 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"feature-flags/internal/manifest"
+	"feature-flags/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findFeature(t *testing.T, m *manifest.Manifest, name string) manifest.Feature {
+	t.Helper()
+	for _, f := range m.Features {
+		if f.Name == name {
+			return f
+		}
+	}
+	t.Fatalf("feature %q not imported", name)
+	return manifest.Feature{}
+}
+
+func hasIssue(report *Report, flag, reason string) bool {
+	for _, issue := range report.Untranslated {
+		if issue.Flag == flag && strings.Contains(issue.Reason, reason) {
+			return true
+		}
+	}
+	return false
+}
+
+const unleashExportJSON = `{
+  "features": [
+    {"name": "checkout", "type": "release"},
+    {"name": "one-click", "type": "release"},
+    {"name": "beta-banner", "type": "experiment"},
+    {"name": "old", "archived": true}
+  ],
+  "featureStrategies": [
+    {"featureName": "checkout", "environment": "production", "strategyName": "default", "parameters": {}},
+    {"featureName": "one-click", "environment": "production", "strategyName": "flexibleRollout",
+     "parameters": {"rollout": "100", "stickiness": "default"},
+     "constraints": [
+       {"contextName": "country", "operator": "IN", "values": ["DE", "FR"]},
+       {"contextName": "plan", "operator": "IN", "values": ["free"], "inverted": true}
+     ]},
+    {"featureName": "one-click", "environment": "production", "strategyName": "userWithId", "parameters": {"userIds": "u1, u2"}},
+    {"featureName": "beta-banner", "environment": "production", "strategyName": "flexibleRollout", "parameters": {"rollout": 25}},
+    {"featureName": "checkout", "environment": "development", "strategyName": "default", "parameters": {}}
+  ],
+  "featureEnvironments": [
+    {"featureName": "checkout", "environment": "production", "enabled": true},
+    {"featureName": "one-click", "environment": "production", "enabled": true},
+    {"featureName": "beta-banner", "environment": "production", "enabled": true},
+    {"featureName": "checkout", "environment": "development", "enabled": false}
+  ],
+  "dependencies": [
+    {"feature": "one-click", "dependencies": [{"feature": "checkout"}]},
+    {"feature": "beta-banner", "dependencies": [{"feature": "checkout", "enabled": false}, {"feature": "missing"}]}
+  ]
+}`
+
+func TestImportUnleash(t *testing.T) {
+	m, report, err := Import(SourceUnleash, []byte(unleashExportJSON), Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "production", report.Environment)
+	assert.Equal(t, 3, report.Features)
+
+	assert.True(t, findFeature(t, m, "checkout").Enabled)
+	assert.Empty(t, findFeature(t, m, "checkout").Rules)
+
+	oneClick := findFeature(t, m, "one-click")
+	assert.True(t, oneClick.Enabled)
+	assert.Equal(t, []models.TargetingRule{
+		{Attribute: "country", Operator: models.RuleOperatorIn, Values: []string{"DE", "FR"}},
+		{Attribute: "plan", Operator: models.RuleOperatorNotIn, Values: []string{"free"}},
+	}, oneClick.Rules)
+	assert.True(t, hasIssue(report, "one-click", "only one targeted strategy"))
+
+	// A partial rollout cannot be represented, so the flag stays off.
+	assert.False(t, findFeature(t, m, "beta-banner").Enabled)
+	assert.True(t, hasIssue(report, "beta-banner", "percentage rollout of 25%"))
+	assert.True(t, hasIssue(report, "beta-banner", "parent to be disabled"))
+	assert.True(t, hasIssue(report, "beta-banner", "not in the export"))
+	assert.True(t, hasIssue(report, "old", "archived"))
+	assert.True(t, hasIssue(report, "", "skipped development"))
+
+	assert.Equal(t, []manifest.Dependency{{Parent: "checkout", Child: "one-click"}}, m.Dependencies)
+
+	m, report, err = Import(SourceUnleash, []byte(unleashExportJSON), Options{Environment: "development"})
+	require.NoError(t, err)
+	assert.Equal(t, "development", report.Environment)
+	assert.False(t, findFeature(t, m, "checkout").Enabled)
+
+	_, _, err = Import(SourceUnleash, []byte(unleashExportJSON), Options{Environment: "staging"})
+	assert.Error(t, err)
+}
+
+const launchDarklyExportJSON = `{"items": [
+  {"key": "checkout", "kind": "boolean", "variations": [{"value": true}, {"value": false}],
+   "environments": {
+     "production": {"on": true, "fallthrough": {"variation": 0}, "offVariation": 1,
+       "targets": [{"values": ["blocked-user"], "variation": 1}],
+       "rules": [{"variation": 1, "clauses": [{"attribute": "country", "op": "in", "values": ["CN"]}]}]},
+     "test": {"on": false, "fallthrough": {"variation": 0}, "offVariation": 1}
+   }},
+  {"key": "one-click", "kind": "boolean", "variations": [{"value": true}, {"value": false}],
+   "environments": {"production": {"on": true, "fallthrough": {"variation": 1}, "offVariation": 1,
+     "rules": [
+       {"variation": 0, "clauses": [{"attribute": "plan", "op": "in", "values": ["pro", "enterprise"]}, {"attribute": "beta", "op": "in", "values": [true], "negate": true}]},
+       {"variation": 0, "clauses": [{"attribute": "email", "op": "endsWith", "values": ["@example.com"]}]},
+       {"rollout": {"variations": [{"variation": 0, "weight": 50000}, {"variation": 1, "weight": 50000}]}, "clauses": []}
+     ],
+     "prerequisites": [{"key": "checkout", "variation": 0}]}}},
+  {"key": "theme", "kind": "multivariate", "variations": [{"value": "dark"}, {"value": "light"}, {"value": "auto"}],
+   "environments": {"production": {"on": true, "fallthrough": {"variation": 0}, "offVariation": 1,
+     "prerequisites": [{"key": "checkout", "variation": 1}]}}}
+]}`
+
+func TestImportLaunchDarkly(t *testing.T) {
+	m, report, err := Import(SourceLaunchDarkly, []byte(launchDarklyExportJSON), Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "production", report.Environment)
+
+	// Falls through to true: everything serving false becomes not_in.
+	checkout := findFeature(t, m, "checkout")
+	assert.True(t, checkout.Enabled)
+	assert.Equal(t, []models.TargetingRule{
+		{Attribute: "key", Operator: models.RuleOperatorNotIn, Values: []string{"blocked-user"}},
+		{Attribute: "country", Operator: models.RuleOperatorNotIn, Values: []string{"CN"}},
+	}, checkout.Rules)
+
+	// Falls through to false: the first rule serving true is kept.
+	oneClick := findFeature(t, m, "one-click")
+	assert.True(t, oneClick.Enabled)
+	assert.Equal(t, []models.TargetingRule{
+		{Attribute: "plan", Operator: models.RuleOperatorIn, Values: []string{"enterprise", "pro"}},
+		{Attribute: "beta", Operator: models.RuleOperatorNotIn, Values: []string{"true"}},
+	}, oneClick.Rules)
+	assert.True(t, hasIssue(report, "one-click", `"endsWith" is not supported`))
+	assert.True(t, hasIssue(report, "one-click", "percentage rollout"))
+
+	assert.True(t, findFeature(t, m, "theme").Enabled)
+	assert.True(t, hasIssue(report, "theme", "3-variation multivariate flag"))
+	assert.True(t, hasIssue(report, "theme", "requiring variation 1"))
+
+	assert.Equal(t, []manifest.Dependency{{Parent: "checkout", Child: "one-click"}}, m.Dependencies)
+
+	m, _, err = Import(SourceLaunchDarkly, []byte(launchDarklyExportJSON), Options{Environment: "test"})
+	require.NoError(t, err)
+	assert.False(t, findFeature(t, m, "checkout").Enabled)
+	assert.False(t, findFeature(t, m, "one-click").Enabled)
+}
+
+const flagsmithDocumentJSON = `{
+  "name": "Production",
+  "feature_states": [
+    {"feature": {"name": "checkout", "type": "STANDARD"}, "enabled": true, "feature_state_value": null},
+    {"feature": {"name": "one-click", "type": "STANDARD"}, "enabled": false, "feature_state_value": null},
+    {"feature": {"name": "banner_text", "type": "STANDARD"}, "enabled": true, "feature_state_value": "Hello"}
+  ],
+  "project": {"segments": [
+    {"name": "eu-pro", "rules": [{"type": "ALL", "conditions": [{"operator": "EQUAL", "property_": "plan", "value": "pro"}],
+       "rules": [{"type": "ANY", "conditions": [
+         {"operator": "EQUAL", "property_": "country", "value": "DE"},
+         {"operator": "EQUAL", "property_": "country", "value": "FR"}]}]}],
+     "feature_states": [{"feature": {"name": "one-click"}, "enabled": true}]},
+    {"name": "internal", "rules": [{"type": "ALL", "conditions": [{"operator": "REGEX", "property_": "email", "value": ".*@example.com"}]}],
+     "feature_states": [{"feature": {"name": "checkout"}, "enabled": false}]}
+  ]},
+  "identity_overrides": [{"identifier": "user-1"}]
+}`
+
+func TestImportFlagsmith(t *testing.T) {
+	m, report, err := Import(SourceFlagsmith, []byte(flagsmithDocumentJSON), Options{Type: models.FeatureTypePremium})
+	require.NoError(t, err)
+	assert.Equal(t, "Production", report.Environment)
+
+	oneClick := findFeature(t, m, "one-click")
+	assert.True(t, oneClick.Enabled)
+	assert.Equal(t, models.FeatureTypePremium, oneClick.Type)
+	assert.Equal(t, []models.TargetingRule{
+		{Attribute: "plan", Operator: models.RuleOperatorIn, Values: []string{"pro"}},
+		{Attribute: "country", Operator: models.RuleOperatorIn, Values: []string{"DE", "FR"}},
+	}, oneClick.Rules)
+
+	assert.True(t, findFeature(t, m, "checkout").Enabled)
+	assert.True(t, hasIssue(report, "checkout", "disabling the feature for a segment"))
+	assert.True(t, hasIssue(report, "banner_text", "remote config value Hello"))
+	assert.True(t, hasIssue(report, "", "1 identity overrides"))
+
+	_, _, err = Import(SourceFlagsmith, []byte(flagsmithDocumentJSON), Options{Environment: "Staging"})
+	assert.Error(t, err)
+}
+
+func TestImport_UnknownSource(t *testing.T) {
+	_, _, err := Import("split", []byte(`{}`), Options{})
+	assert.ErrorIs(t, err, ErrUnknownSource)
+}
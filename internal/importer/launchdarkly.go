@@ -0,0 +1,278 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"feature-flags/internal/manifest"
+	"feature-flags/internal/models"
+)
+
+// ldFlag is a flag as returned by the LaunchDarkly REST API
+// (GET /api/v2/flags/{projectKey}?summary=0), either as an {"items": [...]}
+// page or a bare array.
+type ldFlag struct {
+	Key          string                   `json:"key"`
+	Kind         string                   `json:"kind"`
+	Archived     bool                     `json:"archived"`
+	Variations   []ldVariation            `json:"variations"`
+	Environments map[string]ldEnvironment `json:"environments"`
+}
+
+type ldVariation struct {
+	Value interface{} `json:"value"`
+}
+
+type ldEnvironment struct {
+	On             bool                 `json:"on"`
+	Targets        []ldTarget           `json:"targets"`
+	ContextTargets []ldTarget           `json:"contextTargets"`
+	Rules          []ldRule             `json:"rules"`
+	Fallthrough    ldVariationOrRollout `json:"fallthrough"`
+	OffVariation   *int                 `json:"offVariation"`
+	Prerequisites  []ldPrerequisite     `json:"prerequisites"`
+}
+
+type ldTarget struct {
+	Values      []string `json:"values"`
+	Variation   int      `json:"variation"`
+	ContextKind string   `json:"contextKind"`
+}
+
+type ldVariationOrRollout struct {
+	Variation *int            `json:"variation"`
+	Rollout   json.RawMessage `json:"rollout"`
+}
+
+type ldRule struct {
+	ldVariationOrRollout
+	Clauses []ldClause `json:"clauses"`
+}
+
+type ldClause struct {
+	Attribute   string        `json:"attribute"`
+	Op          string        `json:"op"`
+	Values      []interface{} `json:"values"`
+	Negate      bool          `json:"negate"`
+	ContextKind string        `json:"contextKind"`
+}
+
+type ldPrerequisite struct {
+	Key       string `json:"key"`
+	Variation int    `json:"variation"`
+}
+
+// serves reports which boolean a variation index stands for.
+func (f ldFlag) serves(variation int) (bool, bool) {
+	if variation < 0 || variation >= len(f.Variations) {
+		return false, false
+	}
+	value, ok := f.Variations[variation].Value.(bool)
+	return value, ok
+}
+
+func (f ldFlag) boolean() bool {
+	if len(f.Variations) != 2 {
+		return false
+	}
+	_, ok0 := f.serves(0)
+	_, ok1 := f.serves(1)
+	return ok0 && ok1
+}
+
+func importLaunchDarkly(data []byte, opts Options, report *Report) (*manifest.Manifest, error) {
+	var flags []ldFlag
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(data, &flags); err != nil {
+			return nil, err
+		}
+	} else {
+		var page struct {
+			Items []ldFlag `json:"items"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return nil, err
+		}
+		flags = page.Items
+	}
+
+	var environments []string
+	seen := make(map[string]bool)
+	for _, f := range flags {
+		for name := range f.Environments {
+			if !seen[name] {
+				seen[name] = true
+				environments = append(environments, name)
+			}
+		}
+	}
+	env, err := pickEnvironment(opts.Environment, environments, report)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]ldFlag, len(flags))
+	m := &manifest.Manifest{Features: []manifest.Feature{}, Dependencies: []manifest.Dependency{}}
+	for _, f := range flags {
+		if f.Archived {
+			report.skip(f.Key, "flag", "archived, not imported")
+			continue
+		}
+		byKey[f.Key] = f
+
+		state, ok := f.Environments[env]
+		if !ok {
+			report.skip(f.Key, "flag", "has no %s environment; imported disabled", env)
+		}
+		feature := manifest.Feature{Name: f.Key, Type: opts.Type}
+		if !f.boolean() {
+			report.skip(f.Key, "variations", "%d-variation %s flag imported as on/off only", len(f.Variations), f.Kind)
+			feature.Enabled = state.On
+		} else {
+			feature.Enabled, feature.Rules = ldRules(f, state, report)
+		}
+		m.Features = append(m.Features, feature)
+	}
+
+	for _, f := range flags {
+		if _, ok := byKey[f.Key]; !ok {
+			continue
+		}
+		for _, p := range f.Environments[env].Prerequisites {
+			item := "prerequisite " + p.Key
+			parent, ok := byKey[p.Key]
+			if !ok {
+				report.skip(f.Key, item, "prerequisite flag is not in the export")
+				continue
+			}
+			if value, ok := parent.serves(p.Variation); !ok || !value {
+				report.skip(f.Key, item, "requiring variation %d of the prerequisite is not supported", p.Variation)
+				continue
+			}
+			m.Dependencies = append(m.Dependencies, manifest.Dependency{Parent: p.Key, Child: f.Key})
+		}
+	}
+	return m, nil
+}
+
+// ldRules translates the targeting of a boolean flag. Rules in this service
+// must all match, so when the flag falls through to true every target or
+// rule serving false becomes a not_in rule, and when it falls through to
+// false only one target or rule serving true can be kept.
+func ldRules(f ldFlag, state ldEnvironment, report *Report) (bool, []models.TargetingRule) {
+	if !state.On {
+		if state.OffVariation != nil {
+			if value, _ := f.serves(*state.OffVariation); value {
+				report.skip(f.Key, "offVariation", "serves true while off; imported disabled")
+			}
+		}
+		return false, nil
+	}
+
+	fallthroughValue := false
+	if state.Fallthrough.Variation != nil {
+		fallthroughValue, _ = f.serves(*state.Fallthrough.Variation)
+	} else if len(state.Fallthrough.Rollout) > 0 {
+		report.skip(f.Key, "fallthrough", "percentage rollout is not supported; falls through to false")
+	}
+
+	type source struct {
+		item  string
+		value bool
+		rules []models.TargetingRule
+	}
+	var sources []source
+	for i, t := range append(append([]ldTarget{}, state.Targets...), state.ContextTargets...) {
+		value, _ := f.serves(t.Variation)
+		attribute := "key"
+		if t.ContextKind != "" && t.ContextKind != "user" {
+			attribute = t.ContextKind + ".key"
+		}
+		sources = append(sources, source{
+			item:  fmt.Sprintf("targets[%d]", i),
+			value: value,
+			rules: []models.TargetingRule{{Attribute: attribute, Operator: models.RuleOperatorIn, Values: t.Values}},
+		})
+	}
+	for i, r := range state.Rules {
+		item := fmt.Sprintf("rules[%d]", i)
+		if r.Variation == nil {
+			report.skip(f.Key, item, "percentage rollout is not supported")
+			continue
+		}
+		value, _ := f.serves(*r.Variation)
+		rules, ok := ldClauseRules(f.Key, item, r.Clauses, report)
+		if !ok {
+			continue
+		}
+		sources = append(sources, source{item: item, value: value, rules: rules})
+	}
+
+	var rules []models.TargetingRule
+	if fallthroughValue {
+		for _, s := range sources {
+			if s.value {
+				continue
+			}
+			if len(s.rules) != 1 {
+				report.skip(f.Key, s.item, "serving false to contexts matching several clauses is not supported")
+				continue
+			}
+			rule := s.rules[0]
+			rule.Operator = negate(rule.Operator)
+			rules = append(rules, rule)
+		}
+		return true, rules
+	}
+
+	for _, s := range sources {
+		if !s.value {
+			continue
+		}
+		if rules != nil {
+			report.skip(f.Key, s.item, "only one target or rule serving true can be imported")
+			continue
+		}
+		rules = s.rules
+	}
+	if rules == nil {
+		// On, but everyone gets false.
+		return false, nil
+	}
+	return true, rules
+}
+
+func ldClauseRules(flag, item string, clauses []ldClause, report *Report) ([]models.TargetingRule, bool) {
+	rules := make([]models.TargetingRule, 0, len(clauses))
+	for _, c := range clauses {
+		if c.Op != "in" {
+			report.skip(flag, item, "clause operator %q is not supported", c.Op)
+			return nil, false
+		}
+		values := make([]string, len(c.Values))
+		for i, v := range c.Values {
+			values[i] = fmt.Sprint(v)
+		}
+		sort.Strings(values)
+
+		attribute := c.Attribute
+		if c.ContextKind != "" && c.ContextKind != "user" {
+			attribute = c.ContextKind + "." + c.Attribute
+		}
+		operator := models.RuleOperatorIn
+		if c.Negate {
+			operator = models.RuleOperatorNotIn
+		}
+		rules = append(rules, models.TargetingRule{Attribute: attribute, Operator: operator, Values: values})
+	}
+	return rules, true
+}
+
+func negate(operator models.RuleOperator) models.RuleOperator {
+	if operator == models.RuleOperatorIn {
+		return models.RuleOperatorNotIn
+	}
+	return models.RuleOperatorIn
+}
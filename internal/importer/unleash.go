@@ -0,0 +1,283 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"feature-flags/internal/manifest"
+	"feature-flags/internal/models"
+)
+
+// unleashExport covers both the state export (/api/admin/state/export) and
+// the feature export (/api/admin/features-batch/export) of Unleash.
+type unleashExport struct {
+	Features            []unleashFeature            `json:"features"`
+	FeatureStrategies   []unleashStrategy           `json:"featureStrategies"`
+	FeatureEnvironments []unleashFeatureEnvironment `json:"featureEnvironments"`
+	Environments        []struct {
+		Name string `json:"name"`
+	} `json:"environments"`
+	Dependencies []unleashDependencies `json:"dependencies"`
+}
+
+type unleashFeature struct {
+	Name     string `json:"name"`
+	Archived bool   `json:"archived"`
+	// Enabled and Strategies are set by exports from before environments.
+	Enabled    *bool             `json:"enabled"`
+	Strategies []unleashStrategy `json:"strategies"`
+	Variants   []json.RawMessage `json:"variants"`
+}
+
+type unleashStrategy struct {
+	FeatureName  string                 `json:"featureName"`
+	Environment  string                 `json:"environment"`
+	Name         string                 `json:"name"`
+	StrategyName string                 `json:"strategyName"`
+	Parameters   map[string]interface{} `json:"parameters"`
+	Constraints  []unleashConstraint    `json:"constraints"`
+	Disabled     bool                   `json:"disabled"`
+}
+
+func (s unleashStrategy) name() string {
+	if s.StrategyName != "" {
+		return s.StrategyName
+	}
+	return s.Name
+}
+
+func (s unleashStrategy) param(key string) string {
+	switch v := s.Parameters[key].(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+type unleashConstraint struct {
+	ContextName     string   `json:"contextName"`
+	Operator        string   `json:"operator"`
+	Values          []string `json:"values"`
+	Value           string   `json:"value"`
+	Inverted        bool     `json:"inverted"`
+	CaseInsensitive bool     `json:"caseInsensitive"`
+}
+
+type unleashFeatureEnvironment struct {
+	FeatureName string            `json:"featureName"`
+	Environment string            `json:"environment"`
+	Enabled     bool              `json:"enabled"`
+	Variants    []json.RawMessage `json:"variants"`
+}
+
+type unleashDependencies struct {
+	Feature      string `json:"feature"`
+	Dependencies []struct {
+		Feature  string   `json:"feature"`
+		Enabled  *bool    `json:"enabled"`
+		Variants []string `json:"variants"`
+	} `json:"dependencies"`
+}
+
+func importUnleash(data []byte, opts Options, report *Report) (*manifest.Manifest, error) {
+	var export unleashExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, err
+	}
+
+	var environments []string
+	seen := make(map[string]bool)
+	addEnvironment := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			environments = append(environments, name)
+		}
+	}
+	for _, e := range export.Environments {
+		addEnvironment(e.Name)
+	}
+	for _, fe := range export.FeatureEnvironments {
+		addEnvironment(fe.Environment)
+	}
+	env, err := pickEnvironment(opts.Environment, environments, report)
+	if err != nil {
+		return nil, err
+	}
+
+	featureEnvs := make(map[string]unleashFeatureEnvironment)
+	for _, fe := range export.FeatureEnvironments {
+		if fe.Environment == env {
+			featureEnvs[fe.FeatureName] = fe
+		}
+	}
+	strategies := make(map[string][]unleashStrategy)
+	for _, s := range export.FeatureStrategies {
+		if s.Environment == "" || s.Environment == env {
+			strategies[s.FeatureName] = append(strategies[s.FeatureName], s)
+		}
+	}
+
+	m := &manifest.Manifest{Features: []manifest.Feature{}, Dependencies: []manifest.Dependency{}}
+	imported := make(map[string]bool)
+	for _, f := range export.Features {
+		if f.Archived {
+			report.skip(f.Name, "feature", "archived, not imported")
+			continue
+		}
+
+		enabled := false
+		if fe, ok := featureEnvs[f.Name]; ok {
+			enabled = fe.Enabled
+			if len(fe.Variants) > 0 {
+				report.skip(f.Name, "variants", "%d variants in %s are not supported", len(fe.Variants), env)
+			}
+		} else if f.Enabled != nil {
+			enabled = *f.Enabled
+		}
+		if len(f.Variants) > 0 {
+			report.skip(f.Name, "variants", "%d variants are not supported", len(f.Variants))
+		}
+
+		feature := manifest.Feature{Name: f.Name, Type: opts.Type, Enabled: enabled}
+		if enabled {
+			feature.Enabled, feature.Rules = unleashRules(f.Name, append(strategies[f.Name], f.Strategies...), report)
+		}
+		m.Features = append(m.Features, feature)
+		imported[f.Name] = true
+	}
+
+	for _, d := range export.Dependencies {
+		if !imported[d.Feature] {
+			continue
+		}
+		for _, parent := range d.Dependencies {
+			item := "dependency on " + parent.Feature
+			switch {
+			case !imported[parent.Feature]:
+				report.skip(d.Feature, item, "parent is not in the export")
+			case parent.Enabled != nil && !*parent.Enabled:
+				report.skip(d.Feature, item, "requiring the parent to be disabled is not supported")
+			case len(parent.Variants) > 0:
+				report.skip(d.Feature, item, "requiring parent variants %s is not supported", strings.Join(parent.Variants, ", "))
+			default:
+				m.Dependencies = append(m.Dependencies, manifest.Dependency{Parent: parent.Feature, Child: d.Feature})
+			}
+		}
+	}
+	return m, nil
+}
+
+// unleashRules translates the strategies of an enabled feature. Strategies
+// are alternatives, so a strategy that targets everyone makes the feature
+// on for everyone, and only one targeted strategy can be kept. A feature
+// none of whose strategies can be translated is imported disabled.
+func unleashRules(flag string, strategies []unleashStrategy, report *Report) (bool, []models.TargetingRule) {
+	var (
+		rules      []models.TargetingRule
+		translated int
+		everyone   bool
+	)
+	for i, s := range strategies {
+		if s.Disabled {
+			continue
+		}
+		item := fmt.Sprintf("strategy %s", s.name())
+		strategyRules, ok := unleashStrategyRules(flag, item, s, report)
+		if !ok {
+			continue
+		}
+		translated++
+		if len(strategyRules) == 0 {
+			everyone = true
+			continue
+		}
+		if rules != nil {
+			report.skip(flag, fmt.Sprintf("strategies[%d] %s", i, s.name()), "only one targeted strategy can be imported")
+			continue
+		}
+		rules = strategyRules
+	}
+
+	switch {
+	case everyone:
+		return true, nil
+	case translated > 0:
+		return true, rules
+	case len(strategies) == 0:
+		report.skip(flag, "strategies", "enabled without strategies, which Unleash evaluates as off; imported disabled")
+		return false, nil
+	default:
+		report.skip(flag, "feature", "no strategy could be translated; imported disabled")
+		return false, nil
+	}
+}
+
+func unleashStrategyRules(flag, item string, s unleashStrategy, report *Report) ([]models.TargetingRule, bool) {
+	var rules []models.TargetingRule
+
+	switch s.name() {
+	case "default":
+	case "flexibleRollout", "gradualRolloutRandom", "gradualRolloutUserId", "gradualRolloutSessionId":
+		rollout := s.param("rollout")
+		if rollout == "" {
+			rollout = s.param("percentage")
+		}
+		if rollout != "100" {
+			report.skip(flag, item, "percentage rollout of %s%% is not supported", rollout)
+			return nil, false
+		}
+	case "userWithId":
+		rules = append(rules, models.TargetingRule{Attribute: "userId", Operator: models.RuleOperatorIn, Values: splitList(s.param("userIds"))})
+	case "remoteAddress":
+		ips := splitList(s.param("IPs"))
+		for _, ip := range ips {
+			if strings.Contains(ip, "/") {
+				report.skip(flag, item, "CIDR range %s is not supported", ip)
+				return nil, false
+			}
+		}
+		rules = append(rules, models.TargetingRule{Attribute: "remoteAddress", Operator: models.RuleOperatorIn, Values: ips})
+	default:
+		report.skip(flag, item, "strategy has no equivalent")
+		return nil, false
+	}
+
+	for _, c := range s.Constraints {
+		rule, ok := unleashConstraintRule(c)
+		if !ok {
+			report.skip(flag, item, "constraint %s %s is not supported", c.ContextName, c.Operator)
+			return nil, false
+		}
+		rules = append(rules, rule)
+	}
+	return rules, true
+}
+
+func unleashConstraintRule(c unleashConstraint) (models.TargetingRule, bool) {
+	if c.CaseInsensitive {
+		return models.TargetingRule{}, false
+	}
+
+	var operator models.RuleOperator
+	switch c.Operator {
+	case "IN":
+		operator = models.RuleOperatorIn
+	case "NOT_IN":
+		operator = models.RuleOperatorNotIn
+	default:
+		return models.TargetingRule{}, false
+	}
+	if c.Inverted {
+		operator = negate(operator)
+	}
+
+	values := c.Values
+	if len(values) == 0 && c.Value != "" {
+		values = []string{c.Value}
+	}
+	return models.TargetingRule{Attribute: c.ContextName, Operator: operator, Values: values}, true
+}
@@ -0,0 +1,172 @@
+// Package killswitch turns features off when the error signals reported for
+// them trip their guard rules. A monitor checks the rules on an interval and
+// sends a notification for every trip.
+package killswitch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"feature-flags/internal/logging"
+	"feature-flags/internal/models"
+)
+
+type Config struct {
+	// Interval is how often guard rules are checked.
+	Interval time.Duration
+}
+
+// Checker is the part of the feature service the monitor drives.
+type Checker interface {
+	CheckGuards(ctx context.Context) ([]*models.AuditEntry, error)
+}
+
+// Notifier is told about every trip, whether or not it disabled the feature.
+type Notifier interface {
+	Notify(ctx context.Context, entry *models.AuditEntry) error
+}
+
+// Status reports the monitor's progress since startup.
+type Status struct {
+	Interval  string     `json:"interval"`
+	LastCheck *time.Time `json:"last_check,omitempty"`
+	Trips     int64      `json:"trips"`
+	// Notified counts the trips every notifier was told about.
+	Notified  int64  `json:"notified"`
+	LastTrip  string `json:"last_trip,omitempty"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+type Monitor struct {
+	cfg       Config
+	checker   Checker
+	notifiers []Notifier
+
+	mu     sync.RWMutex
+	status Status
+}
+
+func NewMonitor(cfg Config, checker Checker, notifiers ...Notifier) *Monitor {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 15 * time.Second
+	}
+	return &Monitor{
+		cfg:       cfg,
+		checker:   checker,
+		notifiers: notifiers,
+		status:    Status{Interval: cfg.Interval.String()},
+	}
+}
+
+// Run checks the guard rules every interval until ctx is cancelled.
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := m.Check(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "kill switch check failed", logging.Error(err))
+		}
+	}
+}
+
+// Check checks the guard rules once and notifies every notifier of each
+// trip. Trips are notified even when checking other rules failed.
+func (m *Monitor) Check(ctx context.Context) error {
+	trips, err := m.checker.CheckGuards(ctx)
+
+	var errs []error
+	if err != nil {
+		errs = append(errs, err)
+	}
+	notified := 0
+	for _, entry := range trips {
+		if err := m.notify(ctx, entry); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		notified++
+	}
+	err = errors.Join(errs...)
+
+	m.mu.Lock()
+	now := time.Now()
+	m.status.LastCheck = &now
+	m.status.Trips += int64(len(trips))
+	m.status.Notified += int64(notified)
+	if len(trips) > 0 {
+		m.status.LastTrip = trips[len(trips)-1].FeatureKey
+	}
+	m.status.LastError = ""
+	if err != nil {
+		m.status.LastError = err.Error()
+	}
+	m.mu.Unlock()
+
+	return err
+}
+
+func (m *Monitor) notify(ctx context.Context, entry *models.AuditEntry) error {
+	var errs []error
+	for _, notifier := range m.notifiers {
+		if err := notifier.Notify(ctx, entry); err != nil {
+			errs = append(errs, fmt.Errorf("failed to notify of trip of %s: %w", entry.FeatureKey, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Status returns the monitor's progress since startup.
+func (m *Monitor) Status() Status {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.status
+}
+
+// Webhook posts each trip's audit entry as JSON to a URL.
+type Webhook struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhook(url string, timeout time.Duration) *Webhook {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &Webhook{url: url, client: &http.Client{Timeout: timeout}}
+}
+
+// Notify posts entry and fails unless the response has a 2xx status.
+func (w *Webhook) Notify(ctx context.Context, entry *models.AuditEntry) error {
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package killswitch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"feature-flags/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeChecker struct {
+	trips []*models.AuditEntry
+	err   error
+}
+
+func (c *fakeChecker) CheckGuards(context.Context) ([]*models.AuditEntry, error) {
+	return c.trips, c.err
+}
+
+func TestMonitor_NotifiesTrips(t *testing.T) {
+	received := make(chan models.AuditEntry, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var entry models.AuditEntry
+		if err := json.NewDecoder(r.Body).Decode(&entry); err != nil || entry.FeatureKey == "failing" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		received <- entry
+	}))
+	defer server.Close()
+
+	checker := &fakeChecker{
+		trips: []*models.AuditEntry{
+			{Action: models.AuditActionKillSwitch, FeatureKey: "checkout", Reason: "error_rate 0.2 reached threshold 0.05 over 5m", Disabled: []string{"checkout", "express-checkout"}},
+			{Action: models.AuditActionKillSwitch, FeatureKey: "failing"},
+		},
+		err: errors.New("feature search: failed to count signals"),
+	}
+	m := NewMonitor(Config{Interval: time.Minute}, checker, NewWebhook(server.URL, time.Second))
+
+	err := m.Check(context.Background())
+	assert.ErrorContains(t, err, "failed to count signals")
+	assert.ErrorContains(t, err, "failed to notify of trip of failing: webhook responded 500")
+
+	require.Len(t, received, 1)
+	entry := <-received
+	assert.Equal(t, "checkout", entry.FeatureKey)
+	assert.Equal(t, []string{"checkout", "express-checkout"}, entry.Disabled)
+
+	status := m.Status()
+	assert.Equal(t, int64(2), status.Trips)
+	assert.Equal(t, int64(1), status.Notified)
+	assert.Equal(t, "failing", status.LastTrip)
+	assert.NotNil(t, status.LastCheck)
+	assert.NotEmpty(t, status.LastError)
+
+	checker.trips, checker.err = nil, nil
+	require.NoError(t, m.Check(context.Background()))
+	assert.Empty(t, m.Status().LastError)
+	assert.Equal(t, int64(2), m.Status().Trips)
+}
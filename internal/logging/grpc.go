@@ -0,0 +1,67 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDMetadata is RequestIDHeader as gRPC metadata keys are lowercase.
+const requestIDMetadata = "x-request-id"
+
+// UnaryServerInterceptor is the gRPC counterpart of Middleware. The request
+// ID is read from and returned in the x-request-id metadata.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, done := startCall(ctx, info.FullMethod)
+		resp, err := handler(ctx, req)
+		done(err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of
+// UnaryServerInterceptor.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, done := startCall(ss.Context(), info.FullMethod)
+		err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+		done(err)
+		return err
+	}
+}
+
+func startCall(ctx context.Context, method string) (context.Context, func(error)) {
+	start := time.Now()
+
+	var supplied string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDMetadata); len(values) > 0 {
+			supplied = values[0]
+		}
+	}
+	id := requestID(supplied)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadata, id))
+	ctx = With(ctx, slog.String("request_id", id))
+
+	return ctx, func(err error) {
+		slog.LogAttrs(ctx, slog.LevelInfo, "request",
+			slog.String("method", method),
+			slog.String("code", status.Code(err).String()),
+			slog.Duration("duration", time.Since(start)),
+		)
+	}
+}
+
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,135 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"feature-flags/internal/auth"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Format selects how log records are encoded.
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatText Format = "text"
+)
+
+type Config struct {
+	// Level is one of debug, info, warn or error. Defaults to info.
+	Level string
+	// Format defaults to FormatJSON.
+	Format Format
+}
+
+// Validate checks the level and format.
+func (c Config) Validate() error {
+	_, err := c.level()
+	if err == nil && c.Format != "" && c.Format != FormatJSON && c.Format != FormatText {
+		err = fmt.Errorf("invalid log format %q", c.Format)
+	}
+	return err
+}
+
+func (c Config) level() (slog.Level, error) {
+	var level slog.Level
+	if c.Level != "" {
+		if err := level.UnmarshalText([]byte(c.Level)); err != nil {
+			return level, fmt.Errorf("invalid log level %q", c.Level)
+		}
+	}
+	return level, nil
+}
+
+// New builds a logger writing to w. Records logged with a context carry the
+// attributes added with With, the authenticated actor and the active trace.
+func New(w io.Writer, cfg Config) (*slog.Logger, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	level, _ := cfg.level()
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	if cfg.Format == FormatText {
+		handler = slog.NewTextHandler(w, opts)
+	} else {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+	return slog.New(contextHandler{handler}), nil
+}
+
+// Setup installs a logger writing to stderr as the slog default. Output
+// from the standard log package is routed through it as well.
+func Setup(cfg Config) error {
+	logger, err := New(os.Stderr, cfg)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+	return nil
+}
+
+type attrsKey struct{}
+
+// With returns a context whose log records carry attrs in addition to any
+// already added.
+func With(ctx context.Context, attrs ...slog.Attr) context.Context {
+	existing, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	merged := make([]slog.Attr, 0, len(existing)+len(attrs))
+	merged = append(merged, existing...)
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, attrsKey{}, merged)
+}
+
+// contextHandler adds the attributes carried by the record's context.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs, ok := ctx.Value(attrsKey{}).([]slog.Attr); ok {
+		r.AddAttrs(attrs...)
+	}
+	if p := auth.PrincipalFromContext(ctx); p != nil {
+		r.AddAttrs(slog.String("actor", p.Subject))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(
+			slog.String("trace_id", sc.TraceID().String()),
+			slog.String("span_id", sc.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// Error describes err as an "error" group holding its message, the type of
+// every error in its wrap chain and the message of the innermost cause.
+func Error(err error) slog.Attr {
+	var chain []string
+	cause := err
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		chain = append(chain, fmt.Sprintf("%T", e))
+		cause = e
+	}
+
+	return slog.Group("error",
+		slog.String("message", err.Error()),
+		slog.Any("chain", chain),
+		slog.String("cause", cause.Error()),
+	)
+}
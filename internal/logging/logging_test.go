@@ -0,0 +1,128 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"feature-flags/internal/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var records []map[string]interface{}
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func useLogger(t *testing.T, cfg Config) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	logger, err := New(&buf, cfg)
+	require.NoError(t, err)
+	previous := slog.Default()
+	slog.SetDefault(logger)
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buf
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(&bytes.Buffer{}, Config{Level: "verbose"})
+	assert.Error(t, err)
+	_, err = New(&bytes.Buffer{}, Config{Format: "xml"})
+	assert.Error(t, err)
+
+	buf := useLogger(t, Config{Level: "warn"})
+	slog.Info("dropped")
+	slog.Warn("kept")
+	records := decodeLines(t, buf)
+	require.Len(t, records, 1)
+	assert.Equal(t, "kept", records[0]["msg"])
+}
+
+func TestContextAttributes(t *testing.T) {
+	buf := useLogger(t, Config{})
+
+	ctx := With(context.Background(), slog.String("request_id", "req-1"))
+	ctx = With(ctx, slog.String("operation", "DisableFeature"))
+	ctx = auth.WithPrincipal(ctx, &auth.Principal{Subject: "alice"})
+	slog.InfoContext(ctx, "features disabled")
+
+	records := decodeLines(t, buf)
+	require.Len(t, records, 1)
+	assert.Equal(t, "req-1", records[0]["request_id"])
+	assert.Equal(t, "DisableFeature", records[0]["operation"])
+	assert.Equal(t, "alice", records[0]["actor"])
+}
+
+func TestError(t *testing.T) {
+	root := errors.New("connection refused")
+	err := fmt.Errorf("failed to bulk disable features: %w", root)
+
+	attr := Error(err)
+	assert.Equal(t, "error", attr.Key)
+	group := map[string]slog.Value{}
+	for _, a := range attr.Value.Group() {
+		group[a.Key] = a.Value
+	}
+	assert.Equal(t, err.Error(), group["message"].String())
+	assert.Equal(t, "connection refused", group["cause"].String())
+	assert.Equal(t, []string{"*fmt.wrapError", "*errors.errorString"}, group["chain"].Any())
+}
+
+func TestMiddleware(t *testing.T) {
+	buf := useLogger(t, Config{})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware())
+	r.POST("/api/features/:id/disable", func(c *gin.Context) {
+		_ = c.Error(fmt.Errorf("failed to get feature: %w", errors.New("timeout")))
+		c.Status(http.StatusInternalServerError)
+	})
+	r.GET("/api/features", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/features", nil))
+	generated := w.Header().Get(RequestIDHeader)
+	assert.Len(t, generated, 32)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/features/abc/disable", nil)
+	req.Header.Set(RequestIDHeader, "client-id")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "client-id", w.Header().Get(RequestIDHeader))
+
+	records := decodeLines(t, buf)
+	require.Len(t, records, 3)
+
+	assert.Equal(t, "request", records[0]["msg"])
+	assert.Equal(t, generated, records[0]["request_id"])
+	assert.Equal(t, "/api/features", records[0]["route"])
+
+	// The handler's error is logged once, before the request line.
+	assert.Equal(t, "request failed", records[1]["msg"])
+	assert.Equal(t, "client-id", records[1]["request_id"])
+	assert.Equal(t, "timeout", records[1]["error"].(map[string]interface{})["cause"])
+
+	assert.Equal(t, "ERROR", records[2]["level"])
+	assert.Equal(t, float64(http.StatusInternalServerError), records[2]["status"])
+}
@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the request ID. A client supplied value is kept
+// so IDs can be correlated across services; otherwise one is generated.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds client supplied request IDs.
+const maxRequestIDLength = 128
+
+// Middleware assigns each request an ID, echoes it in the response and adds
+// it to the request context's log attributes. Once the request completes it
+// logs one line for the request and one for each error handlers attached
+// with c.Error, so failures are logged exactly once with their cause chain.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		id := requestID(c.GetHeader(RequestIDHeader))
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(With(c.Request.Context(), slog.String("request_id", id)))
+
+		c.Next()
+
+		// Later middleware may have replaced the request, e.g. to attach the
+		// authenticated principal.
+		ctx := c.Request.Context()
+		for _, err := range c.Errors {
+			slog.ErrorContext(ctx, "request failed", Error(err.Err))
+		}
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		if status >= 500 {
+			level = slog.LevelError
+		}
+		slog.LogAttrs(ctx, level, "request",
+			slog.String("method", c.Request.Method),
+			slog.String("route", route),
+			slog.Int("status", status),
+			slog.Duration("duration", time.Since(start)),
+		)
+	}
+}
+
+func requestID(supplied string) string {
+	if supplied != "" && len(supplied) <= maxRequestIDLength {
+		return supplied
+	}
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,165 @@
+// Package manifest defines the declarative flag configuration format: a
+// YAML or JSON document listing features and the dependency edges between
+// them, and the plan describing how a server differs from it.
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"feature-flags/internal/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+var ErrInvalid = errors.New("invalid manifest")
+
+type Manifest struct {
+	Features     []Feature    `json:"features" yaml:"features"`
+	Dependencies []Dependency `json:"dependencies" yaml:"dependencies"`
+}
+
+type Feature struct {
+	Name string `json:"name" yaml:"name"`
+	// Key is set on the feature when it is created and defaults to one
+	// derived from Name. Keys cannot be changed afterwards.
+	Key              string             `json:"key,omitempty" yaml:"key,omitempty"`
+	Type             models.FeatureType `json:"type" yaml:"type"`
+	Enabled          bool               `json:"enabled" yaml:"enabled"`
+	Protected        bool               `json:"protected,omitempty" yaml:"protected,omitempty"`
+	models.Targeting `yaml:",inline"`
+}
+
+// FeatureKey returns Key, or the key derived from Name if it is not set.
+func (f *Feature) FeatureKey() string {
+	if f.Key != "" {
+		return f.Key
+	}
+	return models.KeyFromName(f.Name)
+}
+
+// Dependency is a parent -> child edge between two features, by name.
+type Dependency struct {
+	Parent string `json:"parent" yaml:"parent"`
+	Child  string `json:"child" yaml:"child"`
+}
+
+// Parse decodes a YAML or JSON manifest and validates it.
+func Parse(data []byte) (*Manifest, error) {
+	m, err := Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Decode decodes a YAML or JSON manifest without validating it, for
+// manifests split across several files.
+func Decode(data []byte) (*Manifest, error) {
+	var m Manifest
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&m); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	return &m, nil
+}
+
+// Marshal encodes the manifest as "yaml" or "json".
+func (m *Manifest) Marshal(format string) ([]byte, error) {
+	switch format {
+	case "", "yaml":
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(m); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case "json":
+		data, err := json.MarshalIndent(m, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	default:
+		return nil, fmt.Errorf("unknown manifest format %q", format)
+	}
+}
+
+// Validate checks the manifest on its own: names and keys are unique and
+// valid, types are known, targeting is valid, and dependencies reference
+// declared features. Cycles are checked when the manifest is planned against a server.
+func (m *Manifest) Validate() error {
+	var problems []string
+
+	names := make(map[string]bool, len(m.Features))
+	keys := make(map[string]string, len(m.Features))
+	for i, f := range m.Features {
+		switch {
+		case strings.TrimSpace(f.Name) == "":
+			problems = append(problems, fmt.Sprintf("features[%d]: name is required", i))
+			continue
+		case names[f.Name]:
+			problems = append(problems, fmt.Sprintf("feature %q is declared more than once", f.Name))
+		}
+		names[f.Name] = true
+
+		key := f.FeatureKey()
+		if err := models.ValidateKey(key); err != nil {
+			problems = append(problems, fmt.Sprintf("feature %q: %v", f.Name, err))
+		} else if other, ok := keys[key]; ok {
+			problems = append(problems, fmt.Sprintf("features %q and %q have the same key %q", other, f.Name, key))
+		}
+		keys[key] = f.Name
+
+		if !f.Type.Valid() {
+			problems = append(problems, fmt.Sprintf("feature %q: unknown type %q", f.Name, f.Type))
+		}
+
+		if err := f.Targeting.Validate(); err != nil {
+			for _, line := range strings.Split(err.Error(), "\n") {
+				problems = append(problems, fmt.Sprintf("feature %q: %s", f.Name, line))
+			}
+		}
+	}
+
+	edges := make(map[Dependency]bool, len(m.Dependencies))
+	for _, d := range m.Dependencies {
+		if !names[d.Parent] {
+			problems = append(problems, fmt.Sprintf("dependency %s -> %s: unknown parent %q", d.Parent, d.Child, d.Parent))
+		}
+		if !names[d.Child] {
+			problems = append(problems, fmt.Sprintf("dependency %s -> %s: unknown child %q", d.Parent, d.Child, d.Child))
+		}
+		if edges[d] {
+			problems = append(problems, fmt.Sprintf("dependency %s -> %s is declared more than once", d.Parent, d.Child))
+		}
+		edges[d] = true
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// ValidationError lists every problem found in a manifest.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid manifest: " + strings.Join(e.Problems, "; ")
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalid
+}
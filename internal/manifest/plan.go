@@ -0,0 +1,96 @@
+package manifest
+
+import (
+	"fmt"
+	"io"
+)
+
+// FieldChange is a change to one field of a feature.
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// FeatureChange is a feature being created, updated or deleted.
+type FeatureChange struct {
+	Name    string        `json:"name"`
+	Changes []FieldChange `json:"changes,omitempty"`
+}
+
+// Cascade is a feature that is disabled because a feature it depends on
+// ends up disabled, even though the manifest (or its current state) has it
+// enabled.
+type Cascade struct {
+	Name   string `json:"name"`
+	Parent string `json:"parent"`
+}
+
+// Plan is the set of changes needed to make a server match a manifest.
+type Plan struct {
+	Creates           []FeatureChange `json:"creates"`
+	Updates           []FeatureChange `json:"updates"`
+	Deletes           []FeatureChange `json:"deletes"`
+	DependencyAdds    []Dependency    `json:"dependency_adds"`
+	DependencyRemoves []Dependency    `json:"dependency_removes"`
+	Cascades          []Cascade       `json:"cascades"`
+	Applied           bool            `json:"applied"`
+}
+
+func NewPlan() *Plan {
+	return &Plan{
+		Creates:           []FeatureChange{},
+		Updates:           []FeatureChange{},
+		Deletes:           []FeatureChange{},
+		DependencyAdds:    []Dependency{},
+		DependencyRemoves: []Dependency{},
+		Cascades:          []Cascade{},
+	}
+}
+
+// Empty reports whether the server already matches the manifest.
+func (p *Plan) Empty() bool {
+	return len(p.Creates) == 0 && len(p.Updates) == 0 && len(p.Deletes) == 0 &&
+		len(p.DependencyAdds) == 0 && len(p.DependencyRemoves) == 0 && len(p.Cascades) == 0
+}
+
+// Write prints the plan in a human readable form, one change per line.
+func (p *Plan) Write(w io.Writer) {
+	for _, c := range p.Creates {
+		fmt.Fprintf(w, "+ create %s%s\n", c.Name, formatChanges(c.Changes))
+	}
+	for _, c := range p.Updates {
+		fmt.Fprintf(w, "~ update %s%s\n", c.Name, formatChanges(c.Changes))
+	}
+	for _, c := range p.Deletes {
+		fmt.Fprintf(w, "- delete %s\n", c.Name)
+	}
+	for _, d := range p.DependencyAdds {
+		fmt.Fprintf(w, "+ dependency %s -> %s\n", d.Parent, d.Child)
+	}
+	for _, d := range p.DependencyRemoves {
+		fmt.Fprintf(w, "- dependency %s -> %s\n", d.Parent, d.Child)
+	}
+	for _, c := range p.Cascades {
+		fmt.Fprintf(w, "! cascade disable %s (parent %s is disabled)\n", c.Name, c.Parent)
+	}
+	fmt.Fprintf(w, "Plan: %d to create, %d to update, %d to delete, %d dependencies to add, %d to remove, %d cascaded disables.\n",
+		len(p.Creates), len(p.Updates), len(p.Deletes), len(p.DependencyAdds), len(p.DependencyRemoves), len(p.Cascades))
+}
+
+func formatChanges(changes []FieldChange) string {
+	s := ""
+	for i, c := range changes {
+		if i == 0 {
+			s += ": "
+		} else {
+			s += ", "
+		}
+		if c.From == nil {
+			s += fmt.Sprintf("%s=%v", c.Field, c.To)
+		} else {
+			s += fmt.Sprintf("%s %v -> %v", c.Field, c.From, c.To)
+		}
+	}
+	return s
+}
@@ -0,0 +1,158 @@
+// Package metrics defines the service's Prometheus metrics and serves them
+// on /metrics.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "feature_flags"
+
+// Registry holds every metric below plus the Go runtime and process
+// collectors.
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of HTTP requests by method, route and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// FeatureOperations counts enables and disables. outcome is "applied",
+	// or "pending" when a change request was created instead.
+	FeatureOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "feature_operations_total",
+		Help:      "Enable and disable operations by outcome.",
+	}, []string{"operation", "outcome"})
+
+	DisableCascadeSize = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "disable_cascade_size",
+		Help:      "Number of features disabled by one disable, including the feature itself.",
+		Buckets:   []float64{1, 2, 3, 5, 10, 20, 50, 100},
+	})
+
+	CycleRejections = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dependency_cycle_rejections_total",
+		Help:      "Dependencies rejected because they would create a cycle.",
+	})
+
+	// DependencyGraphLoads counts loads of the in-memory dependency graph,
+	// i.e. cache misses after startup or a dependency change.
+	DependencyGraphLoads = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dependency_graph_loads_total",
+		Help:      "Loads of the cached dependency graph from MongoDB.",
+	})
+
+	// SnapshotLoads counts loads of the cached evaluation snapshot, i.e.
+	// cache misses after a write or once the snapshot has aged out.
+	SnapshotLoads = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "snapshot_loads_total",
+		Help:      "Loads of the cached evaluation snapshot from MongoDB.",
+	})
+
+	MongoOperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "mongo_operation_duration_seconds",
+		Help:      "Latency of MongoDB repository methods.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"repository", "method"})
+
+	// FlagEvaluations counts evaluations per flag. value is "true" when the
+	// flag resolved on, "false" otherwise.
+	FlagEvaluations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "flag_evaluations_total",
+		Help:      "Flag evaluations by flag and whether it resolved on.",
+	}, []string{"flag", "value"})
+
+	// ExposureEvents counts exposure events by outcome: "written", "dropped"
+	// when the buffer was full, or "failed" when writing the batch failed.
+	ExposureEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "exposure_events_total",
+		Help:      "Exposure events by whether they were written, dropped or failed.",
+	}, []string{"outcome"})
+
+	// KillSwitchTrips counts guard rules tripping by outcome: "disabled",
+	// "pending" when the feature was protected, or "failed".
+	KillSwitchTrips = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "kill_switch_trips_total",
+		Help:      "Guard rules tripped by whether the feature was disabled, left pending approval or failed to disable.",
+	}, []string{"outcome"})
+
+	// RolloutTransitions counts rollout plans moving by transition:
+	// "advanced" to a next step, "completed" at 100%, or "aborted".
+	RolloutTransitions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rollout_transitions_total",
+		Help:      "Rollout plans advanced to a next step, completed or aborted.",
+	}, []string{"transition"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestDuration,
+		FeatureOperations,
+		DisableCascadeSize,
+		CycleRejections,
+		DependencyGraphLoads,
+		SnapshotLoads,
+		MongoOperationDuration,
+		FlagEvaluations,
+		ExposureEvents,
+		KillSwitchTrips,
+		RolloutTransitions,
+	)
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// Middleware records HTTP request latency. Routes are the registered
+// patterns, e.g. /api/features/:id, so IDs do not create new series;
+// requests matching no route are recorded as "unmatched".
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		HTTPRequestDuration.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObserveMongo starts timing a repository method; call the returned
+// function when it finishes.
+func ObserveMongo(repository, method string) func() {
+	start := time.Now()
+	return func() {
+		MongoOperationDuration.WithLabelValues(repository, method).Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObserveEvaluation counts one evaluation of flag.
+func ObserveEvaluation(flag string, on bool) {
+	FlagEvaluations.WithLabelValues(flag, strconv.FormatBool(on)).Inc()
+}
@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware())
+	r.GET("/api/features/:id", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+	r.GET("/metrics", gin.WrapH(Handler()))
+
+	for _, path := range []string{"/api/features/a", "/api/features/b", "/nowhere"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	ObserveMongo("features", "List")()
+	ObserveEvaluation("checkout", true)
+	ObserveEvaluation("checkout", false)
+	ObserveEvaluation("checkout", true)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := w.Body.String()
+
+	// Both IDs are recorded under the route pattern.
+	assert.Contains(t, body, `feature_flags_http_request_duration_seconds_count{method="GET",route="/api/features/:id",status="404"} 2`)
+	assert.Contains(t, body, `feature_flags_http_request_duration_seconds_count{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, body, `feature_flags_mongo_operation_duration_seconds_count{method="List",repository="features"} 1`)
+	assert.Contains(t, body, "go_goroutines")
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(FlagEvaluations.WithLabelValues("checkout", "true")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(FlagEvaluations.WithLabelValues("checkout", "false")))
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AuditAction string
+
+const (
+	// AuditActionKillSwitch records a guard rule tripping.
+	AuditActionKillSwitch AuditAction = "kill_switch"
+	// AuditActionRolloutStep records a rollout plan moving to its next
+	// step, and AuditActionRolloutAbort one being rolled back.
+	AuditActionRolloutStep  AuditAction = "rollout_step"
+	AuditActionRolloutAbort AuditAction = "rollout_abort"
+)
+
+// AuditEntry records an action taken on a feature and why.
+type AuditEntry struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Action     AuditAction        `bson:"action" json:"action" enums:"kill_switch,rollout_step,rollout_abort"`
+	FeatureID  primitive.ObjectID `bson:"feature_id" json:"feature_id"`
+	FeatureKey string             `bson:"feature_key" json:"feature_key"`
+	Actor      string             `bson:"actor" json:"actor"`
+	Reason     string             `bson:"reason" json:"reason"`
+	// Disabled lists the keys of the features disabled, the feature itself
+	// first.
+	Disabled []string `bson:"disabled,omitempty" json:"disabled,omitempty"`
+	// Pending lists the keys of the features a change request would
+	// disable when the feature was protected. They stay enabled until it is
+	// approved.
+	Pending []string `bson:"pending,omitempty" json:"pending,omitempty"`
+	// ChangeRequestID is set when the feature was protected, so a change
+	// request was created instead of disabling it.
+	ChangeRequestID *primitive.ObjectID `bson:"change_request_id,omitempty" json:"change_request_id,omitempty"`
+	// Error is set when the action failed.
+	Error     string    `bson:"error,omitempty" json:"error,omitempty"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
+
+// AuditFilter selects audit entries, newest first. Zero fields match every
+// entry.
+type AuditFilter struct {
+	FeatureKey string
+	Action     AuditAction
+	Limit      int64
+}
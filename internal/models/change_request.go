@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ChangeRequestAction string
+
+const (
+	ChangeRequestActionEnable   ChangeRequestAction = "enable"
+	ChangeRequestActionDisable  ChangeRequestAction = "disable"
+	ChangeRequestActionAddChild ChangeRequestAction = "add_child"
+	// ChangeRequestActionSetTargeting replaces the feature's targeting with
+	// ChangeRequest.Targeting.
+	ChangeRequestActionSetTargeting ChangeRequestAction = "set_targeting"
+	// ChangeRequestActionAddOverrides adds ChangeRequest.Overrides to the
+	// feature, replacing overrides with the same keys.
+	ChangeRequestActionAddOverrides ChangeRequestAction = "add_overrides"
+	// ChangeRequestActionRemoveOverrides removes the overrides with the keys
+	// of ChangeRequest.Overrides.
+	ChangeRequestActionRemoveOverrides ChangeRequestAction = "remove_overrides"
+	// ChangeRequestActionSetRollout starts ChangeRequest.Rollout on the
+	// feature, or removes its plan when that is nil.
+	ChangeRequestActionSetRollout ChangeRequestAction = "set_rollout"
+)
+
+type ChangeRequestStatus string
+
+const (
+	ChangeRequestStatusPending  ChangeRequestStatus = "pending"
+	ChangeRequestStatusApproved ChangeRequestStatus = "approved"
+	ChangeRequestStatusRejected ChangeRequestStatus = "rejected"
+)
+
+// FeatureChange describes a single field change on a feature.
+type FeatureChange struct {
+	FeatureID primitive.ObjectID `bson:"feature_id" json:"feature_id"`
+	Name      string             `bson:"name" json:"name"`
+	Field     string             `bson:"field" json:"field"`
+	From      interface{}        `bson:"from" json:"from"`
+	To        interface{}        `bson:"to" json:"to"`
+}
+
+// DependencyChange describes a dependency edge being added.
+type DependencyChange struct {
+	ParentID primitive.ObjectID `bson:"parent_id" json:"parent_id"`
+	ChildID  primitive.ObjectID `bson:"child_id" json:"child_id"`
+}
+
+// ChangeRequestDiff is the full effect of a change request, including every
+// feature a disable would cascade to.
+type ChangeRequestDiff struct {
+	Features     []FeatureChange    `bson:"features" json:"features"`
+	Dependencies []DependencyChange `bson:"dependencies,omitempty" json:"dependencies,omitempty"`
+}
+
+type ChangeRequest struct {
+	ID            primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Action        ChangeRequestAction `bson:"action" json:"action"`
+	FeatureID     primitive.ObjectID  `bson:"feature_id" json:"feature_id"`
+	ChildID       *primitive.ObjectID `bson:"child_id,omitempty" json:"child_id,omitempty"`
+	Targeting     *Targeting          `bson:"targeting,omitempty" json:"targeting,omitempty"`
+	Overrides     []Override          `bson:"overrides,omitempty" json:"overrides,omitempty"`
+	Rollout       *RolloutPlan        `bson:"rollout,omitempty" json:"rollout,omitempty"`
+	Status        ChangeRequestStatus `bson:"status" json:"status"`
+	Diff          ChangeRequestDiff   `bson:"diff" json:"diff"`
+	RequestedBy   string              `bson:"requested_by" json:"requested_by"`
+	ReviewedBy    string              `bson:"reviewed_by,omitempty" json:"reviewed_by,omitempty"`
+	ReviewComment string              `bson:"review_comment,omitempty" json:"review_comment,omitempty"`
+	ReviewedAt    *time.Time          `bson:"reviewed_at,omitempty" json:"reviewed_at,omitempty"`
+	CreatedAt     time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time           `bson:"updated_at" json:"updated_at"`
+}
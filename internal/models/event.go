@@ -0,0 +1,90 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Exposure records that a variant of a flag was served to the context with
+// TargetingKey.
+type Exposure struct {
+	FlagKey      string    `bson:"flag_key" json:"flag_key"`
+	Variant      string    `bson:"variant" json:"variant"`
+	TargetingKey string    `bson:"targeting_key" json:"targeting_key"`
+	Reason       string    `bson:"reason" json:"reason"`
+	Timestamp    time.Time `bson:"timestamp" json:"timestamp"`
+}
+
+// Conversion records that the context with TargetingKey completed Event,
+// e.g. a purchase. Value is optional, e.g. the amount of the purchase.
+type Conversion struct {
+	Event        string    `bson:"event" json:"event" example:"checkout_completed"`
+	TargetingKey string    `bson:"targeting_key" json:"targeting_key" example:"user-1"`
+	Value        float64   `bson:"value,omitempty" json:"value,omitempty" example:"49.90"`
+	Timestamp    time.Time `bson:"timestamp" json:"timestamp"`
+}
+
+// Validate checks that the conversion names its event and context.
+func (c *Conversion) Validate() error {
+	var errs []error
+	if c.Event == "" {
+		errs = append(errs, errors.New("event is required"))
+	}
+	if c.TargetingKey == "" {
+		errs = append(errs, errors.New("targeting_key is required"))
+	}
+	return errors.Join(errs...)
+}
+
+// EventFilter selects exposures by flag and conversions by event, within
+// [Since, Until). Zero fields match every event.
+type EventFilter struct {
+	FlagKey string
+	Event   string
+	Since   time.Time
+	Until   time.Time
+}
+
+// ExposedUnit is a context's first exposure to an experiment.
+type ExposedUnit struct {
+	TargetingKey string    `bson:"_id"`
+	Variant      string    `bson:"variant"`
+	ExposedAt    time.Time `bson:"exposed_at"`
+}
+
+// ExperimentResults compares the conversion rates of the variants of a
+// flag for one conversion event.
+type ExperimentResults struct {
+	FlagKey string     `json:"flag_key"`
+	Event   string     `json:"event"`
+	Control string     `json:"control"`
+	Since   *time.Time `json:"since,omitempty"`
+	Until   *time.Time `json:"until,omitempty"`
+	// Confidence is the level of every interval, e.g. 0.95.
+	Confidence float64         `json:"confidence"`
+	Variants   []VariantResult `json:"variants"`
+}
+
+// VariantResult is the conversion rate of one variant, with its confidence
+// interval.
+type VariantResult struct {
+	Variant        string  `json:"variant"`
+	Exposed        int     `json:"exposed"`
+	Converted      int     `json:"converted"`
+	ConversionRate float64 `json:"conversion_rate"`
+	Lower          float64 `json:"lower"`
+	Upper          float64 `json:"upper"`
+	// Difference compares the variant with the control. It is nil for the
+	// control itself and for variants without exposures.
+	Difference *Difference `json:"difference,omitempty"`
+}
+
+// Difference is the absolute difference between a variant's conversion
+// rate and the control's, with its confidence interval and the two-sided
+// p-value of a two-proportion z-test.
+type Difference struct {
+	Value  float64 `json:"value"`
+	Lower  float64 `json:"lower"`
+	Upper  float64 `json:"upper"`
+	PValue float64 `json:"p_value"`
+}
@@ -1,8 +1,14 @@
 package models
 
 import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
+	"unicode"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -14,11 +20,275 @@ const (
 	FeatureTypeEnterprise FeatureType = "enterprise"
 )
 
+type RuleOperator string
+
+const (
+	RuleOperatorIn    RuleOperator = "in"
+	RuleOperatorNotIn RuleOperator = "not_in"
+	// RuleOperatorInSegment matches contexts in any of the segments whose
+	// keys are listed in Values, and RuleOperatorNotInSegment contexts in
+	// none of them. Segment rules have no attribute.
+	RuleOperatorInSegment    RuleOperator = "in_segment"
+	RuleOperatorNotInSegment RuleOperator = "not_in_segment"
+)
+
+// Segment reports whether the operator matches segments rather than an
+// attribute.
+func (o RuleOperator) Segment() bool {
+	return o == RuleOperatorInSegment || o == RuleOperatorNotInSegment
+}
+
+// TargetingRule matches evaluation contexts whose attribute value is (or is
+// not) one of Values, or that are (or are not) in one of the segments Values
+// names. An enabled feature with rules is on only for contexts that match all
+// of them.
+type TargetingRule struct {
+	Attribute string       `bson:"attribute" json:"attribute" yaml:"attribute"`
+	Operator  RuleOperator `bson:"operator" json:"operator" yaml:"operator"`
+	Values    []string     `bson:"values" json:"values" yaml:"values"`
+}
+
+// Variant is a named value a feature resolves to. Features without variants
+// resolve to true when on and false when off.
+type Variant struct {
+	Name  string      `bson:"name" json:"name" yaml:"name"`
+	Value interface{} `bson:"value" json:"value" yaml:"value"`
+	// Weight splits contexts between variants by targeting key when any
+	// variant has a weight.
+	Weight int `bson:"weight,omitempty" json:"weight,omitempty" yaml:"weight,omitempty"`
+}
+
+// UnmarshalBSON decodes embedded documents and arrays in Value as plain maps
+// and slices, so variant values encode to JSON as they were written.
+func (v *Variant) UnmarshalBSON(data []byte) error {
+	type plain Variant
+	var decoded plain
+	if err := bson.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	decoded.Value = plainValue(decoded.Value)
+	*v = Variant(decoded)
+	return nil
+}
+
+func plainValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case primitive.D:
+		m := make(map[string]interface{}, len(v))
+		for _, e := range v {
+			m[e.Key] = plainValue(e.Value)
+		}
+		return m
+	case primitive.A:
+		s := make([]interface{}, len(v))
+		for i, e := range v {
+			s[i] = plainValue(e)
+		}
+		return s
+	default:
+		return v
+	}
+}
+
+// Targeting decides what a feature resolves to for an evaluation context.
+type Targeting struct {
+	Rules    []TargetingRule `bson:"rules,omitempty" json:"rules,omitempty" yaml:"rules,omitempty"`
+	Variants []Variant       `bson:"variants,omitempty" json:"variants,omitempty" yaml:"variants,omitempty"`
+	// DefaultVariant is served when the feature is on and no weights are
+	// set. OffVariant is served when it is off; without one, callers fall
+	// back to their own default.
+	DefaultVariant string `bson:"default_variant,omitempty" json:"default_variant,omitempty" yaml:"default_variant,omitempty"`
+	OffVariant     string `bson:"off_variant,omitempty" json:"off_variant,omitempty" yaml:"off_variant,omitempty"`
+}
+
+// Validate checks rule operators and that variant references resolve.
+// Whether referenced segments exist is left to the caller.
+func (t *Targeting) Validate() error {
+	errs := validateRules(t.Rules, true)
+
+	names := make(map[string]bool, len(t.Variants))
+	for i, variant := range t.Variants {
+		switch {
+		case variant.Name == "":
+			errs = append(errs, fmt.Errorf("variants[%d]: name is required", i))
+		case names[variant.Name]:
+			errs = append(errs, fmt.Errorf("variant %q is declared more than once", variant.Name))
+		}
+		if variant.Weight < 0 {
+			errs = append(errs, fmt.Errorf("variant %q: weight must not be negative", variant.Name))
+		}
+		names[variant.Name] = true
+	}
+	if t.DefaultVariant != "" && !names[t.DefaultVariant] {
+		errs = append(errs, fmt.Errorf("default_variant: unknown variant %q", t.DefaultVariant))
+	}
+	if t.OffVariant != "" && !names[t.OffVariant] {
+		errs = append(errs, fmt.Errorf("off_variant: unknown variant %q", t.OffVariant))
+	}
+	if len(t.Variants) > 0 && t.DefaultVariant == "" && !t.Weighted() {
+		errs = append(errs, errors.New("default_variant is required when variants have no weights"))
+	}
+	return errors.Join(errs...)
+}
+
+// validateRules checks the attribute, operator and values of each rule.
+// Segment operators are only accepted if segments is set.
+func validateRules(rules []TargetingRule, segments bool) []error {
+	var errs []error
+	for i, rule := range rules {
+		switch {
+		case rule.Operator == RuleOperatorIn, rule.Operator == RuleOperatorNotIn:
+			if rule.Attribute == "" {
+				errs = append(errs, fmt.Errorf("rules[%d]: attribute is required", i))
+			}
+		case rule.Operator.Segment() && !segments:
+			errs = append(errs, fmt.Errorf("rules[%d]: segment rules cannot reference other segments", i))
+		case rule.Operator.Segment():
+			if rule.Attribute != "" {
+				errs = append(errs, fmt.Errorf("rules[%d]: segment rules have no attribute", i))
+			}
+			if len(rule.Values) == 0 {
+				errs = append(errs, fmt.Errorf("rules[%d]: at least one segment key is required", i))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("rules[%d]: unknown operator %q", i, rule.Operator))
+		}
+	}
+	return errs
+}
+
+// SegmentKeys returns the keys of the segments the rules reference, each
+// once, in the order they first appear.
+func (t *Targeting) SegmentKeys() []string {
+	var keys []string
+	for _, rule := range t.Rules {
+		if !rule.Operator.Segment() {
+			continue
+		}
+		for _, key := range rule.Values {
+			if !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+// Weighted reports whether contexts are split between variants.
+func (t *Targeting) Weighted() bool {
+	for _, variant := range t.Variants {
+		if variant.Weight > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 type Feature struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Name      string             `bson:"name" json:"name"`
-	Type      FeatureType        `bson:"type" json:"type"`
-	IsEnabled bool               `bson:"is_enabled" json:"is_enabled"`
-	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+	ID primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	// Key identifies the feature in application code and evaluation. It is
+	// unique and never changes once the feature is created. Features created
+	// before keys existed have none until they are migrated.
+	Key       string      `bson:"key,omitempty" json:"key"`
+	Name      string      `bson:"name" json:"name"`
+	Type      FeatureType `bson:"type" json:"type"`
+	IsEnabled bool        `bson:"is_enabled" json:"is_enabled"`
+	Protected bool        `bson:"protected" json:"protected"`
+	// Version counts changes that can change how the feature evaluates:
+	// creation, enabling, disabling, type and targeting. Metadata edits leave
+	// it unchanged.
+	Version   int64     `bson:"version" json:"version"`
+	CreatedBy string    `bson:"created_by,omitempty" json:"created_by,omitempty"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+	Targeting `bson:",inline"`
+	Metadata  `bson:",inline"`
+	// Overrides are not part of manifests, so applying one keeps them.
+	Overrides []Override `bson:"overrides,omitempty" json:"overrides,omitempty"`
+	// Guard is not part of manifests either.
+	Guard *GuardRule `bson:"guard,omitempty" json:"guard,omitempty"`
+	// Rollout is not part of manifests either.
+	Rollout *RolloutPlan `bson:"rollout,omitempty" json:"rollout,omitempty"`
+}
+
+// FeatureFilter selects the features to list. Zero fields match every
+// feature.
+type FeatureFilter struct {
+	Type    FeatureType
+	Enabled *bool
+	// Tags matches features that have every one of the tags.
+	Tags  []string
+	Owner string
+	// Segment matches features whose rules reference the segment with this
+	// key.
+	Segment string
+	// Guarded matches features with a guard rule.
+	Guarded bool
+	// Rollout matches features with a rollout plan in this status.
+	Rollout RolloutStatus
+}
+
+// FlagKey returns the key flags are evaluated by: Key, or the name for
+// features created before keys existed.
+func (f *Feature) FlagKey() string {
+	if f.Key != "" {
+		return f.Key
+	}
+	return f.Name
+}
+
+// MaxKeyLength is the longest key ValidateKey accepts.
+const MaxKeyLength = 128
+
+// ValidateKey checks that key is non-empty, at most MaxKeyLength long, and
+// made of lowercase letters, digits, '.', '_' and '-', starting with a
+// letter or digit.
+func ValidateKey(key string) error {
+	switch {
+	case key == "":
+		return errors.New("key is required")
+	case len(key) > MaxKeyLength:
+		return fmt.Errorf("key is longer than %d characters", MaxKeyLength)
+	}
+	for i, r := range key {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+		case (r == '.' || r == '_' || r == '-') && i > 0:
+		case r >= 'A' && r <= 'Z':
+			return fmt.Errorf("key %q must be lowercase", key)
+		case unicode.IsSpace(r):
+			return fmt.Errorf("key %q must not contain whitespace", key)
+		default:
+			return fmt.Errorf("key %q may only contain a-z, 0-9, '.', '_' and '-', and must start with a letter or digit", key)
+		}
+	}
+	return nil
+}
+
+// KeyFromName derives a key from a feature name: it is lowercased and every
+// run of other characters becomes a single '-'. The result is empty if the
+// name has no letters or digits.
+func KeyFromName(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '_':
+			if b.Len() == 0 && (r == '.' || r == '_') {
+				continue
+			}
+			if dash {
+				b.WriteByte('-')
+				dash = false
+			}
+			b.WriteRune(r)
+		default:
+			dash = b.Len() > 0
+		}
+	}
+	key := b.String()
+	if len(key) > MaxKeyLength {
+		key = strings.TrimRight(key[:MaxKeyLength], "-._")
+	}
+	return key
 }
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateKey(t *testing.T) {
+	for _, key := range []string{"checkout", "one-click", "v2.checkout_flow", "2fa"} {
+		assert.NoError(t, ValidateKey(key), key)
+	}
+
+	for key, msg := range map[string]string{
+		"":                                  "required",
+		"One-Click":                         "lowercase",
+		"one click":                         "whitespace",
+		"checkout\t":                        "whitespace",
+		"-checkout":                         "must start with a letter or digit",
+		"check/out":                         "may only contain",
+		strings.Repeat("a", MaxKeyLength+1): "longer than",
+	} {
+		assert.ErrorContains(t, ValidateKey(key), msg, key)
+	}
+}
+
+func TestKeyFromName(t *testing.T) {
+	for name, key := range map[string]string{
+		"checkout":               "checkout",
+		"One-Click Checkout":     "one-click-checkout",
+		"  New   checkout flow!": "new-checkout-flow",
+		"_beta.wallet":           "beta.wallet",
+		"???":                    "",
+	} {
+		assert.Equal(t, key, KeyFromName(name), name)
+		if key != "" {
+			assert.NoError(t, ValidateKey(key), name)
+		}
+	}
+	assert.Len(t, KeyFromName(strings.Repeat("a ", 100)), MaxKeyLength-1)
+}
+
+func TestFeature_FlagKey(t *testing.T) {
+	assert.Equal(t, "one-click", (&Feature{Key: "one-click", Name: "One click"}).FlagKey())
+	assert.Equal(t, "One click", (&Feature{Name: "One click"}).FlagKey())
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// GuardMetric is what a guard rule measures from the signals reported for
+// its flag.
+type GuardMetric string
+
+const (
+	// GuardMetricErrorRate is errors / (errors + successes).
+	GuardMetricErrorRate GuardMetric = "error_rate"
+	// GuardMetricErrorCount is the number of errors.
+	GuardMetricErrorCount GuardMetric = "error_count"
+)
+
+// MaxGuardWindow is the longest window a guard rule may look back over.
+// Signals are kept for this long.
+const MaxGuardWindow = 24 * time.Hour
+
+// GuardRule turns a feature off automatically when the metric, over the
+// signals reported for it in the last Window, reaches Threshold.
+type GuardRule struct {
+	Metric    GuardMetric `bson:"metric" json:"metric" enums:"error_rate,error_count"`
+	Threshold float64     `bson:"threshold" json:"threshold" example:"0.05"`
+	// Window is a duration such as "5m".
+	Window string `bson:"window" json:"window" example:"5m"`
+	// MinRequests is the fewest errors and successes the window must hold
+	// before the rule is checked, so a single early error cannot trip an
+	// error rate.
+	MinRequests int64 `bson:"min_requests,omitempty" json:"min_requests,omitempty" example:"100"`
+	// TrippedAt is when the rule last tripped. Signals reported before it
+	// are not counted again.
+	TrippedAt *time.Time `bson:"tripped_at,omitempty" json:"tripped_at,omitempty"`
+}
+
+// Validate checks that the metric is known, the threshold fits it and the
+// window is a positive duration of at most MaxGuardWindow.
+func (g *GuardRule) Validate() error {
+	var errs []error
+	switch g.Metric {
+	case GuardMetricErrorRate:
+		if g.Threshold <= 0 || g.Threshold > 1 {
+			errs = append(errs, errors.New("threshold: an error rate must be above 0 and at most 1"))
+		}
+	case GuardMetricErrorCount:
+		if g.Threshold < 1 {
+			errs = append(errs, errors.New("threshold: an error count must be at least 1"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("metric: unknown metric %q", g.Metric))
+	}
+	window, err := time.ParseDuration(g.Window)
+	switch {
+	case err != nil:
+		errs = append(errs, fmt.Errorf("window: %v", err))
+	case window <= 0 || window > MaxGuardWindow:
+		errs = append(errs, fmt.Errorf("window: must be above 0 and at most %s", MaxGuardWindow))
+	}
+	if g.MinRequests < 0 {
+		errs = append(errs, errors.New("min_requests must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// WindowDuration returns Window parsed. Call it on validated rules only.
+func (g *GuardRule) WindowDuration() time.Duration {
+	window, _ := time.ParseDuration(g.Window)
+	return window
+}
+
+// Check returns the metric for counts and whether it reaches the threshold.
+// Counts with fewer than MinRequests requests, or none, never trip.
+func (g *GuardRule) Check(counts SignalCounts) (value float64, tripped bool) {
+	total := counts.Errors + counts.Successes
+	if total == 0 || total < g.MinRequests {
+		return 0, false
+	}
+	switch g.Metric {
+	case GuardMetricErrorRate:
+		value = float64(counts.Errors) / float64(total)
+	case GuardMetricErrorCount:
+		value = float64(counts.Errors)
+	default:
+		return 0, false
+	}
+	return value, value >= g.Threshold
+}
+
+// Signal reports how many calls a service made under a flag failed and
+// succeeded.
+type Signal struct {
+	FlagKey   string    `bson:"flag_key" json:"flag_key" example:"new-checkout"`
+	Errors    int64     `bson:"errors" json:"errors" example:"3"`
+	Successes int64     `bson:"successes" json:"successes" example:"97"`
+	Timestamp time.Time `bson:"timestamp" json:"timestamp"`
+}
+
+// Validate checks that the signal names its flag and counts something.
+func (s *Signal) Validate() error {
+	var errs []error
+	if s.FlagKey == "" {
+		errs = append(errs, errors.New("flag_key is required"))
+	}
+	if s.Errors < 0 || s.Successes < 0 {
+		errs = append(errs, errors.New("errors and successes must not be negative"))
+	} else if s.Errors+s.Successes == 0 {
+		errs = append(errs, errors.New("errors or successes is required"))
+	}
+	return errors.Join(errs...)
+}
+
+// SignalCounts sums the signals reported for a flag.
+type SignalCounts struct {
+	Errors    int64 `bson:"errors" json:"errors"`
+	Successes int64 `bson:"successes" json:"successes"`
+}
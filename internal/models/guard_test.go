@@ -0,0 +1,50 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuardRule_Validate(t *testing.T) {
+	assert.NoError(t, (&GuardRule{Metric: GuardMetricErrorRate, Threshold: 0.05, Window: "5m", MinRequests: 100}).Validate())
+	assert.NoError(t, (&GuardRule{Metric: GuardMetricErrorCount, Threshold: 50, Window: "1h"}).Validate())
+
+	err := (&GuardRule{Metric: GuardMetricErrorRate, Threshold: 5, Window: "48h", MinRequests: -1}).Validate()
+	assert.ErrorContains(t, err, "an error rate must be above 0 and at most 1")
+	assert.ErrorContains(t, err, "window: must be above 0 and at most 24h0m0s")
+	assert.ErrorContains(t, err, "min_requests must not be negative")
+
+	err = (&GuardRule{Metric: "latency", Threshold: 1, Window: "soon"}).Validate()
+	assert.ErrorContains(t, err, `unknown metric "latency"`)
+	assert.ErrorContains(t, err, "window: time: invalid duration")
+}
+
+func TestGuardRule_Check(t *testing.T) {
+	rate := &GuardRule{Metric: GuardMetricErrorRate, Threshold: 0.1, Window: "5m", MinRequests: 20}
+
+	value, tripped := rate.Check(SignalCounts{Errors: 3, Successes: 7})
+	assert.False(t, tripped, "fewer requests than min_requests")
+	assert.Zero(t, value)
+
+	value, tripped = rate.Check(SignalCounts{Errors: 2, Successes: 18})
+	assert.True(t, tripped)
+	assert.InDelta(t, 0.1, value, 1e-9)
+
+	_, tripped = rate.Check(SignalCounts{Errors: 1, Successes: 99})
+	assert.False(t, tripped)
+
+	count := &GuardRule{Metric: GuardMetricErrorCount, Threshold: 5, Window: "5m"}
+	_, tripped = count.Check(SignalCounts{})
+	assert.False(t, tripped)
+	value, tripped = count.Check(SignalCounts{Errors: 5, Successes: 1000})
+	assert.True(t, tripped)
+	assert.Equal(t, 5.0, value)
+}
+
+func TestSignal_Validate(t *testing.T) {
+	assert.NoError(t, (&Signal{FlagKey: "checkout", Errors: 1}).Validate())
+	assert.ErrorContains(t, (&Signal{}).Validate(), "flag_key is required")
+	assert.ErrorContains(t, (&Signal{FlagKey: "checkout"}).Validate(), "errors or successes is required")
+	assert.ErrorContains(t, (&Signal{FlagKey: "checkout", Errors: -1}).Validate(), "must not be negative")
+}
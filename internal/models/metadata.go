@@ -0,0 +1,175 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Link points at a ticket, pull request or document about a feature.
+type Link struct {
+	Title string `bson:"title,omitempty" json:"title,omitempty"`
+	URL   string `bson:"url" json:"url"`
+}
+
+// Metadata describes a feature for the people who work on it. It plays no
+// part in evaluation, so changing it does not change the feature's Version.
+type Metadata struct {
+	Description string   `bson:"description,omitempty" json:"description,omitempty"`
+	Tags        []string `bson:"tags,omitempty" json:"tags,omitempty"`
+	// Owner is the team that owns the feature; Maintainer is how to reach
+	// the person looking after it, such as an email address.
+	Owner      string `bson:"owner,omitempty" json:"owner,omitempty"`
+	Maintainer string `bson:"maintainer,omitempty" json:"maintainer,omitempty"`
+	Links      []Link `bson:"links,omitempty" json:"links,omitempty"`
+	// Attributes holds project-defined string, number and boolean values,
+	// checked against the configured AttributeSchema.
+	Attributes map[string]interface{} `bson:"attributes,omitempty" json:"attributes,omitempty"`
+}
+
+// MaxTagLength is the longest tag Metadata.Validate accepts.
+const MaxTagLength = 64
+
+// Validate checks tags, links and attributes. Attributes are checked against
+// schema; with an empty schema any scalar attribute is accepted.
+func (m *Metadata) Validate(schema AttributeSchema) error {
+	var errs []error
+	tags := make(map[string]bool, len(m.Tags))
+	for i, tag := range m.Tags {
+		switch {
+		case tag == "":
+			errs = append(errs, fmt.Errorf("tags[%d]: tag is empty", i))
+		case len(tag) > MaxTagLength:
+			errs = append(errs, fmt.Errorf("tag %q is longer than %d characters", tag, MaxTagLength))
+		case strings.ContainsFunc(tag, unicode.IsSpace):
+			errs = append(errs, fmt.Errorf("tag %q must not contain whitespace", tag))
+		case tags[tag]:
+			errs = append(errs, fmt.Errorf("tag %q is listed more than once", tag))
+		}
+		tags[tag] = true
+	}
+	for i, link := range m.Links {
+		u, err := url.Parse(link.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("links[%d]: %q is not an http or https URL", i, link.URL))
+		}
+	}
+	if err := schema.Check(m.Attributes); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+type AttributeType string
+
+const (
+	AttributeTypeString AttributeType = "string"
+	AttributeTypeNumber AttributeType = "number"
+	AttributeTypeBool   AttributeType = "bool"
+)
+
+// AttributeDefinition declares a custom attribute features may carry.
+type AttributeDefinition struct {
+	Name     string        `yaml:"name" json:"name"`
+	Type     AttributeType `yaml:"type" json:"type"`
+	Required bool          `yaml:"required" json:"required"`
+	// Values, when set, lists the values a string attribute may take.
+	Values []string `yaml:"values,omitempty" json:"values,omitempty"`
+}
+
+// AttributeSchema lists the custom attributes of a project. When it is
+// empty attributes are free-form, limited only to scalar values.
+type AttributeSchema []AttributeDefinition
+
+// Validate checks the schema itself.
+func (s AttributeSchema) Validate() error {
+	var errs []error
+	names := make(map[string]bool, len(s))
+	for i, def := range s {
+		switch {
+		case def.Name == "":
+			errs = append(errs, fmt.Errorf("attributes[%d]: name is required", i))
+		case names[def.Name]:
+			errs = append(errs, fmt.Errorf("attribute %q is declared more than once", def.Name))
+		}
+		names[def.Name] = true
+		switch def.Type {
+		case AttributeTypeString, AttributeTypeNumber, AttributeTypeBool:
+		default:
+			errs = append(errs, fmt.Errorf("attribute %q: unknown type %q", def.Name, def.Type))
+		}
+		if len(def.Values) > 0 && def.Type != AttributeTypeString {
+			errs = append(errs, fmt.Errorf("attribute %q: values are only allowed for string attributes", def.Name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Check reports every attribute that is missing, undeclared or of the wrong
+// type.
+func (s AttributeSchema) Check(attributes map[string]interface{}) error {
+	var errs []error
+	if len(s) == 0 {
+		for _, name := range sortedNames(attributes) {
+			if attributeType(attributes[name]) == "" {
+				errs = append(errs, fmt.Errorf("attribute %q: value must be a string, number or boolean", name))
+			}
+		}
+		return errors.Join(errs...)
+	}
+
+	declared := make(map[string]bool, len(s))
+	for _, def := range s {
+		declared[def.Name] = true
+		value, ok := attributes[def.Name]
+		if !ok {
+			if def.Required {
+				errs = append(errs, fmt.Errorf("attribute %q is required", def.Name))
+			}
+			continue
+		}
+		if attributeType(value) != def.Type {
+			errs = append(errs, fmt.Errorf("attribute %q must be a %s", def.Name, def.Type))
+			continue
+		}
+		if str, ok := value.(string); ok && len(def.Values) > 0 && !slices.Contains(def.Values, str) {
+			errs = append(errs, fmt.Errorf("attribute %q must be one of %s", def.Name, strings.Join(def.Values, ", ")))
+		}
+	}
+	for _, name := range sortedNames(attributes) {
+		if !declared[name] {
+			errs = append(errs, fmt.Errorf("attribute %q is not declared", name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// attributeType returns the type of an attribute value as decoded from JSON
+// or BSON, or "" if it is not a scalar.
+func attributeType(value interface{}) AttributeType {
+	switch value.(type) {
+	case string:
+		return AttributeTypeString
+	case float64, float32, int, int32, int64:
+		return AttributeTypeNumber
+	case bool:
+		return AttributeTypeBool
+	default:
+		return ""
+	}
+}
+
+// sortedNames returns the attribute names in order, so errors are reported
+// in the same order every time.
+func sortedNames(attributes map[string]interface{}) []string {
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetadata_Validate(t *testing.T) {
+	valid := Metadata{
+		Description: "One-click checkout for returning customers",
+		Tags:        []string{"checkout", "q3-launch"},
+		Owner:       "payments",
+		Maintainer:  "alice@example.com",
+		Links:       []Link{{Title: "PAY-123", URL: "https://jira.example.com/browse/PAY-123"}},
+		Attributes:  map[string]interface{}{"revenue_impacting": true, "cost": 3.5},
+	}
+	assert.NoError(t, valid.Validate(nil))
+
+	invalid := Metadata{
+		Tags:       []string{"checkout", "", "q3 launch", "checkout"},
+		Links:      []Link{{URL: "jira/PAY-123"}},
+		Attributes: map[string]interface{}{"nested": map[string]interface{}{"a": 1}},
+	}
+	err := invalid.Validate(nil)
+	require.Error(t, err)
+	for _, msg := range []string{
+		"tags[1]: tag is empty",
+		`tag "q3 launch" must not contain whitespace`,
+		`tag "checkout" is listed more than once`,
+		`links[0]: "jira/PAY-123" is not an http or https URL`,
+		`attribute "nested": value must be a string, number or boolean`,
+	} {
+		assert.ErrorContains(t, err, msg)
+	}
+}
+
+func TestAttributeSchema_Check(t *testing.T) {
+	schema := AttributeSchema{
+		{Name: "tier", Type: AttributeTypeString, Required: true, Values: []string{"gold", "silver"}},
+		{Name: "cost", Type: AttributeTypeNumber},
+		{Name: "beta", Type: AttributeTypeBool},
+	}
+	require.NoError(t, schema.Validate())
+
+	assert.NoError(t, schema.Check(map[string]interface{}{"tier": "gold", "cost": float64(2), "beta": false}))
+	assert.NoError(t, schema.Check(map[string]interface{}{"tier": "silver", "cost": int32(2)}))
+
+	err := schema.Check(map[string]interface{}{"tier": "bronze", "cost": "high", "team": "payments"})
+	require.Error(t, err)
+	for _, msg := range []string{
+		`attribute "tier" must be one of gold, silver`,
+		`attribute "cost" must be a number`,
+		`attribute "team" is not declared`,
+	} {
+		assert.ErrorContains(t, err, msg)
+	}
+	assert.ErrorContains(t, schema.Check(nil), `attribute "tier" is required`)
+}
+
+func TestAttributeSchema_Validate(t *testing.T) {
+	err := AttributeSchema{
+		{Name: "tier", Type: AttributeTypeString},
+		{Name: "tier", Type: AttributeTypeString},
+		{Name: "", Type: AttributeTypeBool},
+		{Name: "cost", Type: AttributeTypeNumber, Values: []string{"1"}},
+		{Name: "size", Type: "enum"},
+	}.Validate()
+	require.Error(t, err)
+	for _, msg := range []string{
+		`attribute "tier" is declared more than once`,
+		"attributes[2]: name is required",
+		`attribute "cost": values are only allowed for string attributes`,
+		`attribute "size": unknown type "enum"`,
+	} {
+		assert.ErrorContains(t, err, msg)
+	}
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type OverrideAction string
+
+const (
+	OverrideActionAllow OverrideAction = "allow"
+	OverrideActionDeny  OverrideAction = "deny"
+)
+
+// MaxOverrides is the most overrides a feature may have. Overrides are for
+// individual tenants and users; larger audiences belong in targeting rules.
+const MaxOverrides = 1000
+
+// Override turns a feature on or off for the evaluation contexts whose
+// Attribute equals Value, ahead of IsEnabled, targeting rules and splits.
+// When an allow and a deny override both match, the deny wins.
+type Override struct {
+	Attribute string         `bson:"attribute" json:"attribute" yaml:"attribute" example:"tenantId"`
+	Value     string         `bson:"value" json:"value" yaml:"value" example:"acme"`
+	Action    OverrideAction `bson:"action" json:"action" yaml:"action" enums:"allow,deny"`
+	// Variant is served by an allow override instead of the variant the
+	// feature would serve when on.
+	Variant string `bson:"variant,omitempty" json:"variant,omitempty" yaml:"variant,omitempty"`
+	// BypassDependencies serves an allow override even where a parent
+	// feature is off. Without it the feature stays off with its parent.
+	BypassDependencies bool      `bson:"bypass_dependencies,omitempty" json:"bypass_dependencies,omitempty" yaml:"bypass_dependencies,omitempty"`
+	Reason             string    `bson:"reason,omitempty" json:"reason,omitempty" yaml:"reason,omitempty" example:"early access for the Q3 pilot"`
+	CreatedBy          string    `bson:"created_by,omitempty" json:"created_by,omitempty" yaml:"created_by,omitempty"`
+	CreatedAt          time.Time `bson:"created_at" json:"created_at" yaml:"created_at"`
+}
+
+// OverrideKey identifies an override on a feature. A feature has at most
+// one override per key.
+type OverrideKey struct {
+	Attribute string `bson:"attribute" json:"attribute" binding:"required" example:"tenantId"`
+	Value     string `bson:"value" json:"value" binding:"required" example:"acme"`
+}
+
+func (o *Override) Key() OverrideKey {
+	return OverrideKey{Attribute: o.Attribute, Value: o.Value}
+}
+
+// Validate checks the override against the targeting of its feature, whose
+// variants an allow override may name.
+func (o *Override) Validate(targeting *Targeting) error {
+	var errs []error
+	if o.Attribute == "" || o.Value == "" {
+		errs = append(errs, errors.New("attribute and value are required"))
+	}
+	switch o.Action {
+	case OverrideActionAllow:
+		if o.Variant != "" && !targeting.HasVariant(o.Variant) {
+			errs = append(errs, fmt.Errorf("unknown variant %q", o.Variant))
+		}
+	case OverrideActionDeny:
+		if o.Variant != "" || o.BypassDependencies {
+			errs = append(errs, errors.New("variant and bypass_dependencies only apply to allow overrides"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unknown action %q", o.Action))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("override %s=%s: %w", o.Attribute, o.Value, err)
+	}
+	return nil
+}
+
+// HasVariant reports whether the targeting declares a variant named name.
+func (t *Targeting) HasVariant(name string) bool {
+	for _, variant := range t.Variants {
+		if variant.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverride_Validate(t *testing.T) {
+	targeting := &Targeting{Variants: []Variant{{Name: "grid", Value: "grid"}}, DefaultVariant: "grid"}
+
+	assert.NoError(t, (&Override{Attribute: "tenantId", Value: "acme", Action: OverrideActionAllow, Variant: "grid", BypassDependencies: true}).Validate(targeting))
+	assert.NoError(t, (&Override{Attribute: "userId", Value: "42", Action: OverrideActionDeny}).Validate(targeting))
+
+	err := (&Override{Attribute: "tenantId", Value: "acme", Action: OverrideActionAllow, Variant: "list"}).Validate(targeting)
+	assert.ErrorContains(t, err, `override tenantId=acme: unknown variant "list"`)
+	err = (&Override{Attribute: "tenantId", Value: "acme", Action: OverrideActionDeny, BypassDependencies: true}).Validate(targeting)
+	assert.ErrorContains(t, err, "only apply to allow overrides")
+	err = (&Override{Action: "block"}).Validate(targeting)
+	assert.ErrorContains(t, err, "attribute and value are required")
+	assert.ErrorContains(t, err, `unknown action "block"`)
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// FeatureTypes lists the known feature types, from the lowest plan to the
+// highest.
+var FeatureTypes = []FeatureType{FeatureTypeBasic, FeatureTypePremium, FeatureTypeEnterprise}
+
+// Valid reports whether t is one of FeatureTypes.
+func (t FeatureType) Valid() bool {
+	return slices.Contains(FeatureTypes, t)
+}
+
+// PlanHierarchy lists plans from lowest to highest. An account on a plan is
+// entitled to the features of its plan and of every plan below it. A
+// feature's type names the lowest plan entitled to it, so every FeatureType
+// must be a plan; other plans, such as a free tier below basic, may be
+// added around them.
+type PlanHierarchy []string
+
+// DefaultPlanHierarchy has one plan per feature type.
+func DefaultPlanHierarchy() PlanHierarchy {
+	plans := make(PlanHierarchy, len(FeatureTypes))
+	for i, t := range FeatureTypes {
+		plans[i] = string(t)
+	}
+	return plans
+}
+
+// Validate checks that plans are named once and that every feature type is a
+// plan, in the order of FeatureTypes.
+func (h PlanHierarchy) Validate() error {
+	var errs []error
+	seen := make(map[string]bool, len(h))
+	for i, plan := range h {
+		switch {
+		case plan == "":
+			errs = append(errs, fmt.Errorf("plans[%d]: name is required", i))
+		case seen[plan]:
+			errs = append(errs, fmt.Errorf("plan %q is listed more than once", plan))
+		}
+		seen[plan] = true
+	}
+	last := -1
+	for _, t := range FeatureTypes {
+		rank, ok := h.Rank(string(t))
+		switch {
+		case !ok:
+			errs = append(errs, fmt.Errorf("feature type %q must be a plan", t))
+		case rank < last:
+			errs = append(errs, fmt.Errorf("plan %q must rank above the plans of lower feature types", t))
+		default:
+			last = rank
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Rank returns the position of plan in the hierarchy, lowest first.
+func (h PlanHierarchy) Rank(plan string) (int, bool) {
+	rank := slices.Index(h, plan)
+	return rank, rank >= 0
+}
+
+// Entitled reports whether an account on plan is entitled to features of
+// type t. Unknown plans are entitled to nothing.
+func (h PlanHierarchy) Entitled(plan string, t FeatureType) bool {
+	rank, ok := h.Rank(plan)
+	if !ok {
+		return false
+	}
+	required, ok := h.Rank(string(t))
+	return ok && rank >= required
+}
+
+// Entitlements lists the features a tenant's plan entitles it to.
+type Entitlements struct {
+	TenantID string `json:"tenant_id,omitempty"`
+	Plan     string `json:"plan" example:"premium"`
+	// Override is set when Plan is the tenant's override rather than the
+	// plan given in the request.
+	Override bool       `json:"override"`
+	Features []*Feature `json:"features"`
+}
+
+// TenantPlan puts one tenant on a plan regardless of the plan its
+// evaluation contexts carry, e.g. for a trial or a negotiated contract.
+type TenantPlan struct {
+	TenantID string `bson:"_id" json:"tenant_id"`
+	Plan     string `bson:"plan" json:"plan" example:"enterprise"`
+	// Reason records why the tenant was moved, for whoever reviews it later.
+	Reason    string    `bson:"reason,omitempty" json:"reason,omitempty" example:"enterprise trial until 2026-12-31"`
+	UpdatedBy string    `bson:"updated_by,omitempty" json:"updated_by,omitempty"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
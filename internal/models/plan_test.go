@@ -0,0 +1,28 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanHierarchy_Validate(t *testing.T) {
+	assert.NoError(t, DefaultPlanHierarchy().Validate())
+	assert.NoError(t, PlanHierarchy{"free", "basic", "team", "premium", "enterprise"}.Validate())
+
+	err := PlanHierarchy{"basic", "enterprise", "premium", "basic", ""}.Validate()
+	assert.ErrorContains(t, err, `plan "basic" is listed more than once`)
+	assert.ErrorContains(t, err, "plans[4]: name is required")
+	assert.ErrorContains(t, err, `plan "enterprise" must rank above`)
+	assert.ErrorContains(t, PlanHierarchy{"basic", "premium"}.Validate(), `feature type "enterprise" must be a plan`)
+}
+
+func TestPlanHierarchy_Entitled(t *testing.T) {
+	plans := PlanHierarchy{"free", "basic", "premium", "enterprise"}
+	assert.True(t, plans.Entitled("premium", FeatureTypeBasic))
+	assert.True(t, plans.Entitled("premium", FeatureTypePremium))
+	assert.False(t, plans.Entitled("premium", FeatureTypeEnterprise))
+	assert.False(t, plans.Entitled("free", FeatureTypeBasic))
+	assert.False(t, plans.Entitled("gold", FeatureTypeBasic))
+	assert.False(t, plans.Entitled("enterprise", FeatureType("custom")))
+}
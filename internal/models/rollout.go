@@ -0,0 +1,140 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RolloutStatus is where a rollout plan is in its life.
+type RolloutStatus string
+
+const (
+	// RolloutStatusActive plans advance once the current step's hold has
+	// passed.
+	RolloutStatusActive RolloutStatus = "active"
+	// RolloutStatusPaused plans stay at their current step until resumed.
+	RolloutStatusPaused RolloutStatus = "paused"
+	// RolloutStatusCompleted plans reached their last step, 100%.
+	RolloutStatusCompleted RolloutStatus = "completed"
+	// RolloutStatusAborted plans were rolled back to 0%.
+	RolloutStatusAborted RolloutStatus = "aborted"
+)
+
+// MaxRolloutSteps is the most steps a rollout plan may have.
+const MaxRolloutSteps = 20
+
+// RolloutStep serves the feature to Percentage of targeting keys for Hold
+// before the plan moves to the next step.
+type RolloutStep struct {
+	Percentage float64 `bson:"percentage" json:"percentage" example:"5"`
+	// Hold is a duration such as "1h". The last step has none.
+	Hold string `bson:"hold,omitempty" json:"hold,omitempty" example:"1h"`
+}
+
+// RolloutPlan releases a feature gradually: an enabled feature with a plan
+// is only on for the share of targeting keys its current step allows.
+type RolloutPlan struct {
+	Steps  []RolloutStep `bson:"steps" json:"steps"`
+	Status RolloutStatus `bson:"status" json:"status" enums:"active,paused,completed,aborted"`
+	// Step is the index of the current step.
+	Step          int       `bson:"step" json:"step"`
+	StepStartedAt time.Time `bson:"step_started_at" json:"step_started_at"`
+	// PausedAt is set while the plan is paused. The time spent paused does
+	// not count towards the hold.
+	PausedAt *time.Time `bson:"paused_at,omitempty" json:"paused_at,omitempty"`
+}
+
+// Validate checks that there are between 2 and MaxRolloutSteps steps, that
+// their percentages rise to 100 and that every step but the last holds for
+// a positive duration.
+func (p *RolloutPlan) Validate() error {
+	if len(p.Steps) < 2 || len(p.Steps) > MaxRolloutSteps {
+		return fmt.Errorf("steps: a plan must have between 2 and %d steps", MaxRolloutSteps)
+	}
+	var errs []error
+	last := len(p.Steps) - 1
+	for i, step := range p.Steps {
+		switch {
+		case step.Percentage <= 0 || step.Percentage > 100:
+			errs = append(errs, fmt.Errorf("steps[%d]: percentage must be above 0 and at most 100", i))
+		case i > 0 && step.Percentage <= p.Steps[i-1].Percentage:
+			errs = append(errs, fmt.Errorf("steps[%d]: percentage must be above that of the step before", i))
+		}
+		if i == last {
+			if step.Hold != "" {
+				errs = append(errs, fmt.Errorf("steps[%d]: the last step has no hold", i))
+			}
+			continue
+		}
+		hold, err := time.ParseDuration(step.Hold)
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("steps[%d]: hold: %v", i, err))
+		case hold <= 0:
+			errs = append(errs, fmt.Errorf("steps[%d]: hold must be above 0", i))
+		}
+	}
+	if p.Steps[last].Percentage != 100 {
+		errs = append(errs, errors.New("steps: the last step must be 100%"))
+	}
+	return errors.Join(errs...)
+}
+
+// Percentage returns the share of targeting keys the feature is on for:
+// that of the current step, 100 once completed and 0 once aborted.
+func (p *RolloutPlan) Percentage() float64 {
+	switch p.Status {
+	case RolloutStatusCompleted:
+		return 100
+	case RolloutStatusAborted:
+		return 0
+	}
+	if p.Step < 0 || p.Step >= len(p.Steps) {
+		return 0
+	}
+	return p.Steps[p.Step].Percentage
+}
+
+// Due reports whether an active plan has held its current step long enough
+// at now to move to the next. Call it on validated plans only.
+func (p *RolloutPlan) Due(now time.Time) bool {
+	if p.Status != RolloutStatusActive || p.Step >= len(p.Steps)-1 {
+		return false
+	}
+	hold, _ := time.ParseDuration(p.Steps[p.Step].Hold)
+	return !now.Before(p.StepStartedAt.Add(hold))
+}
+
+// Advance moves the plan to its next step at now, completing it on the
+// last.
+func (p *RolloutPlan) Advance(now time.Time) {
+	p.Step++
+	p.StepStartedAt = now
+	if p.Step >= len(p.Steps)-1 {
+		p.Step = len(p.Steps) - 1
+		p.Status = RolloutStatusCompleted
+	}
+}
+
+// Pause stops the plan at its current step.
+func (p *RolloutPlan) Pause(now time.Time) {
+	p.Status = RolloutStatusPaused
+	p.PausedAt = &now
+}
+
+// Resume continues a paused plan, extending the current step's hold by the
+// time spent paused.
+func (p *RolloutPlan) Resume(now time.Time) {
+	if p.PausedAt != nil {
+		p.StepStartedAt = p.StepStartedAt.Add(now.Sub(*p.PausedAt))
+	}
+	p.Status = RolloutStatusActive
+	p.PausedAt = nil
+}
+
+// Abort rolls the plan back to 0%.
+func (p *RolloutPlan) Abort() {
+	p.Status = RolloutStatusAborted
+	p.PausedAt = nil
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRolloutPlan_Validate(t *testing.T) {
+	plan := &RolloutPlan{Steps: []RolloutStep{{Percentage: 1, Hold: "1h"}, {Percentage: 5, Hold: "1h"}, {Percentage: 25, Hold: "24h"}, {Percentage: 100}}}
+	assert.NoError(t, plan.Validate())
+
+	assert.ErrorContains(t, (&RolloutPlan{Steps: []RolloutStep{{Percentage: 100}}}).Validate(), "between 2 and 20 steps")
+
+	err := (&RolloutPlan{Steps: []RolloutStep{{Percentage: 0, Hold: "1h"}, {Percentage: 50}, {Percentage: 40, Hold: "1h"}}}).Validate()
+	assert.ErrorContains(t, err, "steps[0]: percentage must be above 0 and at most 100")
+	assert.ErrorContains(t, err, "steps[1]: hold: time: invalid duration")
+	assert.ErrorContains(t, err, "steps[2]: percentage must be above that of the step before")
+	assert.ErrorContains(t, err, "steps[2]: the last step has no hold")
+	assert.ErrorContains(t, err, "the last step must be 100%")
+
+	err = (&RolloutPlan{Steps: []RolloutStep{{Percentage: 10, Hold: "-1m"}, {Percentage: 100}}}).Validate()
+	assert.ErrorContains(t, err, "steps[0]: hold must be above 0")
+}
+
+func TestRolloutPlan_Lifecycle(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	plan := &RolloutPlan{
+		Steps:         []RolloutStep{{Percentage: 5, Hold: "1h"}, {Percentage: 50, Hold: "1h"}, {Percentage: 100}},
+		Status:        RolloutStatusActive,
+		StepStartedAt: start,
+	}
+	assert.Equal(t, 5.0, plan.Percentage())
+	assert.False(t, plan.Due(start.Add(59*time.Minute)))
+	assert.True(t, plan.Due(start.Add(time.Hour)))
+
+	plan.Advance(start.Add(time.Hour))
+	assert.Equal(t, 50.0, plan.Percentage())
+	assert.Equal(t, RolloutStatusActive, plan.Status)
+
+	// Time spent paused does not count towards the hold.
+	plan.Pause(start.Add(90 * time.Minute))
+	assert.False(t, plan.Due(start.Add(3*time.Hour)))
+	assert.Equal(t, 50.0, plan.Percentage())
+	plan.Resume(start.Add(150 * time.Minute))
+	assert.Nil(t, plan.PausedAt)
+	assert.False(t, plan.Due(start.Add(179*time.Minute)))
+	assert.True(t, plan.Due(start.Add(180*time.Minute)))
+
+	plan.Advance(start.Add(180 * time.Minute))
+	assert.Equal(t, RolloutStatusCompleted, plan.Status)
+	assert.Equal(t, 2, plan.Step)
+	assert.Equal(t, 100.0, plan.Percentage())
+	assert.False(t, plan.Due(start.Add(24*time.Hour)))
+
+	plan.Abort()
+	assert.Equal(t, 0.0, plan.Percentage())
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Segment is a reusable audience. Targeting rules reference it by key with
+// the in_segment and not_in_segment operators, so an edit to a segment
+// applies to every feature that uses it.
+//
+// A context is in the segment if the value of Attribute is in Included, or
+// if it matches all of Rules; a value in Excluded keeps it out either way. A
+// segment without rules contains only the contexts it includes.
+type Segment struct {
+	ID primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	// Key identifies the segment in targeting rules. It is unique and never
+	// changes once the segment is created.
+	Key         string          `bson:"key" json:"key" example:"internal-employees"`
+	Name        string          `bson:"name" json:"name" example:"Internal employees"`
+	Description string          `bson:"description,omitempty" json:"description,omitempty"`
+	Rules       []TargetingRule `bson:"rules,omitempty" json:"rules,omitempty"`
+	// Attribute is the context attribute Included and Excluded list values
+	// of. It defaults to the targeting key.
+	Attribute string   `bson:"attribute,omitempty" json:"attribute,omitempty" example:"userId"`
+	Included  []string `bson:"included,omitempty" json:"included,omitempty"`
+	Excluded  []string `bson:"excluded,omitempty" json:"excluded,omitempty"`
+	// Version counts changes to the segment, starting at 1.
+	Version   int64     `bson:"version" json:"version"`
+	CreatedBy string    `bson:"created_by,omitempty" json:"created_by,omitempty"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// Validate checks the segment's rules and lists. Its key is checked
+// separately with ValidateKey.
+func (s *Segment) Validate() error {
+	errs := validateRules(s.Rules, false)
+	if slices.Contains(s.Included, "") || slices.Contains(s.Excluded, "") {
+		errs = append(errs, errors.New("included and excluded values must not be empty"))
+	}
+	included := make(map[string]bool, len(s.Included))
+	for _, value := range s.Included {
+		included[value] = true
+	}
+	for _, value := range s.Excluded {
+		if value != "" && included[value] {
+			errs = append(errs, fmt.Errorf("%q is both included and excluded", value))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSegment_Validate(t *testing.T) {
+	segment := &Segment{
+		Rules:    []TargetingRule{{Attribute: "email_domain", Operator: RuleOperatorIn, Values: []string{"example.com"}}},
+		Included: []string{"contractor-1"},
+		Excluded: []string{"intern-7"},
+	}
+	assert.NoError(t, segment.Validate())
+
+	segment = &Segment{
+		Rules:    []TargetingRule{{Operator: RuleOperatorInSegment, Values: []string{"staff"}}},
+		Included: []string{"user-1", ""},
+		Excluded: []string{"user-1"},
+	}
+	err := segment.Validate()
+	assert.ErrorContains(t, err, "segment rules cannot reference other segments")
+	assert.ErrorContains(t, err, "must not be empty")
+	assert.ErrorContains(t, err, `"user-1" is both included and excluded`)
+}
+
+func TestTargeting_SegmentRules(t *testing.T) {
+	targeting := &Targeting{Rules: []TargetingRule{
+		{Operator: RuleOperatorInSegment, Values: []string{"staff", "beta"}},
+		{Attribute: "country", Operator: RuleOperatorIn, Values: []string{"IN"}},
+		{Operator: RuleOperatorNotInSegment, Values: []string{"staff", "churned"}},
+	}}
+	assert.NoError(t, targeting.Validate())
+	assert.Equal(t, []string{"staff", "beta", "churned"}, targeting.SegmentKeys())
+
+	targeting = &Targeting{Rules: []TargetingRule{
+		{Attribute: "country", Operator: RuleOperatorInSegment, Values: []string{"staff"}},
+		{Operator: RuleOperatorNotInSegment},
+	}}
+	err := targeting.Validate()
+	assert.ErrorContains(t, err, "rules[0]: segment rules have no attribute")
+	assert.ErrorContains(t, err, "rules[1]: at least one segment key is required")
+}
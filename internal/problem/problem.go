@@ -0,0 +1,90 @@
+// Package problem writes HTTP error responses as RFC 7807 problem details
+// (application/problem+json).
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContentType is the media type of a problem details body.
+const ContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details object. Extensions are written as
+// additional top-level members, e.g. "cycle_path".
+type Problem struct {
+	// Type identifies the kind of problem. It is "about:blank" when the
+	// status code says everything there is to say.
+	Type     string `json:"type" example:"urn:feature-flags:problem:dependency-cycle"`
+	Title    string `json:"title" example:"Dependency cycle"`
+	Status   int    `json:"status" example:"422"`
+	Detail   string `json:"detail,omitempty" example:"dependency would create a cycle"`
+	Instance string `json:"instance,omitempty" example:"/api/features/dependencies"`
+
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// New returns a problem with no specific type, titled after status.
+func New(status int, detail string) *Problem {
+	return &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// Typed returns a problem of the named type. name is a short slug such as
+// "not-found" and becomes urn:feature-flags:problem:<name>.
+func Typed(status int, name, title, detail string) *Problem {
+	return &Problem{
+		Type:   "urn:feature-flags:problem:" + name,
+		Title:  title,
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// With sets an extension member and returns p.
+func (p *Problem) With(key string, value interface{}) *Problem {
+	if p.Extensions == nil {
+		p.Extensions = make(map[string]interface{})
+	}
+	p.Extensions[key] = value
+	return p
+}
+
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	members := make(map[string]interface{}, len(p.Extensions)+5)
+	for key, value := range p.Extensions {
+		members[key] = value
+	}
+	members["type"] = p.Type
+	members["title"] = p.Title
+	members["status"] = p.Status
+	if p.Detail != "" {
+		members["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		members["instance"] = p.Instance
+	}
+	return json.Marshal(members)
+}
+
+// Write responds with p. Instance defaults to the request path.
+func Write(c *gin.Context, p *Problem) {
+	if p.Instance == "" {
+		p.Instance = c.Request.URL.Path
+	}
+	// Gin only sets the JSON content type when none is set.
+	c.Header("Content-Type", ContentType)
+	c.JSON(p.Status, p)
+}
+
+// Abort responds with p and stops the remaining handlers.
+func Abort(c *gin.Context, p *Problem) {
+	c.Abort()
+	Write(c, p)
+}
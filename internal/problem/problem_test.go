@@ -0,0 +1,55 @@
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/features/:id", func(c *gin.Context) {
+		Write(c, Typed(http.StatusUnprocessableEntity, "dependency-cycle", "Dependency cycle", "dependency would create a cycle").
+			With("cycle_path", []string{"a", "b", "a"}))
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/features/1", nil))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, map[string]interface{}{
+		"type":       "urn:feature-flags:problem:dependency-cycle",
+		"title":      "Dependency cycle",
+		"status":     float64(422),
+		"detail":     "dependency would create a cycle",
+		"instance":   "/api/features/1",
+		"cycle_path": []interface{}{"a", "b", "a"},
+	}, body)
+}
+
+func TestAbort(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	reached := false
+	router.GET("/",
+		func(c *gin.Context) { Abort(c, New(http.StatusUnauthorized, "missing bearer token")) },
+		func(c *gin.Context) { reached = true },
+	)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.False(t, reached)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.JSONEq(t, `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"missing bearer token","instance":"/"}`, w.Body.String())
+}
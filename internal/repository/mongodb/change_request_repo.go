@@ -0,0 +1,136 @@
+package mongodb
+
+import (
+	"context"
+	"feature-flags/internal/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ChangeRequestRepository struct {
+	collection *mongo.Collection
+}
+
+func NewChangeRequestRepository(db *mongo.Database) *ChangeRequestRepository {
+	return &ChangeRequestRepository{
+		collection: db.Collection("change_requests"),
+	}
+}
+
+func (r *ChangeRequestRepository) Create(ctx context.Context, request *models.ChangeRequest) error {
+	ctx, end := observe(ctx, "change_requests", "Create")
+	defer end()
+
+	request.CreatedAt = time.Now()
+	request.UpdatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	request.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *ChangeRequestRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.ChangeRequest, error) {
+	ctx, end := observe(ctx, "change_requests", "GetByID")
+	defer end()
+
+	var request models.ChangeRequest
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&request)
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// Reopen moves a change request the reviewer approved back to pending, for
+// when applying it failed without a transaction to roll the approval back.
+// A request that is not approved by reviewer is left as it is.
+func (r *ChangeRequestRepository) Reopen(ctx context.Context, id primitive.ObjectID, reviewer string) error {
+	ctx, end := observe(ctx, "change_requests", "Reopen")
+	defer end()
+
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "status": models.ChangeRequestStatusApproved, "reviewed_by": reviewer},
+		bson.M{
+			"$set":   bson.M{"status": models.ChangeRequestStatusPending, "updated_at": time.Now()},
+			"$unset": bson.M{"reviewed_by": "", "review_comment": "", "reviewed_at": ""},
+		},
+	)
+	return err
+}
+
+// List returns change requests, newest first. An empty status returns all.
+func (r *ChangeRequestRepository) List(ctx context.Context, status models.ChangeRequestStatus) ([]*models.ChangeRequest, error) {
+	ctx, end := observe(ctx, "change_requests", "List")
+	defer end()
+
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.M{"created_at": -1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	requests := make([]*models.ChangeRequest, 0)
+	if err = cursor.All(ctx, &requests); err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// FindPending returns the newest pending change request with action for the
+// feature with featureID. It returns mongo.ErrNoDocuments if there is none.
+func (r *ChangeRequestRepository) FindPending(ctx context.Context, featureID primitive.ObjectID, action models.ChangeRequestAction) (*models.ChangeRequest, error) {
+	ctx, end := observe(ctx, "change_requests", "FindPending")
+	defer end()
+
+	var request models.ChangeRequest
+	err := r.collection.FindOne(ctx,
+		bson.M{"status": models.ChangeRequestStatusPending, "action": action, "feature_id": featureID},
+		options.FindOne().SetSort(bson.M{"created_at": -1}),
+	).Decode(&request)
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// Resolve moves a pending change request to status. It returns
+// mongo.ErrNoDocuments if the request does not exist or is no longer pending,
+// so two reviewers cannot resolve the same request.
+func (r *ChangeRequestRepository) Resolve(ctx context.Context, id primitive.ObjectID, status models.ChangeRequestStatus, reviewer, comment string) (*models.ChangeRequest, error) {
+	ctx, end := observe(ctx, "change_requests", "Resolve")
+	defer end()
+
+	now := time.Now()
+
+	var request models.ChangeRequest
+	err := r.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": id, "status": models.ChangeRequestStatusPending},
+		bson.M{"$set": bson.M{
+			"status":         status,
+			"reviewed_by":    reviewer,
+			"review_comment": comment,
+			"reviewed_at":    now,
+			"updated_at":     now,
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&request)
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
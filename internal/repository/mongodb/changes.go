@@ -0,0 +1,57 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrChangeStreamsUnsupported is returned by Watch when the server is not a
+// replica set or sharded cluster.
+var ErrChangeStreamsUnsupported = errors.New("change streams need a replica set or sharded cluster")
+
+// changeHooks calls the functions registered with OnChange after every
+// write a repository makes, whether or not the write succeeded, so caches
+// built from the collection can be dropped.
+type changeHooks struct {
+	mu  sync.RWMutex
+	fns []func()
+}
+
+// OnChange registers fn to be called after every write made through the
+// repository. Writes made by other instances are only seen through Watch.
+func (h *changeHooks) OnChange(fn func()) {
+	h.mu.Lock()
+	h.fns = append(h.fns, fn)
+	h.mu.Unlock()
+}
+
+func (h *changeHooks) changed() {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, fn := range h.fns {
+		fn()
+	}
+}
+
+// watch calls onChange after every insert, update or delete in collection,
+// until ctx is done or the change stream fails.
+func watch(ctx context.Context, collection *mongo.Collection, onChange func()) error {
+	stream, err := collection.Watch(ctx, mongo.Pipeline{})
+	var cmdErr mongo.CommandError
+	// 40573: "The $changeStream stage is only supported on replica sets"
+	if errors.As(err, &cmdErr) && cmdErr.Code == 40573 {
+		return ErrChangeStreamsUnsupported
+	}
+	if err != nil {
+		return err
+	}
+	defer stream.Close(context.Background())
+
+	for stream.Next(ctx) {
+		onChange()
+	}
+	return stream.Err()
+}
@@ -0,0 +1,176 @@
+package mongodb
+
+import (
+	"context"
+	"feature-flags/internal/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EventRepository stores exposure and conversion events. Events are only
+// ever inserted and read back in bulk.
+type EventRepository struct {
+	exposures   *mongo.Collection
+	conversions *mongo.Collection
+}
+
+func NewEventRepository(db *mongo.Database) *EventRepository {
+	return &EventRepository{
+		exposures:   db.Collection("exposures"),
+		conversions: db.Collection("conversions"),
+	}
+}
+
+// InsertExposures writes a batch of exposures. The batch is unordered, so
+// one bad event does not stop the rest.
+func (r *EventRepository) InsertExposures(ctx context.Context, exposures []models.Exposure) error {
+	ctx, end := observe(ctx, "exposures", "InsertExposures")
+	defer end()
+
+	docs := make([]interface{}, len(exposures))
+	for i := range exposures {
+		docs[i] = exposures[i]
+	}
+	_, err := r.exposures.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	return err
+}
+
+func (r *EventRepository) InsertConversions(ctx context.Context, conversions []models.Conversion) error {
+	ctx, end := observe(ctx, "conversions", "InsertConversions")
+	defer end()
+
+	docs := make([]interface{}, len(conversions))
+	for i := range conversions {
+		docs[i] = conversions[i]
+	}
+	_, err := r.conversions.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	return err
+}
+
+// FirstExposures returns each targeting key's first exposure to the flag
+// in filter with one of reasons.
+func (r *EventRepository) FirstExposures(ctx context.Context, filter models.EventFilter, reasons []string) ([]models.ExposedUnit, error) {
+	ctx, end := observe(ctx, "exposures", "FirstExposures")
+	defer end()
+
+	match := exposureQuery(filter)
+	match["reason"] = bson.M{"$in": reasons}
+	cursor, err := r.exposures.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$sort", Value: bson.D{{Key: "timestamp", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":        "$targeting_key",
+			"variant":    bson.M{"$first": "$variant"},
+			"exposed_at": bson.M{"$first": "$timestamp"},
+		}}},
+	}, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	units := make([]models.ExposedUnit, 0)
+	if err := cursor.All(ctx, &units); err != nil {
+		return nil, err
+	}
+	return units, nil
+}
+
+// LastConversions returns the time of each targeting key's latest
+// conversion for the event in filter.
+func (r *EventRepository) LastConversions(ctx context.Context, filter models.EventFilter) (map[string]time.Time, error) {
+	ctx, end := observe(ctx, "conversions", "LastConversions")
+	defer end()
+
+	cursor, err := r.conversions.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: conversionQuery(filter)}},
+		{{Key: "$group", Value: bson.M{"_id": "$targeting_key", "at": bson.M{"$max": "$timestamp"}}}},
+	}, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	last := make(map[string]time.Time)
+	for cursor.Next(ctx) {
+		var row struct {
+			TargetingKey string    `bson:"_id"`
+			At           time.Time `bson:"at"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			return nil, err
+		}
+		last[row.TargetingKey] = row.At
+	}
+	return last, cursor.Err()
+}
+
+// EachExposure calls fn with every exposure matching filter, oldest first,
+// without loading them all at once. It stops at the first error fn returns.
+func (r *EventRepository) EachExposure(ctx context.Context, filter models.EventFilter, fn func(*models.Exposure) error) error {
+	ctx, end := observe(ctx, "exposures", "EachExposure")
+	defer end()
+
+	return each(ctx, r.exposures, exposureQuery(filter), fn)
+}
+
+// EachConversion is EachExposure for conversions.
+func (r *EventRepository) EachConversion(ctx context.Context, filter models.EventFilter, fn func(*models.Conversion) error) error {
+	ctx, end := observe(ctx, "conversions", "EachConversion")
+	defer end()
+
+	return each(ctx, r.conversions, conversionQuery(filter), fn)
+}
+
+func each[T any](ctx context.Context, collection *mongo.Collection, query bson.M, fn func(*T) error) error {
+	cursor, err := collection.Find(ctx, query, options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var event T
+		if err := cursor.Decode(&event); err != nil {
+			return err
+		}
+		if err := fn(&event); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func exposureQuery(filter models.EventFilter) bson.M {
+	query := timeQuery(filter)
+	if filter.FlagKey != "" {
+		query["flag_key"] = filter.FlagKey
+	}
+	return query
+}
+
+func conversionQuery(filter models.EventFilter) bson.M {
+	query := timeQuery(filter)
+	if filter.Event != "" {
+		query["event"] = filter.Event
+	}
+	return query
+}
+
+func timeQuery(filter models.EventFilter) bson.M {
+	query := bson.M{}
+	timestamp := bson.M{}
+	if !filter.Since.IsZero() {
+		timestamp["$gte"] = filter.Since
+	}
+	if !filter.Until.IsZero() {
+		timestamp["$lt"] = filter.Until
+	}
+	if len(timestamp) > 0 {
+		query["timestamp"] = timestamp
+	}
+	return query
+}
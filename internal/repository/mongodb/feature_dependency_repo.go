@@ -3,6 +3,7 @@ package mongodb
 import (
 	"context"
 	"feature-flags/internal/models"
+	"feature-flags/internal/tracing"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -12,6 +13,7 @@ import (
 
 type FeatureDependencyRepository struct {
 	collection *mongo.Collection
+	changeHooks
 }
 
 func NewFeatureDependencyRepository(db *mongo.Database) *FeatureDependencyRepository {
@@ -21,6 +23,10 @@ func NewFeatureDependencyRepository(db *mongo.Database) *FeatureDependencyReposi
 }
 
 func (r *FeatureDependencyRepository) Create(ctx context.Context, dependency *models.FeatureDependency) error {
+	ctx, end := observe(ctx, "feature_dependencies", "Create")
+	defer end()
+	defer r.changed()
+
 	dependency.CreatedAt = time.Now()
 	dependency.UpdatedAt = time.Now()
 
@@ -34,6 +40,9 @@ func (r *FeatureDependencyRepository) Create(ctx context.Context, dependency *mo
 }
 
 func (r *FeatureDependencyRepository) GetChildren(ctx context.Context, parentID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	ctx, end := observe(ctx, "feature_dependencies", "GetChildren")
+	defer end()
+
 	cursor, err := r.collection.Find(ctx, bson.M{"parent_id": parentID})
 	if err != nil {
 		return nil, err
@@ -49,10 +58,14 @@ func (r *FeatureDependencyRepository) GetChildren(ctx context.Context, parentID
 	for i, dep := range dependencies {
 		children[i] = dep.ChildID
 	}
+	tracing.SetAttributes(ctx, tracing.ParentID(parentID), tracing.ChildrenCountKey.Int(len(children)))
 	return children, nil
 }
 
 func (r *FeatureDependencyRepository) GetParents(ctx context.Context, childID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	ctx, end := observe(ctx, "feature_dependencies", "GetParents")
+	defer end()
+
 	cursor, err := r.collection.Find(ctx, bson.M{"child_id": childID})
 	if err != nil {
 		return nil, err
@@ -72,6 +85,10 @@ func (r *FeatureDependencyRepository) GetParents(ctx context.Context, childID pr
 }
 
 func (r *FeatureDependencyRepository) Delete(ctx context.Context, parentID, childID primitive.ObjectID) error {
+	ctx, end := observe(ctx, "feature_dependencies", "Delete")
+	defer end()
+	defer r.changed()
+
 	_, err := r.collection.DeleteOne(ctx, bson.M{
 		"parent_id": parentID,
 		"child_id":  childID,
@@ -79,7 +96,25 @@ func (r *FeatureDependencyRepository) Delete(ctx context.Context, parentID, chil
 	return err
 }
 
+// DeleteByFeature removes every edge the feature takes part in.
+func (r *FeatureDependencyRepository) DeleteByFeature(ctx context.Context, featureID primitive.ObjectID) error {
+	ctx, end := observe(ctx, "feature_dependencies", "DeleteByFeature")
+	defer end()
+	defer r.changed()
+
+	_, err := r.collection.DeleteMany(ctx, bson.M{
+		"$or": []bson.M{
+			{"parent_id": featureID},
+			{"child_id": featureID},
+		},
+	})
+	return err
+}
+
 func (r *FeatureDependencyRepository) Exists(ctx context.Context, parentID, childID primitive.ObjectID) (bool, error) {
+	ctx, end := observe(ctx, "feature_dependencies", "Exists")
+	defer end()
+
 	count, err := r.collection.CountDocuments(ctx, bson.M{
 		"parent_id": parentID,
 		"child_id":  childID,
@@ -89,3 +124,33 @@ func (r *FeatureDependencyRepository) Exists(ctx context.Context, parentID, chil
 	}
 	return count > 0, nil
 }
+
+func (r *FeatureDependencyRepository) List(ctx context.Context) ([]models.FeatureDependency, error) {
+	ctx, end := observe(ctx, "feature_dependencies", "List")
+	defer end()
+
+	cursor, err := r.collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	dependencies := make([]models.FeatureDependency, 0)
+	if err := cursor.All(ctx, &dependencies); err != nil {
+		return nil, err
+	}
+	return dependencies, nil
+}
+
+func (r *FeatureDependencyRepository) Count(ctx context.Context) (int64, error) {
+	ctx, end := observe(ctx, "feature_dependencies", "Count")
+	defer end()
+
+	return r.collection.CountDocuments(ctx, bson.M{})
+}
+
+// Watch calls onChange after every insert, update or delete of a dependency,
+// until ctx is done or the change stream fails.
+func (r *FeatureDependencyRepository) Watch(ctx context.Context, onChange func()) error {
+	return watch(ctx, r.collection, onChange)
+}
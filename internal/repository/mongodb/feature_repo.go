@@ -3,15 +3,18 @@ package mongodb
 import (
 	"context"
 	"feature-flags/internal/models"
+	"feature-flags/internal/tracing"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type FeatureRepository struct {
 	collection *mongo.Collection
+	changeHooks
 }
 
 func NewFeatureRepository(db *mongo.Database) *FeatureRepository {
@@ -21,6 +24,10 @@ func NewFeatureRepository(db *mongo.Database) *FeatureRepository {
 }
 
 func (r *FeatureRepository) Create(ctx context.Context, feature *models.Feature) error {
+	ctx, end := observe(ctx, "features", "Create")
+	defer end()
+	defer r.changed()
+
 	feature.CreatedAt = time.Now()
 	feature.UpdatedAt = time.Now()
 
@@ -34,6 +41,10 @@ func (r *FeatureRepository) Create(ctx context.Context, feature *models.Feature)
 }
 
 func (r *FeatureRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.Feature, error) {
+	ctx, end := observe(ctx, "features", "GetByID")
+	defer end()
+	tracing.SetAttributes(ctx, tracing.FeatureID(id))
+
 	var feature models.Feature
 	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&feature)
 	if err != nil {
@@ -42,41 +53,394 @@ func (r *FeatureRepository) GetByID(ctx context.Context, id primitive.ObjectID)
 	return &feature, nil
 }
 
+// GetByIDs returns the features with the given IDs in one query, in no
+// particular order. Missing features are left out.
+func (r *FeatureRepository) GetByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*models.Feature, error) {
+	ctx, end := observe(ctx, "features", "GetByIDs")
+	defer end()
+
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	features := make([]*models.Feature, 0, len(ids))
+	if err = cursor.All(ctx, &features); err != nil {
+		return nil, err
+	}
+	return features, nil
+}
+
+// GetByKey returns the feature with key. Features created before keys
+// existed are matched by name until they are migrated.
+func (r *FeatureRepository) GetByKey(ctx context.Context, key string) (*models.Feature, error) {
+	ctx, end := observe(ctx, "features", "GetByKey")
+	defer end()
+
+	filter := bson.M{"$or": bson.A{
+		bson.M{"key": key},
+		bson.M{"key": bson.M{"$exists": false}, "name": key},
+	}}
+	var feature models.Feature
+	if err := r.collection.FindOne(ctx, filter).Decode(&feature); err != nil {
+		return nil, err
+	}
+	return &feature, nil
+}
+
+// Update stores the fields of feature that a manifest declares: name,
+// type, enabled state, protection, targeting and Version. Emptied targeting
+// fields are removed. Overrides, guard, rollout plan and metadata are
+// written by their own methods and left as they are.
 func (r *FeatureRepository) Update(ctx context.Context, feature *models.Feature) error {
+	ctx, end := observe(ctx, "features", "Update")
+	defer end()
+	defer r.changed()
+
 	feature.UpdatedAt = time.Now()
+	set := bson.M{
+		"name":       feature.Name,
+		"type":       feature.Type,
+		"is_enabled": feature.IsEnabled,
+		"protected":  feature.Protected,
+		"version":    feature.Version,
+		"updated_at": feature.UpdatedAt,
+	}
 
-	_, err := r.collection.UpdateOne(
-		ctx,
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": feature.ID}, targetingUpdate(feature.Targeting, set))
+	return err
+}
+
+// SetTargeting stores the targeting of feature and bumps the stored
+// Version, removing emptied fields and leaving the rest of the stored
+// feature as it is.
+func (r *FeatureRepository) SetTargeting(ctx context.Context, feature *models.Feature) error {
+	ctx, end := observe(ctx, "features", "SetTargeting")
+	defer end()
+	defer r.changed()
+
+	feature.UpdatedAt = time.Now()
+	update := targetingUpdate(feature.Targeting, bson.M{"updated_at": feature.UpdatedAt})
+	update["$inc"] = bson.M{"version": 1}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": feature.ID}, update)
+	return err
+}
+
+// SetProtected stores whether feature is protected, leaving the rest of the
+// stored feature as it is. Protection does not change how a feature
+// evaluates, so Version is left as it is too.
+func (r *FeatureRepository) SetProtected(ctx context.Context, feature *models.Feature) error {
+	ctx, end := observe(ctx, "features", "SetProtected")
+	defer end()
+	defer r.changed()
+
+	feature.UpdatedAt = time.Now()
+	_, err := r.collection.UpdateOne(ctx,
 		bson.M{"_id": feature.ID},
-		bson.M{"$set": feature},
+		bson.M{"$set": bson.M{"protected": feature.Protected, "updated_at": feature.UpdatedAt}},
 	)
 	return err
 }
 
+// targetingUpdate adds the fields of t to set and returns the update, which
+// unsets the fields t leaves empty, since they are omitted when stored.
+func targetingUpdate(t models.Targeting, set bson.M) bson.M {
+	unset := bson.M{}
+	fields := []struct {
+		name  string
+		value interface{}
+		empty bool
+	}{
+		{"rules", t.Rules, len(t.Rules) == 0},
+		{"variants", t.Variants, len(t.Variants) == 0},
+		{"default_variant", t.DefaultVariant, t.DefaultVariant == ""},
+		{"off_variant", t.OffVariant, t.OffVariant == ""},
+	}
+	for _, f := range fields {
+		if f.empty {
+			unset[f.name] = ""
+		} else {
+			set[f.name] = f.value
+		}
+	}
+
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	return update
+}
+
+// SetOverrides stores the overrides of feature and bumps its Version,
+// provided the stored Version is still feature.Version, leaving the rest of
+// the stored feature as it is. It returns false when the feature was
+// changed since it was read.
+func (r *FeatureRepository) SetOverrides(ctx context.Context, feature *models.Feature) (bool, error) {
+	ctx, end := observe(ctx, "features", "SetOverrides")
+	defer end()
+	defer r.changed()
+
+	overrides := feature.Overrides
+	if overrides == nil {
+		overrides = []models.Override{}
+	}
+	updatedAt := time.Now()
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": feature.ID, "version": feature.Version},
+		bson.M{
+			"$set": bson.M{"overrides": overrides, "updated_at": updatedAt},
+			"$inc": bson.M{"version": 1},
+		},
+	)
+	if err != nil || result.MatchedCount == 0 {
+		return false, err
+	}
+	feature.UpdatedAt = updatedAt
+	feature.Version++
+	return true, nil
+}
+
+// SetGuard stores the guard rule of feature, or removes it when nil,
+// leaving the rest of the stored feature as it is. Guards do not change how
+// a feature evaluates, so Version is left as it is too.
+func (r *FeatureRepository) SetGuard(ctx context.Context, feature *models.Feature) error {
+	ctx, end := observe(ctx, "features", "SetGuard")
+	defer end()
+	defer r.changed()
+
+	feature.UpdatedAt = time.Now()
+	update := bson.M{"$set": bson.M{"guard": feature.Guard, "updated_at": feature.UpdatedAt}}
+	if feature.Guard == nil {
+		update = bson.M{"$set": bson.M{"updated_at": feature.UpdatedAt}, "$unset": bson.M{"guard": ""}}
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": feature.ID}, update)
+	return err
+}
+
+// ClaimGuardTrip sets the guard's tripped_at to at, provided it is still
+// previous (nil when the guard never tripped). It returns false when
+// another instance claimed the trip first or the guard was changed.
+func (r *FeatureRepository) ClaimGuardTrip(ctx context.Context, id primitive.ObjectID, previous *time.Time, at time.Time) (bool, error) {
+	ctx, end := observe(ctx, "features", "ClaimGuardTrip")
+	defer end()
+	defer r.changed()
+
+	filter := bson.M{"_id": id, "guard": bson.M{"$exists": true}}
+	if previous == nil {
+		filter["guard.tripped_at"] = bson.M{"$exists": false}
+	} else {
+		filter["guard.tripped_at"] = *previous
+	}
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"guard.tripped_at": at}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// ReleaseGuardTrip undoes a ClaimGuardTrip that set tripped_at to claimed,
+// restoring previous (nil when the guard never tripped). It leaves the
+// guard alone if it was changed or tripped again since.
+func (r *FeatureRepository) ReleaseGuardTrip(ctx context.Context, id primitive.ObjectID, claimed time.Time, previous *time.Time) error {
+	ctx, end := observe(ctx, "features", "ReleaseGuardTrip")
+	defer end()
+	defer r.changed()
+
+	update := bson.M{"$unset": bson.M{"guard.tripped_at": ""}}
+	if previous != nil {
+		update = bson.M{"$set": bson.M{"guard.tripped_at": *previous}}
+	}
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, "guard.tripped_at": claimed}, update)
+	return err
+}
+
+// SetRollout stores the rollout plan of feature, or removes the plan when
+// nil, and bumps its Version, provided the stored Version is still
+// feature.Version, leaving the rest of the stored feature as it is. It
+// returns false when the feature was changed since it was read, e.g. by the
+// executor advancing the plan.
+func (r *FeatureRepository) SetRollout(ctx context.Context, feature *models.Feature) (bool, error) {
+	ctx, end := observe(ctx, "features", "SetRollout")
+	defer end()
+	defer r.changed()
+
+	updatedAt := time.Now()
+	set := bson.M{"updated_at": updatedAt}
+	update := bson.M{"$set": set, "$unset": bson.M{"rollout": ""}, "$inc": bson.M{"version": 1}}
+	if feature.Rollout != nil {
+		set["rollout"] = feature.Rollout
+		update = bson.M{"$set": set, "$inc": bson.M{"version": 1}}
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": feature.ID, "version": feature.Version}, update)
+	if err != nil || result.MatchedCount == 0 {
+		return false, err
+	}
+	feature.UpdatedAt = updatedAt
+	feature.Version++
+	return true, nil
+}
+
+// UpdateRollout stores the rollout plan of feature and bumps its Version,
+// provided the stored plan is still at the status and step of previous. It
+// returns false when another instance or request moved the plan first.
+func (r *FeatureRepository) UpdateRollout(ctx context.Context, feature *models.Feature, previous models.RolloutPlan) (bool, error) {
+	ctx, end := observe(ctx, "features", "UpdateRollout")
+	defer end()
+	defer r.changed()
+
+	filter := bson.M{
+		"_id":            feature.ID,
+		"rollout.status": previous.Status,
+		"rollout.step":   previous.Step,
+	}
+	updatedAt := time.Now()
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{
+		"$set": bson.M{"rollout": feature.Rollout, "updated_at": updatedAt},
+		"$inc": bson.M{"version": 1},
+	})
+	if err != nil || result.ModifiedCount == 0 {
+		return false, err
+	}
+	feature.UpdatedAt = updatedAt
+	feature.Version++
+	return true, nil
+}
+
 func (r *FeatureRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	ctx, end := observe(ctx, "features", "Delete")
+	defer end()
+	defer r.changed()
+
 	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
 	return err
 }
 
 func (r *FeatureRepository) List(ctx context.Context) ([]*models.Feature, error) {
+	ctx, end := observe(ctx, "features", "List")
+	defer end()
+
 	cursor, err := r.collection.Find(ctx, bson.M{})
 	if err != nil {
 		return nil, err
 	}
 	defer cursor.Close(ctx)
 
-	var features []*models.Feature
+	features := make([]*models.Feature, 0)
+	if err = cursor.All(ctx, &features); err != nil {
+		return nil, err
+	}
+	return features, nil
+}
+
+// Find returns the features matching filter, ordered by name.
+func (r *FeatureRepository) Find(ctx context.Context, filter models.FeatureFilter) ([]*models.Feature, error) {
+	ctx, end := observe(ctx, "features", "Find")
+	defer end()
+
+	query := bson.M{}
+	if filter.Type != "" {
+		query["type"] = filter.Type
+	}
+	if filter.Enabled != nil {
+		query["is_enabled"] = *filter.Enabled
+	}
+	if len(filter.Tags) > 0 {
+		query["tags"] = bson.M{"$all": filter.Tags}
+	}
+	if filter.Owner != "" {
+		query["owner"] = filter.Owner
+	}
+	if filter.Segment != "" {
+		query["rules"] = bson.M{"$elemMatch": bson.M{
+			"operator": bson.M{"$in": []models.RuleOperator{models.RuleOperatorInSegment, models.RuleOperatorNotInSegment}},
+			"values":   filter.Segment,
+		}}
+	}
+
+	if filter.Guarded {
+		query["guard"] = bson.M{"$exists": true}
+	}
+	if filter.Rollout != "" {
+		query["rollout.status"] = filter.Rollout
+	}
+
+	cursor, err := r.collection.Find(ctx, query, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	features := make([]*models.Feature, 0)
 	if err = cursor.All(ctx, &features); err != nil {
 		return nil, err
 	}
 	return features, nil
 }
 
-func (r *FeatureRepository) BulkUpdate(ctx context.Context, ids []primitive.ObjectID, update bson.M) error {
-	_, err := r.collection.UpdateMany(
+// SetEnabled enables or disables every feature in ids in one update and
+// bumps their Version. Features already in that state are left as they
+// are. It returns how many features changed.
+func (r *FeatureRepository) SetEnabled(ctx context.Context, ids []primitive.ObjectID, enabled bool) (int64, error) {
+	ctx, end := observe(ctx, "features", "SetEnabled")
+	defer end()
+	defer r.changed()
+
+	result, err := r.collection.UpdateMany(
 		ctx,
-		bson.M{"_id": bson.M{"$in": ids}},
-		bson.M{"$set": update},
+		bson.M{"_id": bson.M{"$in": ids}, "is_enabled": !enabled},
+		bson.M{
+			"$set": bson.M{"is_enabled": enabled, "updated_at": time.Now()},
+			"$inc": bson.M{"version": 1},
+		},
 	)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// UpdateMetadata replaces the metadata of a feature without touching its
+// Version, and returns the updated feature.
+func (r *FeatureRepository) UpdateMetadata(ctx context.Context, id primitive.ObjectID, metadata models.Metadata) (*models.Feature, error) {
+	ctx, end := observe(ctx, "features", "UpdateMetadata")
+	defer end()
+	defer r.changed()
+
+	update := bson.M{"$set": bson.M{
+		"description": metadata.Description,
+		"tags":        metadata.Tags,
+		"owner":       metadata.Owner,
+		"maintainer":  metadata.Maintainer,
+		"links":       metadata.Links,
+		"attributes":  metadata.Attributes,
+		"updated_at":  time.Now(),
+	}}
+
+	var feature models.Feature
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&feature)
+	if err != nil {
+		return nil, err
+	}
+	return &feature, nil
+}
+
+func (r *FeatureRepository) Count(ctx context.Context) (int64, error) {
+	ctx, end := observe(ctx, "features", "Count")
+	defer end()
+
+	return r.collection.CountDocuments(ctx, bson.M{})
+}
+
+// Watch calls onChange after every insert, update or delete of a feature,
+// until ctx is done or the change stream fails.
+func (r *FeatureRepository) Watch(ctx context.Context, onChange func()) error {
+	return watch(ctx, r.collection, onChange)
 }
@@ -0,0 +1,148 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"feature-flags/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// requiredIndexes are the indexes the repositories' queries rely on, keyed
+// by collection.
+var requiredIndexes = map[string][]mongo.IndexModel{
+	"features": {
+		{
+			// Partial so that features created before keys existed, which
+			// have none, do not collide.
+			Keys: bson.D{{Key: "key", Value: 1}},
+			Options: options.Index().SetName("key").SetUnique(true).
+				SetPartialFilterExpression(bson.M{"key": bson.M{"$type": "string"}}),
+		},
+		// Listing is ordered by name and filtered by type, enabled state, tag
+		// or owner.
+		{
+			Keys:    bson.D{{Key: "name", Value: 1}},
+			Options: options.Index().SetName("name"),
+		},
+		{
+			Keys:    bson.D{{Key: "type", Value: 1}, {Key: "name", Value: 1}},
+			Options: options.Index().SetName("type_name"),
+		},
+		{
+			Keys:    bson.D{{Key: "is_enabled", Value: 1}, {Key: "name", Value: 1}},
+			Options: options.Index().SetName("is_enabled_name"),
+		},
+		{
+			Keys:    bson.D{{Key: "tags", Value: 1}, {Key: "name", Value: 1}},
+			Options: options.Index().SetName("tags_name"),
+		},
+		{
+			Keys:    bson.D{{Key: "owner", Value: 1}, {Key: "name", Value: 1}},
+			Options: options.Index().SetName("owner_name"),
+		},
+		// Sparse so that only features with a rollout plan are indexed.
+		{
+			Keys:    bson.D{{Key: "rollout.status", Value: 1}},
+			Options: options.Index().SetName("rollout_status").SetSparse(true),
+		},
+	},
+	"feature_dependencies": {
+		{
+			// Also serves GetChildren, which queries parent_id alone, so
+			// parent_id needs no index of its own.
+			Keys:    bson.D{{Key: "parent_id", Value: 1}, {Key: "child_id", Value: 1}},
+			Options: options.Index().SetName("parent_id_child_id").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "child_id", Value: 1}},
+			Options: options.Index().SetName("child_id"),
+		},
+	},
+	"segments": {
+		{
+			Keys:    bson.D{{Key: "key", Value: 1}},
+			Options: options.Index().SetName("key").SetUnique(true),
+		},
+	},
+	"exposures": {
+		{
+			Keys:    bson.D{{Key: "flag_key", Value: 1}, {Key: "timestamp", Value: 1}},
+			Options: options.Index().SetName("flag_key_timestamp"),
+		},
+	},
+	"conversions": {
+		{
+			Keys:    bson.D{{Key: "event", Value: 1}, {Key: "timestamp", Value: 1}},
+			Options: options.Index().SetName("event_timestamp"),
+		},
+	},
+	"signals": {
+		{
+			Keys:    bson.D{{Key: "flag_key", Value: 1}, {Key: "timestamp", Value: 1}},
+			Options: options.Index().SetName("flag_key_timestamp"),
+		},
+		{
+			// Signals older than the longest guard window are never read.
+			Keys:    bson.D{{Key: "timestamp", Value: 1}},
+			Options: options.Index().SetName("timestamp_ttl").SetExpireAfterSeconds(int32(models.MaxGuardWindow.Seconds())),
+		},
+	},
+	"audit_log": {
+		{
+			Keys:    bson.D{{Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("created_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "feature_key", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("feature_key_created_at"),
+		},
+	},
+	"change_requests": {
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("status_created_at"),
+		},
+	},
+}
+
+// EnsureIndexes creates any required index that does not exist yet.
+func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	for collection, models := range requiredIndexes {
+		if _, err := db.Collection(collection).Indexes().CreateMany(ctx, models); err != nil {
+			return fmt.Errorf("failed to create %s indexes: %w", collection, err)
+		}
+	}
+	return nil
+}
+
+// CheckIndexes returns an error naming every required index that is
+// missing.
+func CheckIndexes(ctx context.Context, db *mongo.Database) error {
+	var missing []string
+	for collection, models := range requiredIndexes {
+		specs, err := db.Collection(collection).Indexes().ListSpecifications(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list %s indexes: %w", collection, err)
+		}
+		existing := make(map[string]bool, len(specs))
+		for _, spec := range specs {
+			existing[spec.Name] = true
+		}
+		for _, model := range models {
+			if name := *model.Options.Name; !existing[name] {
+				missing = append(missing, collection+"."+name)
+			}
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("missing indexes: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
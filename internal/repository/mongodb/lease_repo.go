@@ -0,0 +1,58 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LeaseRepository elects a leader among instances: a lease is held by one
+// holder at a time until it expires or is released. Each lease is one
+// document keyed by its name.
+type LeaseRepository struct {
+	collection *mongo.Collection
+}
+
+func NewLeaseRepository(db *mongo.Database) *LeaseRepository {
+	return &LeaseRepository{
+		collection: db.Collection("leases"),
+	}
+}
+
+// Acquire takes or renews the lease with name for holder until ttl from
+// now. It returns false while another holder's lease has not expired.
+func (r *LeaseRepository) Acquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	ctx, end := observe(ctx, "leases", "Acquire")
+	defer end()
+
+	now := time.Now()
+	filter := bson.M{
+		"_id": name,
+		"$or": bson.A{
+			bson.M{"holder": holder},
+			bson.M{"expires_at": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"holder": holder, "acquired_at": now, "expires_at": now.Add(ttl)}}
+
+	// When another holder has the lease the filter matches nothing, and the
+	// upsert collides with its document.
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Release gives up the lease with name if holder has it, so another
+// instance can take it without waiting for it to expire.
+func (r *LeaseRepository) Release(ctx context.Context, name, holder string) error {
+	ctx, end := observe(ctx, "leases", "Release")
+	defer end()
+
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": name, "holder": holder})
+	return err
+}
@@ -0,0 +1,179 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"feature-flags/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Migration is a versioned change to stored documents. Up may run more than
+// once, e.g. when it fails part way or two instances start together, so it
+// must only touch documents that still need the change.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database) error
+}
+
+// migrations are applied in version order. Add new ones at the end with the
+// next version; never renumber or change one that has been released.
+var migrations = []Migration{
+	{Version: 1, Description: "backfill feature keys", Up: backfillFeatureKeys},
+}
+
+// appliedMigration records a migration in the schema_migrations collection.
+type appliedMigration struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"applied_at"`
+}
+
+// Bootstrap prepares db for the repositories: it creates the required
+// indexes and applies pending migrations. Migrations run even if some
+// indexes could not be created.
+func Bootstrap(ctx context.Context, db *mongo.Database) error {
+	return errors.Join(EnsureIndexes(ctx, db), Migrate(ctx, db))
+}
+
+// Migrate applies every migration that has not been applied yet, in version
+// order, and stops at the first that fails.
+func Migrate(ctx context.Context, db *mongo.Database) error {
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range migrations {
+		if applied[migration.Version] {
+			continue
+		}
+		slog.InfoContext(ctx, "applying migration", "version", migration.Version, "description", migration.Description)
+		if err := migration.Up(ctx, db); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", migration.Version, migration.Description, err)
+		}
+		_, err := db.Collection("schema_migrations").InsertOne(ctx, appliedMigration{
+			Version:     migration.Version,
+			Description: migration.Description,
+			AppliedAt:   time.Now(),
+		})
+		// A duplicate means another instance applied it concurrently.
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+		}
+	}
+	return nil
+}
+
+// CheckMigrations returns an error naming every migration that has not been
+// applied.
+func CheckMigrations(ctx context.Context, db *mongo.Database) error {
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	var pending []string
+	for _, migration := range migrations {
+		if !applied[migration.Version] {
+			pending = append(pending, strconv.Itoa(migration.Version))
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("pending migrations: %s", strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+func appliedVersions(ctx context.Context, db *mongo.Database) (map[int]bool, error) {
+	cursor, err := db.Collection("schema_migrations").Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var records []appliedMigration
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+	applied := make(map[int]bool, len(records))
+	for _, record := range records {
+		applied[record.Version] = true
+	}
+	return applied, nil
+}
+
+// backfillFeatureKeys gives every feature created before keys existed a
+// key. Oldest features are keyed first, so when two names derive the same
+// key the older feature gets it and the newer one's key is suffixed with
+// its ID.
+func backfillFeatureKeys(ctx context.Context, db *mongo.Database) error {
+	features := db.Collection("features")
+
+	missing := bson.M{"key": bson.M{"$exists": false}}
+	cursor, err := features.Find(ctx, missing, options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetProjection(bson.M{"name": 1}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var legacy []struct {
+		ID   primitive.ObjectID `bson:"_id"`
+		Name string             `bson:"name"`
+	}
+	if err := cursor.All(ctx, &legacy); err != nil {
+		return err
+	}
+
+	for _, feature := range legacy {
+		key := legacyKey(feature.Name, feature.ID)
+		filter := bson.M{"_id": feature.ID, "key": bson.M{"$exists": false}}
+		_, err := features.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"key": key}})
+		if mongo.IsDuplicateKeyError(err) {
+			key = suffixedKey(key, feature.ID)
+			_, err = features.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"key": key}})
+		}
+		if err != nil {
+			return fmt.Errorf("failed to set key of feature %s: %w", feature.ID.Hex(), err)
+		}
+		if key != feature.Name {
+			// Clients evaluating this feature by name must switch to the key.
+			slog.WarnContext(ctx, "feature key differs from its name", "feature_id", feature.ID.Hex(), "name", feature.Name, "key", key)
+		}
+	}
+	return nil
+}
+
+// legacyKey returns the key for a feature created before keys existed: its
+// name when that is a valid key, so clients evaluating it by name are not
+// affected, otherwise a key derived from the name.
+func legacyKey(name string, id primitive.ObjectID) string {
+	if models.ValidateKey(name) == nil {
+		return name
+	}
+	if key := models.KeyFromName(name); key != "" {
+		return key
+	}
+	return "feature-" + id.Hex()
+}
+
+// suffixedKey makes key unique by appending id, shortening key if needed.
+func suffixedKey(key string, id primitive.ObjectID) string {
+	suffix := "-" + id.Hex()
+	if len(key)+len(suffix) > models.MaxKeyLength {
+		key = key[:models.MaxKeyLength-len(suffix)]
+	}
+	return key + suffix
+}
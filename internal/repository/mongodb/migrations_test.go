@@ -0,0 +1,32 @@
+package mongodb
+
+import (
+	"strings"
+	"testing"
+
+	"feature-flags/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMigrations_Versions(t *testing.T) {
+	for i, migration := range migrations {
+		assert.Equal(t, i+1, migration.Version, "migrations must be numbered 1, 2, ... in order")
+		assert.NotEmpty(t, migration.Description)
+		assert.NotNil(t, migration.Up)
+	}
+}
+
+func TestLegacyKey(t *testing.T) {
+	id := primitive.NewObjectID()
+
+	assert.Equal(t, "checkout.v2", legacyKey("checkout.v2", id))
+	assert.Equal(t, "new-checkout", legacyKey("New Checkout", id))
+	assert.Equal(t, "feature-"+id.Hex(), legacyKey("???", id))
+
+	for _, key := range []string{legacyKey("???", id), suffixedKey("new-checkout", id), suffixedKey(strings.Repeat("a", models.MaxKeyLength), id)} {
+		assert.NoError(t, models.ValidateKey(key), key)
+	}
+	assert.Equal(t, "new-checkout-"+id.Hex(), suffixedKey("new-checkout", id))
+}
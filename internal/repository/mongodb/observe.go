@@ -0,0 +1,24 @@
+package mongodb
+
+import (
+	"context"
+	"feature-flags/internal/metrics"
+	"feature-flags/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// observe starts a span and a latency measurement for a repository method.
+// Call the returned function when the method returns.
+func observe(ctx context.Context, collection, method string) (context.Context, func()) {
+	ctx, span := tracing.Start(ctx, collection+"."+method,
+		attribute.String("db.system.name", "mongodb"),
+		attribute.String("db.collection.name", collection),
+		attribute.String("db.operation.name", method),
+	)
+	done := metrics.ObserveMongo(collection, method)
+	return ctx, func() {
+		done()
+		span.End()
+	}
+}
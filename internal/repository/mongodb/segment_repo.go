@@ -0,0 +1,123 @@
+package mongodb
+
+import (
+	"context"
+	"feature-flags/internal/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SegmentRepository stores segments. Keys are unique, so Create reports a
+// taken key as a duplicate key error.
+type SegmentRepository struct {
+	collection *mongo.Collection
+	changeHooks
+}
+
+func NewSegmentRepository(db *mongo.Database) *SegmentRepository {
+	return &SegmentRepository{
+		collection: db.Collection("segments"),
+	}
+}
+
+func (r *SegmentRepository) Create(ctx context.Context, segment *models.Segment) error {
+	ctx, end := observe(ctx, "segments", "Create")
+	defer end()
+	defer r.changed()
+
+	segment.CreatedAt = time.Now()
+	segment.UpdatedAt = segment.CreatedAt
+
+	result, err := r.collection.InsertOne(ctx, segment)
+	if err != nil {
+		return err
+	}
+	segment.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *SegmentRepository) GetByKey(ctx context.Context, key string) (*models.Segment, error) {
+	ctx, end := observe(ctx, "segments", "GetByKey")
+	defer end()
+
+	var segment models.Segment
+	if err := r.collection.FindOne(ctx, bson.M{"key": key}).Decode(&segment); err != nil {
+		return nil, err
+	}
+	return &segment, nil
+}
+
+// GetByKeys returns the segments with the given keys in one query, in no
+// particular order. Missing segments are left out.
+func (r *SegmentRepository) GetByKeys(ctx context.Context, keys []string) ([]*models.Segment, error) {
+	ctx, end := observe(ctx, "segments", "GetByKeys")
+	defer end()
+
+	return r.find(ctx, bson.M{"key": bson.M{"$in": keys}}, nil)
+}
+
+// List returns every segment, ordered by key.
+func (r *SegmentRepository) List(ctx context.Context) ([]*models.Segment, error) {
+	ctx, end := observe(ctx, "segments", "List")
+	defer end()
+
+	return r.find(ctx, bson.M{}, options.Find().SetSort(bson.M{"key": 1}))
+}
+
+func (r *SegmentRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]*models.Segment, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	segments := make([]*models.Segment, 0)
+	if err := cursor.All(ctx, &segments); err != nil {
+		return nil, err
+	}
+	return segments, nil
+}
+
+// Update replaces the stored segment with segment, so emptied lists and
+// rules are removed, provided the stored Version is still the one before
+// segment.Version. It returns false when the segment was changed since it
+// was read.
+func (r *SegmentRepository) Update(ctx context.Context, segment *models.Segment) (bool, error) {
+	ctx, end := observe(ctx, "segments", "Update")
+	defer end()
+	defer r.changed()
+
+	segment.UpdatedAt = time.Now()
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": segment.ID, "version": segment.Version - 1}, segment)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// Delete removes the segment with key. It returns mongo.ErrNoDocuments if
+// there is none.
+func (r *SegmentRepository) Delete(ctx context.Context, key string) error {
+	ctx, end := observe(ctx, "segments", "Delete")
+	defer end()
+	defer r.changed()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"key": key})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// Watch calls onChange after every insert, update or delete of a segment,
+// until ctx is done or the change stream fails.
+func (r *SegmentRepository) Watch(ctx context.Context, onChange func()) error {
+	return watch(ctx, r.collection, onChange)
+}
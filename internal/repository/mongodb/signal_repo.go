@@ -0,0 +1,62 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"feature-flags/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// SignalRepository stores the error and success counts services report per
+// flag. Signals expire after models.MaxGuardWindow.
+type SignalRepository struct {
+	collection *mongo.Collection
+}
+
+func NewSignalRepository(db *mongo.Database) *SignalRepository {
+	return &SignalRepository{
+		collection: db.Collection("signals"),
+	}
+}
+
+func (r *SignalRepository) Insert(ctx context.Context, signals []models.Signal) error {
+	ctx, end := observe(ctx, "signals", "Insert")
+	defer end()
+
+	docs := make([]interface{}, len(signals))
+	for i := range signals {
+		docs[i] = signals[i]
+	}
+	_, err := r.collection.InsertMany(ctx, docs)
+	return err
+}
+
+// Counts sums the signals reported for the flag with key at or after since.
+func (r *SignalRepository) Counts(ctx context.Context, key string, since time.Time) (models.SignalCounts, error) {
+	ctx, end := observe(ctx, "signals", "Counts")
+	defer end()
+
+	var counts models.SignalCounts
+	cursor, err := r.collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"flag_key": key, "timestamp": bson.M{"$gte": since}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       nil,
+			"errors":    bson.M{"$sum": "$errors"},
+			"successes": bson.M{"$sum": "$successes"},
+		}}},
+	})
+	if err != nil {
+		return counts, err
+	}
+	defer cursor.Close(ctx)
+
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&counts); err != nil {
+			return counts, err
+		}
+	}
+	return counts, cursor.Err()
+}
@@ -0,0 +1,77 @@
+package mongodb
+
+import (
+	"context"
+	"feature-flags/internal/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TenantPlanRepository stores per-tenant plan overrides, keyed by tenant ID.
+type TenantPlanRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTenantPlanRepository(db *mongo.Database) *TenantPlanRepository {
+	return &TenantPlanRepository{
+		collection: db.Collection("tenant_plans"),
+	}
+}
+
+func (r *TenantPlanRepository) Get(ctx context.Context, tenantID string) (*models.TenantPlan, error) {
+	ctx, end := observe(ctx, "tenant_plans", "Get")
+	defer end()
+
+	var plan models.TenantPlan
+	if err := r.collection.FindOne(ctx, bson.M{"_id": tenantID}).Decode(&plan); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// List returns every override, ordered by tenant ID.
+func (r *TenantPlanRepository) List(ctx context.Context) ([]*models.TenantPlan, error) {
+	ctx, end := observe(ctx, "tenant_plans", "List")
+	defer end()
+
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	plans := make([]*models.TenantPlan, 0)
+	if err := cursor.All(ctx, &plans); err != nil {
+		return nil, err
+	}
+	return plans, nil
+}
+
+// Set creates or replaces the override for plan.TenantID.
+func (r *TenantPlanRepository) Set(ctx context.Context, plan *models.TenantPlan) error {
+	ctx, end := observe(ctx, "tenant_plans", "Set")
+	defer end()
+
+	plan.UpdatedAt = time.Now()
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": plan.TenantID}, plan, options.Replace().SetUpsert(true))
+	return err
+}
+
+// Delete removes the override for tenantID. It returns mongo.ErrNoDocuments
+// if there is none.
+func (r *TenantPlanRepository) Delete(ctx context.Context, tenantID string) error {
+	ctx, end := observe(ctx, "tenant_plans", "Delete")
+	defer end()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": tenantID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
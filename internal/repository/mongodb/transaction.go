@@ -0,0 +1,80 @@
+package mongodb
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TxManager runs groups of repository calls in a multi-document transaction.
+// Transactions need a replica set or sharded cluster; against a standalone
+// server (such as the docker-compose setup) the calls run without one, and
+// callers must undo what they can themselves when a call fails. Supported
+// reports which applies.
+type TxManager struct {
+	client *mongo.Client
+	db     *mongo.Database
+
+	mu        sync.Mutex
+	checked   bool
+	supported bool
+}
+
+func NewTxManager(db *mongo.Database) *TxManager {
+	return &TxManager{
+		client: db.Client(),
+		db:     db,
+	}
+}
+
+// Run calls fn inside a transaction. Repository calls made by fn must use
+// the context it receives. fn may be retried on transient errors, so it must
+// not have side effects outside the database.
+func (m *TxManager) Run(ctx context.Context, fn func(ctx context.Context) error) error {
+	supported, err := m.transactionsSupported(ctx)
+	if err != nil {
+		return err
+	}
+	if !supported {
+		return fn(ctx)
+	}
+
+	session, err := m.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
+}
+
+// Supported reports whether the server supports transactions, so Run is
+// atomic.
+func (m *TxManager) Supported(ctx context.Context) (bool, error) {
+	return m.transactionsSupported(ctx)
+}
+
+func (m *TxManager) transactionsSupported(ctx context.Context) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.checked {
+		return m.supported, nil
+	}
+
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := m.db.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return false, err
+	}
+	m.checked = true
+	m.supported = hello.SetName != "" || hello.Msg == "isdbgrid"
+	return m.supported, nil
+}
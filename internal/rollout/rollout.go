@@ -0,0 +1,159 @@
+// Package rollout advances progressive rollout plans. An executor moves
+// each plan to its next step once the current step has held long enough.
+// Instances elect one leader through a lease, and only the leader advances
+// plans.
+package rollout
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"feature-flags/internal/logging"
+	"feature-flags/internal/models"
+)
+
+// LeaseName names the lease instances compete for.
+const LeaseName = "rollout-executor"
+
+type Config struct {
+	// Interval is how often plans are checked.
+	Interval time.Duration
+	// LeaseTTL is how long leadership lasts without being renewed. It must
+	// exceed Interval, or the leader loses the lease between runs.
+	LeaseTTL time.Duration
+	// Holder identifies this instance to the others. It defaults to the
+	// hostname and a random suffix.
+	Holder string
+}
+
+// Advancer is the part of the feature service the executor drives.
+type Advancer interface {
+	AdvanceRollouts(ctx context.Context) ([]*models.AuditEntry, error)
+}
+
+// Lease elects the instance that advances plans.
+type Lease interface {
+	Acquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+	Release(ctx context.Context, name, holder string) error
+}
+
+// Status reports the executor's progress since startup.
+type Status struct {
+	Interval string     `json:"interval"`
+	Holder   string     `json:"holder"`
+	Leader   bool       `json:"leader"`
+	LastRun  *time.Time `json:"last_run,omitempty"`
+	// Steps counts the steps this instance advanced plans by.
+	Steps     int64  `json:"steps"`
+	LastStep  string `json:"last_step,omitempty"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+type Executor struct {
+	cfg      Config
+	advancer Advancer
+	lease    Lease
+
+	mu     sync.RWMutex
+	status Status
+}
+
+func NewExecutor(cfg Config, advancer Advancer, lease Lease) *Executor {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 30 * time.Second
+	}
+	if cfg.LeaseTTL <= cfg.Interval {
+		cfg.LeaseTTL = 3 * cfg.Interval
+	}
+	if cfg.Holder == "" {
+		cfg.Holder = defaultHolder()
+	}
+	return &Executor{
+		cfg:      cfg,
+		advancer: advancer,
+		lease:    lease,
+		status:   Status{Interval: cfg.Interval.String(), Holder: cfg.Holder},
+	}
+}
+
+func defaultHolder() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "instance"
+	}
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	return host + "-" + hex.EncodeToString(suffix)
+}
+
+// Run advances plans every interval until ctx is cancelled, then releases
+// the lease so another instance can take over at once.
+func (e *Executor) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			e.release()
+			return
+		case <-ticker.C:
+		}
+		if err := e.Advance(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "rollout advance failed", logging.Error(err))
+		}
+	}
+}
+
+// Advance takes or renews the lease and, if this instance holds it,
+// advances the plans that are due once.
+func (e *Executor) Advance(ctx context.Context) error {
+	leader, err := e.lease.Acquire(ctx, LeaseName, e.cfg.Holder, e.cfg.LeaseTTL)
+	var steps []*models.AuditEntry
+	if err == nil && leader {
+		steps, err = e.advancer.AdvanceRollouts(ctx)
+	}
+
+	e.mu.Lock()
+	now := time.Now()
+	e.status.LastRun = &now
+	e.status.Leader = leader
+	e.status.Steps += int64(len(steps))
+	if len(steps) > 0 {
+		e.status.LastStep = steps[len(steps)-1].FeatureKey
+	}
+	e.status.LastError = ""
+	if err != nil {
+		e.status.LastError = err.Error()
+	}
+	e.mu.Unlock()
+
+	return err
+}
+
+func (e *Executor) release() {
+	e.mu.RLock()
+	leader := e.status.Leader
+	e.mu.RUnlock()
+	if !leader {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := e.lease.Release(ctx, LeaseName, e.cfg.Holder); err != nil {
+		slog.Warn("failed to release rollout lease", logging.Error(err))
+	}
+}
+
+// Status returns the executor's progress since startup.
+func (e *Executor) Status() Status {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.status
+}
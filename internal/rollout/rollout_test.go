@@ -0,0 +1,99 @@
+package rollout
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"feature-flags/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAdvancer struct {
+	steps []*models.AuditEntry
+	err   error
+	calls int
+}
+
+func (a *fakeAdvancer) AdvanceRollouts(context.Context) ([]*models.AuditEntry, error) {
+	a.calls++
+	return a.steps, a.err
+}
+
+// fakeLease grants the lease to one holder until it is released.
+type fakeLease struct {
+	mu     sync.Mutex
+	holder string
+	err    error
+}
+
+func (l *fakeLease) Acquire(_ context.Context, _ string, holder string, _ time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return false, l.err
+	}
+	if l.holder == "" {
+		l.holder = holder
+	}
+	return l.holder == holder, nil
+}
+
+func (l *fakeLease) Release(_ context.Context, _ string, holder string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.holder == holder {
+		l.holder = ""
+	}
+	return nil
+}
+
+func TestExecutor_OnlyLeaderAdvances(t *testing.T) {
+	lease := &fakeLease{}
+	leader := &fakeAdvancer{steps: []*models.AuditEntry{{Action: models.AuditActionRolloutStep, FeatureKey: "search"}}}
+	follower := &fakeAdvancer{}
+	a := NewExecutor(Config{Interval: time.Minute, Holder: "a"}, leader, lease)
+	b := NewExecutor(Config{Interval: time.Minute, Holder: "b"}, follower, lease)
+
+	require.NoError(t, a.Advance(context.Background()))
+	require.NoError(t, b.Advance(context.Background()))
+	assert.Equal(t, 1, leader.calls)
+	assert.Zero(t, follower.calls)
+
+	status := a.Status()
+	assert.True(t, status.Leader)
+	assert.Equal(t, int64(1), status.Steps)
+	assert.Equal(t, "search", status.LastStep)
+	assert.False(t, b.Status().Leader)
+
+	// Releasing the lease hands leadership over.
+	a.release()
+	require.NoError(t, b.Advance(context.Background()))
+	assert.Equal(t, 1, follower.calls)
+	assert.True(t, b.Status().Leader)
+}
+
+func TestExecutor_RecordsErrors(t *testing.T) {
+	lease := &fakeLease{err: errors.New("connection refused")}
+	advancer := &fakeAdvancer{}
+	e := NewExecutor(Config{Interval: time.Minute}, advancer, lease)
+	assert.NotEmpty(t, e.Status().Holder)
+
+	assert.ErrorContains(t, e.Advance(context.Background()), "connection refused")
+	assert.Zero(t, advancer.calls)
+	assert.False(t, e.Status().Leader)
+	assert.NotEmpty(t, e.Status().LastError)
+
+	lease.err = nil
+	advancer.err = errors.New("feature search: failed to advance rollout plan")
+	assert.ErrorContains(t, e.Advance(context.Background()), "failed to advance")
+	assert.True(t, e.Status().Leader)
+
+	advancer.err = nil
+	require.NoError(t, e.Advance(context.Background()))
+	assert.Empty(t, e.Status().LastError)
+}
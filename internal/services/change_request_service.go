@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"feature-flags/internal/auth"
+	"feature-flags/internal/logging"
+	"feature-flags/internal/metrics"
+	"feature-flags/internal/models"
+	"feature-flags/internal/tracing"
+	"fmt"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrChangeRequestNotFound = kindError(ErrNotFound, "change request not found")
+	ErrChangeRequestClosed   = kindError(ErrConflict, "change request is no longer pending")
+	ErrSelfApproval          = errors.New("change requests cannot be approved by their requester")
+	ErrReviewerRequired      = errors.New("an authenticated reviewer is required")
+	// ErrApprovalsDisabled is returned when a feature is marked protected
+	// while requests are not authenticated. No one could approve its
+	// change requests, so it could never be changed again.
+	ErrApprovalsDisabled = kindError(ErrConflict, "protected features need authentication to approve their changes")
+)
+
+// PendingChangeError is returned when a change touches a protected feature
+// and was recorded as a change request instead of being applied.
+type PendingChangeError struct {
+	ChangeRequest *models.ChangeRequest
+}
+
+func (e *PendingChangeError) Error() string {
+	return fmt.Sprintf("change requires approval: change request %s created", e.ChangeRequest.ID.Hex())
+}
+
+// DisableApprovals records that requests are not authenticated, so change
+// requests cannot be approved. Features can then be unprotected but not
+// protected. Call it before serving requests.
+func (s *FeatureService) DisableApprovals() {
+	s.approvalsDisabled = true
+}
+
+// checkProtectable rejects marking a feature protected when approvals are
+// disabled.
+func (s *FeatureService) checkProtectable(protected bool) error {
+	if protected && s.approvalsDisabled {
+		return ErrApprovalsDisabled
+	}
+	return nil
+}
+
+func actorFromContext(ctx context.Context) string {
+	if p := auth.PrincipalFromContext(ctx); p != nil {
+		return p.Subject
+	}
+	return ""
+}
+
+func (s *FeatureService) submitChangeRequest(ctx context.Context, request *models.ChangeRequest) error {
+	request.Status = models.ChangeRequestStatusPending
+	request.RequestedBy = actorFromContext(ctx)
+
+	if err := s.changeRequestRepo.Create(ctx, request); err != nil {
+		return fmt.Errorf("failed to create change request: %w", err)
+	}
+	switch request.Action {
+	case models.ChangeRequestActionEnable, models.ChangeRequestActionDisable:
+		metrics.FeatureOperations.WithLabelValues(string(request.Action), "pending").Inc()
+	}
+	slog.InfoContext(ctx, "change request submitted",
+		"change_request_id", request.ID.Hex(),
+		"action", request.Action,
+	)
+	return &PendingChangeError{ChangeRequest: request}
+}
+
+func (s *FeatureService) ListChangeRequests(ctx context.Context, status models.ChangeRequestStatus) ([]*models.ChangeRequest, error) {
+	ctx, span := start(ctx, "ListChangeRequests")
+	defer span.End()
+
+	return s.changeRequestRepo.List(ctx, status)
+}
+
+func (s *FeatureService) GetChangeRequest(ctx context.Context, id primitive.ObjectID) (*models.ChangeRequest, error) {
+	ctx, span := start(ctx, "GetChangeRequest", tracing.ChangeRequestID(id))
+	defer span.End()
+
+	request, err := s.changeRequestRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrChangeRequestNotFound
+		}
+		return nil, err
+	}
+	return request, nil
+}
+
+// ApproveChangeRequest approves a pending change request and applies it.
+// Marking the request approved and applying the change happen in a single
+// transaction, and the change is re-validated against the current state of
+// the graph, so a disable cascades to whatever depends on the feature at
+// approval time. Without transaction support, a request whose change fails
+// to apply is moved back to pending so it can be approved again.
+func (s *FeatureService) ApproveChangeRequest(ctx context.Context, id primitive.ObjectID, comment string) (*models.ChangeRequest, error) {
+	ctx, span := start(ctx, "ApproveChangeRequest", tracing.ChangeRequestID(id))
+	defer span.End()
+
+	reviewer := actorFromContext(ctx)
+	if reviewer == "" {
+		return nil, ErrReviewerRequired
+	}
+
+	request, err := s.GetChangeRequest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != models.ChangeRequestStatusPending {
+		return nil, ErrChangeRequestClosed
+	}
+	if request.RequestedBy == reviewer {
+		return nil, ErrSelfApproval
+	}
+
+	// Drop any graph or snapshot cached during the transaction, in case it
+	// rolls back or a load read the state before it committed.
+	defer s.graph.invalidate()
+	defer s.snapshots.invalidate()
+
+	var approved *models.ChangeRequest
+	err = s.txManager.Run(ctx, func(ctx context.Context) error {
+		resolved, err := s.changeRequestRepo.Resolve(ctx, id, models.ChangeRequestStatusApproved, reviewer, comment)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return ErrChangeRequestClosed
+			}
+			return err
+		}
+		approved = resolved
+		return s.applyChangeRequest(ctx, resolved)
+	})
+	if err != nil {
+		// Inside a transaction the approval already rolled back, and
+		// reopening finds nothing to undo.
+		if approved != nil {
+			if reopenErr := s.changeRequestRepo.Reopen(ctx, id, reviewer); reopenErr != nil {
+				slog.ErrorContext(ctx, "failed to reopen change request after failed apply",
+					"change_request_id", id.Hex(), logging.Error(reopenErr))
+			}
+		}
+		return nil, err
+	}
+	slog.InfoContext(ctx, "change request approved", "action", approved.Action)
+	return approved, nil
+}
+
+// RejectChangeRequest closes a pending change request without applying it.
+// Requesters may reject their own requests to withdraw them.
+func (s *FeatureService) RejectChangeRequest(ctx context.Context, id primitive.ObjectID, comment string) (*models.ChangeRequest, error) {
+	ctx, span := start(ctx, "RejectChangeRequest", tracing.ChangeRequestID(id))
+	defer span.End()
+
+	reviewer := actorFromContext(ctx)
+	if reviewer == "" {
+		return nil, ErrReviewerRequired
+	}
+
+	if _, err := s.GetChangeRequest(ctx, id); err != nil {
+		return nil, err
+	}
+
+	request, err := s.changeRequestRepo.Resolve(ctx, id, models.ChangeRequestStatusRejected, reviewer, comment)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrChangeRequestClosed
+		}
+		return nil, err
+	}
+	slog.InfoContext(ctx, "change request rejected", "action", request.Action)
+	return request, nil
+}
+
+func (s *FeatureService) applyChangeRequest(ctx context.Context, request *models.ChangeRequest) error {
+	switch request.Action {
+	case models.ChangeRequestActionEnable:
+		return s.enableFeature(ctx, request.FeatureID)
+	case models.ChangeRequestActionDisable:
+		return s.disableFeature(ctx, request.FeatureID)
+	case models.ChangeRequestActionAddChild:
+		if request.ChildID == nil {
+			return errors.New("change request has no child feature")
+		}
+		return s.addChild(ctx, request.FeatureID, *request.ChildID)
+	case models.ChangeRequestActionSetTargeting:
+		if request.Targeting == nil {
+			return errors.New("change request has no targeting")
+		}
+		return s.setTargeting(ctx, request.FeatureID, *request.Targeting)
+	case models.ChangeRequestActionAddOverrides:
+		return s.addOverrides(ctx, request.FeatureID, request.Overrides)
+	case models.ChangeRequestActionRemoveOverrides:
+		return s.removeOverrides(ctx, request.FeatureID, request.Overrides)
+	case models.ChangeRequestActionSetRollout:
+		return s.setRollout(ctx, request.FeatureID, request.Rollout)
+	default:
+		return fmt.Errorf("unknown change request action %q", request.Action)
+	}
+}
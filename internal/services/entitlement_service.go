@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	"feature-flags/internal/evaluation"
+	"feature-flags/internal/models"
+	"feature-flags/internal/tracing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TenantAttribute is the default evaluation context attribute identifying
+// the tenant whose plan override applies.
+const TenantAttribute = "tenantId"
+
+// DefaultEntitlements gates features by the default plan hierarchy, read
+// from the plan context attribute.
+func DefaultEntitlements() evaluation.Entitlements {
+	return evaluation.Entitlements{Plans: models.DefaultPlanHierarchy(), PlanAttribute: "plan"}
+}
+
+// SetEntitlements sets the plan hierarchy and the context attributes holding
+// the plan and the tenant. Call it before serving requests.
+func (s *FeatureService) SetEntitlements(entitlements evaluation.Entitlements, tenantAttribute string) {
+	s.entitlements = entitlements
+	s.tenantAttribute = tenantAttribute
+}
+
+// SetTenantPlan puts a tenant on a plan, replacing any previous override.
+func (s *FeatureService) SetTenantPlan(ctx context.Context, plan *models.TenantPlan) error {
+	ctx, span := start(ctx, "SetTenantPlan", tracing.TenantIDKey.String(plan.TenantID))
+	defer span.End()
+
+	if plan.TenantID == "" {
+		return fmt.Errorf("%w: tenant ID is required", ErrInvalidPlan)
+	}
+	if err := s.checkPlan(plan.Plan); err != nil {
+		return err
+	}
+	plan.UpdatedBy = actorFromContext(ctx)
+	if err := s.tenantPlanRepo.Set(ctx, plan); err != nil {
+		return fmt.Errorf("failed to set tenant plan: %w", err)
+	}
+	return nil
+}
+
+// GetTenantPlan returns the plan override of a tenant.
+func (s *FeatureService) GetTenantPlan(ctx context.Context, tenantID string) (*models.TenantPlan, error) {
+	ctx, span := start(ctx, "GetTenantPlan", tracing.TenantIDKey.String(tenantID))
+	defer span.End()
+
+	plan, err := s.tenantPlanRepo.Get(ctx, tenantID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, &NotFoundError{Resource: "tenant plan", ID: tenantID}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant plan: %w", err)
+	}
+	return plan, nil
+}
+
+// ListTenantPlans returns every plan override, ordered by tenant ID.
+func (s *FeatureService) ListTenantPlans(ctx context.Context) ([]*models.TenantPlan, error) {
+	ctx, span := start(ctx, "ListTenantPlans")
+	defer span.End()
+
+	return s.tenantPlanRepo.List(ctx)
+}
+
+// DeleteTenantPlan removes a tenant's plan override, so the plan in its
+// evaluation contexts applies again.
+func (s *FeatureService) DeleteTenantPlan(ctx context.Context, tenantID string) error {
+	ctx, span := start(ctx, "DeleteTenantPlan", tracing.TenantIDKey.String(tenantID))
+	defer span.End()
+
+	err := s.tenantPlanRepo.Delete(ctx, tenantID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return &NotFoundError{Resource: "tenant plan", ID: tenantID}
+	}
+	return err
+}
+
+// Entitlements lists the features a tenant's plan entitles it to, whether
+// or not they are enabled. The tenant's override takes precedence over
+// plan, which is only required for tenants without one.
+func (s *FeatureService) Entitlements(ctx context.Context, tenantID, plan string) (*models.Entitlements, error) {
+	ctx, span := start(ctx, "Entitlements", tracing.TenantIDKey.String(tenantID))
+	defer span.End()
+
+	out := &models.Entitlements{TenantID: tenantID, Plan: plan, Features: []*models.Feature{}}
+	if tenantID != "" {
+		override, err := s.tenantPlanRepo.Get(ctx, tenantID)
+		switch {
+		case err == nil:
+			out.Plan, out.Override = override.Plan, true
+		case !errors.Is(err, mongo.ErrNoDocuments):
+			return nil, fmt.Errorf("failed to get tenant plan: %w", err)
+		}
+	}
+	if out.Plan == "" {
+		return nil, fmt.Errorf("%w: plan is required for tenants without a plan override", ErrInvalidPlan)
+	}
+	if err := s.checkPlan(out.Plan); err != nil {
+		return nil, err
+	}
+
+	features, err := s.featureRepo.Find(ctx, models.FeatureFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list features: %w", err)
+	}
+	for _, feature := range features {
+		// Features with unknown types are not gated; see evaluation.
+		if !feature.Type.Valid() || s.entitlements.Plans.Entitled(out.Plan, feature.Type) {
+			out.Features = append(out.Features, feature)
+		}
+	}
+	span.SetAttributes(tracing.ResultCountKey.Int(len(out.Features)))
+	return out, nil
+}
+
+func (s *FeatureService) checkPlan(plan string) error {
+	if _, ok := s.entitlements.Plans.Rank(plan); !ok {
+		return fmt.Errorf("%w: %q is not one of %v", ErrInvalidPlan, plan, s.entitlements.Plans)
+	}
+	return nil
+}
+
+// applyTenantPlan returns evalCtx with the plan replaced by the override of
+// the tenant it names, if there is one. evalCtx itself is not modified.
+func (s *FeatureService) applyTenantPlan(ctx context.Context, evalCtx evaluation.Context) (evaluation.Context, error) {
+	tenantID, ok := evalCtx[s.tenantAttribute].(string)
+	if !ok || tenantID == "" {
+		return evalCtx, nil
+	}
+	override, err := s.tenantPlanRepo.Get(ctx, tenantID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return evalCtx, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant plan: %w", err)
+	}
+	applied := maps.Clone(evalCtx)
+	applied[s.entitlements.PlanAttribute] = override.Plan
+	return applied, nil
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Error kinds. Errors caused by the request rather than by the server match
+// one of these with errors.Is, so transports can map them to status codes
+// without knowing every specific error.
+var (
+	ErrNotFound       = errors.New("not found")
+	ErrConflict       = errors.New("conflict")
+	ErrCycle          = errors.New("cyclic dependency detected")
+	ErrParentDisabled = errors.New("parent feature is disabled")
+	ErrOverrideLimit  = errors.New("too many overrides")
+)
+
+var (
+	ErrDependencyNotFound = kindError(ErrNotFound, "dependency not found")
+	ErrDependencyExists   = kindError(ErrConflict, "dependency already exists")
+	ErrFeatureExists      = kindError(ErrConflict, "feature key already exists")
+	// ErrConcurrentChange is returned when a resource changed between being
+	// read and written, so the write would undo the other change. Retrying
+	// applies it to the current state.
+	ErrConcurrentChange = kindError(ErrConflict, "changed concurrently by another request; retry")
+	ErrInvalidTargeting   = errors.New("invalid targeting")
+	ErrInvalidKey         = errors.New("invalid key")
+	ErrInvalidMetadata    = errors.New("invalid metadata")
+	ErrInvalidFeatureType = errors.New("invalid feature type")
+	ErrInvalidPlan        = errors.New("invalid plan")
+	ErrInvalidOverride    = errors.New("invalid override")
+)
+
+// kind is a specific error that also matches the broader kind it belongs
+// to, e.g. ErrDependencyExists is an ErrConflict.
+type kind struct {
+	kind    error
+	message string
+}
+
+func kindError(k error, message string) error {
+	return &kind{kind: k, message: message}
+}
+
+func (e *kind) Error() string { return e.message }
+func (e *kind) Unwrap() error { return e.kind }
+
+// NotFoundError reports a missing resource. It matches ErrNotFound.
+type NotFoundError struct {
+	Resource string
+	ID       string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s %s not found", e.Resource, e.ID)
+}
+
+func (e *NotFoundError) Unwrap() error { return ErrNotFound }
+
+// CycleError is returned when a dependency would make a feature depend on
+// itself. Path is the cycle the new edge would close, starting and ending
+// with the parent. It matches ErrCycle.
+type CycleError struct {
+	Path []primitive.ObjectID
+}
+
+func (e *CycleError) Error() string {
+	if len(e.Path) == 2 && e.Path[0] == e.Path[1] {
+		return fmt.Sprintf("%v: cannot add self as child", ErrCycle)
+	}
+	return fmt.Sprintf("%v: %s", ErrCycle, joinIDs(e.Path, " -> "))
+}
+
+func (e *CycleError) Unwrap() error { return ErrCycle }
+
+// ParentDisabledError is returned when a feature cannot be enabled because
+// some of its parents are disabled. It matches ErrParentDisabled.
+type ParentDisabledError struct {
+	FeatureID primitive.ObjectID
+	ParentIDs []primitive.ObjectID
+}
+
+func (e *ParentDisabledError) Error() string {
+	return fmt.Sprintf("cannot enable feature %s: %v: %s", e.FeatureID.Hex(), ErrParentDisabled, joinIDs(e.ParentIDs, ", "))
+}
+
+func (e *ParentDisabledError) Unwrap() error { return ErrParentDisabled }
+
+// OverrideLimitError is returned when a change would leave a feature with
+// more than Limit overrides. It matches ErrOverrideLimit.
+type OverrideLimitError struct {
+	FeatureID primitive.ObjectID
+	Limit     int
+	Count     int
+}
+
+func (e *OverrideLimitError) Error() string {
+	return fmt.Sprintf("%v: feature %s would have %d overrides, the limit is %d", ErrOverrideLimit, e.FeatureID.Hex(), e.Count, e.Limit)
+}
+
+func (e *OverrideLimitError) Unwrap() error { return ErrOverrideLimit }
+
+func joinIDs(ids []primitive.ObjectID, sep string) string {
+	hex := make([]string, len(ids))
+	for i, id := range ids {
+		hex[i] = id.Hex()
+	}
+	return strings.Join(hex, sep)
+}
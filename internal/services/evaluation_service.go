@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"feature-flags/internal/evaluation"
+	"feature-flags/internal/metrics"
+	"feature-flags/internal/tracing"
+)
+
+// snapshotMaxAge bounds how long a cached snapshot is used. Writes made by
+// other instances are seen within it even without change streams.
+const snapshotMaxAge = 10 * time.Second
+
+// snapshotCache holds the evaluation snapshot, so evaluations and watch
+// streams do not list every feature, dependency and segment each time.
+// Every write through the feature, dependency and segment repositories
+// invalidates it; writes made by other instances are picked up through
+// WatchChanges.
+type snapshotCache struct {
+	mu       sync.Mutex
+	snapshot *evaluation.Snapshot
+	loadedAt time.Time
+	// generation counts invalidations, so a load that raced with a write
+	// is not cached.
+	generation uint64
+}
+
+func (c *snapshotCache) get(ctx context.Context, load func(ctx context.Context) (*evaluation.Snapshot, error)) (*evaluation.Snapshot, error) {
+	c.mu.Lock()
+	snapshot, generation := c.snapshot, c.generation
+	if snapshot != nil && time.Since(c.loadedAt) > snapshotMaxAge {
+		snapshot = nil
+	}
+	c.mu.Unlock()
+	if snapshot != nil {
+		return snapshot, nil
+	}
+
+	loadedAt := time.Now()
+	snapshot, err := load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	metrics.SnapshotLoads.Inc()
+
+	c.mu.Lock()
+	if c.generation == generation {
+		c.snapshot = snapshot
+		c.loadedAt = loadedAt
+	}
+	c.mu.Unlock()
+	return snapshot, nil
+}
+
+func (c *snapshotCache) invalidate() {
+	c.mu.Lock()
+	c.snapshot = nil
+	c.generation++
+	c.mu.Unlock()
+}
+
+// Evaluate resolves the flag with key for an evaluation context.
+func (s *FeatureService) Evaluate(ctx context.Context, key string, evalCtx evaluation.Context) (evaluation.Result, error) {
+	ctx, span := start(ctx, "Evaluate", tracing.FlagKeyKey.String(key))
+	defer span.End()
+
+	evalCtx, err := s.applyTenantPlan(ctx, evalCtx)
+	if err != nil {
+		return evaluation.Result{}, err
+	}
+	snapshot, err := s.snapshot(ctx)
+	if err != nil {
+		return evaluation.Result{}, err
+	}
+	result := snapshot.Evaluate(key, evalCtx)
+	observeEvaluation(result)
+	s.recordExposures(snapshot, evalCtx, result)
+	return result, nil
+}
+
+// EvaluateAll resolves every flag for an evaluation context.
+func (s *FeatureService) EvaluateAll(ctx context.Context, evalCtx evaluation.Context) ([]evaluation.Result, error) {
+	ctx, span := start(ctx, "EvaluateAll")
+	defer span.End()
+
+	evalCtx, err := s.applyTenantPlan(ctx, evalCtx)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := s.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	results := snapshot.EvaluateAll(evalCtx)
+	span.SetAttributes(tracing.ResultCountKey.Int(len(results)))
+	for _, result := range results {
+		observeEvaluation(result)
+	}
+	s.recordExposures(snapshot, evalCtx, results...)
+	return results, nil
+}
+
+// observeEvaluation counts an evaluation. Unknown flags are not counted, so
+// arbitrary keys sent by clients do not create new series.
+func observeEvaluation(result evaluation.Result) {
+	if result.ErrorCode == evaluation.ErrorFlagNotFound {
+		return
+	}
+	metrics.ObserveEvaluation(result.Key, result.On())
+}
+
+// snapshot returns the cached evaluation snapshot, loading it if needed.
+// The snapshot is shared, so callers must not modify it.
+func (s *FeatureService) snapshot(ctx context.Context) (*evaluation.Snapshot, error) {
+	return s.snapshots.get(ctx, s.loadSnapshot)
+}
+
+func (s *FeatureService) loadSnapshot(ctx context.Context) (*evaluation.Snapshot, error) {
+	features, err := s.featureRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list features: %w", err)
+	}
+	dependencies, err := s.dependencyRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dependencies: %w", err)
+	}
+	segments, err := s.segmentRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list segments: %w", err)
+	}
+	return evaluation.NewSnapshot(features, dependencies).WithSegments(segments).WithEntitlements(&s.entitlements), nil
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"feature-flags/internal/evaluation"
+	"feature-flags/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotCache(t *testing.T) {
+	features := []*models.Feature{testFeature("checkout", true)}
+	loads := 0
+	load := func(context.Context) (*evaluation.Snapshot, error) {
+		loads++
+		return evaluation.NewSnapshot(features, nil), nil
+	}
+
+	var cache snapshotCache
+	ctx := context.Background()
+	snapshot, err := cache.get(ctx, load)
+	require.NoError(t, err)
+	assert.True(t, snapshot.Evaluate("checkout", evaluation.Context{}).On())
+
+	_, err = cache.get(ctx, load)
+	require.NoError(t, err)
+	assert.Equal(t, 1, loads, "cached snapshot is reused")
+
+	features = []*models.Feature{testFeature("checkout", false)}
+	cache.invalidate()
+	snapshot, err = cache.get(ctx, load)
+	require.NoError(t, err)
+	assert.False(t, snapshot.Evaluate("checkout", evaluation.Context{}).On())
+	assert.Equal(t, 2, loads)
+
+	// Writes by other instances are seen once the snapshot ages out.
+	cache.loadedAt = time.Now().Add(-snapshotMaxAge - time.Second)
+	_, err = cache.get(ctx, load)
+	require.NoError(t, err)
+	assert.Equal(t, 3, loads)
+}
+
+func TestSnapshotCache_InvalidatedDuringLoad(t *testing.T) {
+	var cache snapshotCache
+	ctx := context.Background()
+
+	_, err := cache.get(ctx, func(context.Context) (*evaluation.Snapshot, error) {
+		cache.invalidate()
+		return evaluation.NewSnapshot(nil, nil), nil
+	})
+	require.NoError(t, err)
+
+	loaded := false
+	_, err = cache.get(ctx, func(context.Context) (*evaluation.Snapshot, error) {
+		loaded = true
+		return evaluation.NewSnapshot(nil, nil), nil
+	})
+	require.NoError(t, err)
+	assert.True(t, loaded)
+}
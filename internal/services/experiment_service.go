@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"feature-flags/internal/evaluation"
+	"feature-flags/internal/events"
+	"feature-flags/internal/experiment"
+	"feature-flags/internal/models"
+	"feature-flags/internal/tracing"
+)
+
+// MaxConversionBatch is the most conversions one TrackConversions call
+// accepts.
+const MaxConversionBatch = 1000
+
+// MaxClockSkew is how far past the current time a client-supplied event
+// timestamp may be. Later timestamps are rejected rather than counted in
+// windows they do not belong to.
+const MaxClockSkew = 5 * time.Minute
+
+// DefaultConfidence is the confidence level of experiment results when none
+// is given.
+const DefaultConfidence = 0.95
+
+var (
+	ErrInvalidEvent      = errors.New("invalid event")
+	ErrInvalidExperiment = errors.New("invalid experiment query")
+)
+
+// ExperimentOptions narrows experiment results. Zero fields take defaults:
+// the feature's default variant (or its first) as control, every event
+// recorded, and DefaultConfidence.
+type ExperimentOptions struct {
+	Control    string
+	Since      time.Time
+	Until      time.Time
+	Confidence float64
+}
+
+// SetExposureRecorder records an exposure for every variant served by
+// Evaluate and EvaluateAll. Call it before serving requests; without a
+// recorder no exposures are recorded.
+func (s *FeatureService) SetExposureRecorder(recorder *events.Recorder) {
+	s.exposures = recorder
+}
+
+// recordExposures queues an exposure for each result that served a declared
+// variant to a context with a targeting key. Errors and plain on/off flags
+// are not experiments and are skipped.
+func (s *FeatureService) recordExposures(snapshot *evaluation.Snapshot, evalCtx evaluation.Context, results ...evaluation.Result) {
+	if s.exposures == nil {
+		return
+	}
+	targetingKey, ok := evalCtx[evaluation.TargetingKey].(string)
+	if !ok || targetingKey == "" {
+		return
+	}
+	now := time.Now()
+	for _, result := range results {
+		if result.Variant == "" || result.ErrorCode != "" || !snapshot.HasVariants(result.Key) {
+			continue
+		}
+		s.exposures.Record(models.Exposure{
+			FlagKey:      result.Key,
+			Variant:      result.Variant,
+			TargetingKey: targetingKey,
+			Reason:       string(result.Reason),
+			Timestamp:    now,
+		})
+	}
+}
+
+// TrackConversions stores conversion events. Every conversion is validated
+// before any is stored; those without a timestamp get the current time and
+// those more than MaxClockSkew in the future are rejected.
+func (s *FeatureService) TrackConversions(ctx context.Context, conversions []models.Conversion) error {
+	ctx, span := start(ctx, "TrackConversions", tracing.EventCountKey.Int(len(conversions)))
+	defer span.End()
+
+	if len(conversions) == 0 {
+		return fmt.Errorf("%w: at least one conversion is required", ErrInvalidEvent)
+	}
+	if len(conversions) > MaxConversionBatch {
+		return fmt.Errorf("%w: at most %d conversions may be sent at once", ErrInvalidEvent, MaxConversionBatch)
+	}
+	now := time.Now()
+	for i := range conversions {
+		if err := conversions[i].Validate(); err != nil {
+			return fmt.Errorf("%w: conversions[%d]: %v", ErrInvalidEvent, i, err)
+		}
+		if conversions[i].Timestamp.IsZero() {
+			conversions[i].Timestamp = now
+		}
+		if err := checkTimestamp(conversions[i].Timestamp, now); err != nil {
+			return fmt.Errorf("%w: conversions[%d]: %v", ErrInvalidEvent, i, err)
+		}
+	}
+	if err := s.eventRepo.InsertConversions(ctx, conversions); err != nil {
+		return fmt.Errorf("failed to store conversions: %w", err)
+	}
+	return nil
+}
+
+// ExperimentResults compares the conversion rates for event of the variants
+// of the flag with key. Only contexts assigned by a weighted split count,
+// each with the variant of its first exposure; a context converted if it
+// sent event at or after that exposure.
+func (s *FeatureService) ExperimentResults(ctx context.Context, key, event string, opts ExperimentOptions) (*models.ExperimentResults, error) {
+	ctx, span := start(ctx, "ExperimentResults", tracing.FlagKeyKey.String(key), tracing.EventNameKey.String(event))
+	defer span.End()
+
+	if event == "" {
+		return nil, fmt.Errorf("%w: event is required", ErrInvalidExperiment)
+	}
+	if opts.Confidence == 0 {
+		opts.Confidence = DefaultConfidence
+	}
+	if opts.Confidence <= 0 || opts.Confidence >= 1 {
+		return nil, fmt.Errorf("%w: confidence must be between 0 and 1", ErrInvalidExperiment)
+	}
+	if !opts.Since.IsZero() && !opts.Until.IsZero() && !opts.Since.Before(opts.Until) {
+		return nil, fmt.Errorf("%w: since must be before until", ErrInvalidExperiment)
+	}
+	feature, err := s.GetFeatureByKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	variants := make([]string, len(feature.Variants))
+	for i, variant := range feature.Variants {
+		variants[i] = variant.Name
+	}
+	if len(variants) == 0 {
+		return nil, fmt.Errorf("%w: feature %s has no variants", ErrInvalidExperiment, key)
+	}
+	control := opts.Control
+	if control == "" {
+		control = feature.DefaultVariant
+	}
+	if control == "" {
+		control = variants[0]
+	}
+	if !slices.Contains(variants, control) {
+		return nil, fmt.Errorf("%w: control %q is not a variant of %s", ErrInvalidExperiment, control, key)
+	}
+
+	filter := models.EventFilter{FlagKey: key, Event: event, Since: opts.Since, Until: opts.Until}
+	units, err := s.eventRepo.FirstExposures(ctx, filter, []string{string(evaluation.ReasonSplit)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load exposures: %w", err)
+	}
+	conversions, err := s.eventRepo.LastConversions(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load conversions: %w", err)
+	}
+	span.SetAttributes(tracing.ResultCountKey.Int(len(units)))
+
+	results := &models.ExperimentResults{
+		FlagKey:    key,
+		Event:      event,
+		Control:    control,
+		Confidence: opts.Confidence,
+		Variants:   experiment.Analyze(units, conversions, variants, control, opts.Confidence),
+	}
+	if !opts.Since.IsZero() {
+		results.Since = &opts.Since
+	}
+	if !opts.Until.IsZero() {
+		results.Until = &opts.Until
+	}
+	return results, nil
+}
+
+// ExportExposures calls fn with every exposure matching filter, oldest
+// first.
+func (s *FeatureService) ExportExposures(ctx context.Context, filter models.EventFilter, fn func(*models.Exposure) error) error {
+	ctx, span := start(ctx, "ExportExposures", tracing.FlagKeyKey.String(filter.FlagKey))
+	defer span.End()
+
+	return s.eventRepo.EachExposure(ctx, filter, fn)
+}
+
+// ExportConversions calls fn with every conversion matching filter, oldest
+// first.
+func (s *FeatureService) ExportConversions(ctx context.Context, filter models.EventFilter, fn func(*models.Conversion) error) error {
+	ctx, span := start(ctx, "ExportConversions", tracing.EventNameKey.String(filter.Event))
+	defer span.End()
+
+	return s.eventRepo.EachConversion(ctx, filter, fn)
+}
+
+// checkTimestamp rejects a client-supplied timestamp more than MaxClockSkew
+// past now.
+func checkTimestamp(timestamp, now time.Time) error {
+	if timestamp.After(now.Add(MaxClockSkew)) {
+		return fmt.Errorf("timestamp %s is more than %s in the future", timestamp.Format(time.RFC3339), MaxClockSkew)
+	}
+	return nil
+}
@@ -3,171 +3,741 @@ package services
 import (
 	"context"
 	"errors"
+	"feature-flags/internal/evaluation"
+	"feature-flags/internal/events"
+	"feature-flags/internal/metrics"
 	"feature-flags/internal/models"
 	"feature-flags/internal/repository/mongodb"
+	"feature-flags/internal/tracing"
 	"fmt"
-	"log"
+	"log/slog"
+	"slices"
 	"time"
 
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type FeatureService struct {
-	featureRepo    *mongodb.FeatureRepository
-	dependencyRepo *mongodb.FeatureDependencyRepository
+	featureRepo       *mongodb.FeatureRepository
+	dependencyRepo    *mongodb.FeatureDependencyRepository
+	changeRequestRepo *mongodb.ChangeRequestRepository
+	tenantPlanRepo    *mongodb.TenantPlanRepository
+	segmentRepo       *mongodb.SegmentRepository
+	eventRepo         *mongodb.EventRepository
+	signalRepo        *mongodb.SignalRepository
+	auditRepo         *mongodb.AuditRepository
+	txManager         *mongodb.TxManager
+	managed           managedSet
+	graph             graphCache
+	snapshots         snapshotCache
+	attributeSchema   models.AttributeSchema
+	entitlements      evaluation.Entitlements
+	tenantAttribute   string
+	exposures         *events.Recorder
+	approvalsDisabled bool
 }
 
-func NewFeatureService(featureRepo *mongodb.FeatureRepository, dependencyRepo *mongodb.FeatureDependencyRepository) *FeatureService {
-	return &FeatureService{
-		featureRepo:    featureRepo,
-		dependencyRepo: dependencyRepo,
+func NewFeatureService(featureRepo *mongodb.FeatureRepository, dependencyRepo *mongodb.FeatureDependencyRepository, changeRequestRepo *mongodb.ChangeRequestRepository, tenantPlanRepo *mongodb.TenantPlanRepository, segmentRepo *mongodb.SegmentRepository, eventRepo *mongodb.EventRepository, signalRepo *mongodb.SignalRepository, auditRepo *mongodb.AuditRepository, txManager *mongodb.TxManager) *FeatureService {
+	s := &FeatureService{
+		featureRepo:       featureRepo,
+		dependencyRepo:    dependencyRepo,
+		changeRequestRepo: changeRequestRepo,
+		tenantPlanRepo:    tenantPlanRepo,
+		segmentRepo:       segmentRepo,
+		eventRepo:         eventRepo,
+		signalRepo:        signalRepo,
+		auditRepo:         auditRepo,
+		txManager:         txManager,
+		entitlements:      DefaultEntitlements(),
+		tenantAttribute:   TenantAttribute,
 	}
+	featureRepo.OnChange(s.snapshots.invalidate)
+	dependencyRepo.OnChange(s.snapshots.invalidate)
+	segmentRepo.OnChange(s.snapshots.invalidate)
+	return s
 }
 
 func (s *FeatureService) CreateFeature(ctx context.Context, feature *models.Feature) error {
-	return s.featureRepo.Create(ctx, feature)
+	ctx, span := start(ctx, "CreateFeature")
+	defer span.End()
+
+	if feature.Key == "" {
+		feature.Key = models.KeyFromName(feature.Name)
+	}
+	if err := s.checkUnmanaged(feature); err != nil {
+		return err
+	}
+	if err := s.checkProtectable(feature.Protected); err != nil {
+		return err
+	}
+	if err := models.ValidateKey(feature.Key); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+	if !feature.Type.Valid() {
+		return fmt.Errorf("%w: %q is not one of %v", ErrInvalidFeatureType, feature.Type, models.FeatureTypes)
+	}
+	if err := feature.Targeting.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTargeting, err)
+	}
+	if err := s.checkSegments(ctx, &feature.Targeting); err != nil {
+		return err
+	}
+	if err := feature.Metadata.Validate(s.attributeSchema); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMetadata, err)
+	}
+	return s.createFeature(ctx, feature)
 }
 
-func (s *FeatureService) AddChild(ctx context.Context, parentID, childID primitive.ObjectID) error {
-	// Check for cyclic dependency
-	if err := s.checkCyclicDependency(ctx, parentID, childID); err != nil {
+// SetAttributeSchema sets the custom attributes features may carry. Call it
+// before serving requests; with no schema attributes are free-form.
+func (s *FeatureService) SetAttributeSchema(schema models.AttributeSchema) {
+	s.attributeSchema = schema
+}
+
+// createFeature inserts feature as version 1, reporting a taken key as
+// ErrFeatureExists.
+func (s *FeatureService) createFeature(ctx context.Context, feature *models.Feature) error {
+	feature.Version = 1
+	feature.CreatedBy = actorFromContext(ctx)
+	err := s.featureRepo.Create(ctx, feature)
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("%w: %s", ErrFeatureExists, feature.Key)
+	}
+	return err
+}
+
+// GetFeatureByKey returns the feature with key.
+func (s *FeatureService) GetFeatureByKey(ctx context.Context, key string) (*models.Feature, error) {
+	ctx, span := start(ctx, "GetFeatureByKey", tracing.FlagKeyKey.String(key))
+	defer span.End()
+
+	feature, err := s.featureRepo.GetByKey(ctx, key)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, &NotFoundError{Resource: "feature", ID: key}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feature: %w", err)
+	}
+	return feature, nil
+}
+
+// ListFeatures returns the features matching filter, ordered by name.
+func (s *FeatureService) ListFeatures(ctx context.Context, filter models.FeatureFilter) ([]*models.Feature, error) {
+	ctx, span := start(ctx, "ListFeatures")
+	defer span.End()
+
+	return s.featureRepo.Find(ctx, filter)
+}
+
+func (s *FeatureService) ListDependencies(ctx context.Context) ([]models.FeatureDependency, error) {
+	ctx, span := start(ctx, "ListDependencies")
+	defer span.End()
+
+	return s.dependencyRepo.List(ctx)
+}
+
+// Counts returns the number of features and dependency edges.
+func (s *FeatureService) Counts(ctx context.Context) (features, dependencies int64, err error) {
+	ctx, span := start(ctx, "Counts")
+	defer span.End()
+
+	features, err = s.featureRepo.Count(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count features: %w", err)
+	}
+	dependencies, err = s.dependencyRepo.Count(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count dependencies: %w", err)
+	}
+	return features, dependencies, nil
+}
+
+// GetDependencies returns the direct parents and children of a feature.
+func (s *FeatureService) GetDependencies(ctx context.Context, id primitive.ObjectID) (parents, children []primitive.ObjectID, err error) {
+	ctx, span := start(ctx, "GetDependencies", tracing.FeatureID(id))
+	defer span.End()
+
+	if _, err := s.getFeature(ctx, id); err != nil {
+		return nil, nil, err
+	}
+
+	graph, err := s.dependencyGraph(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	children, _ = graph.Children(ctx, id)
+	return graph.Parents(id), children, nil
+}
+
+// RemoveChild deletes the dependency of childID on parentID.
+func (s *FeatureService) RemoveChild(ctx context.Context, parentID, childID primitive.ObjectID) error {
+	ctx, span := start(ctx, "RemoveChild", tracing.ParentID(parentID), tracing.ChildID(childID))
+	defer span.End()
+
+	if err := s.checkUnmanagedIDs(ctx, parentID, childID); err != nil {
 		return err
 	}
 
-	// Check if dependency already exists
 	exists, err := s.dependencyRepo.Exists(ctx, parentID, childID)
 	if err != nil {
 		return err
 	}
-	if exists {
-		return errors.New("dependency already exists")
+	if !exists {
+		return ErrDependencyNotFound
 	}
+	defer s.graph.invalidate()
+	return s.dependencyRepo.Delete(ctx, parentID, childID)
+}
+
+// AddChild makes childID depend on parentID. If either feature is protected
+// a pending change request is created instead and a *PendingChangeError is
+// returned.
+func (s *FeatureService) AddChild(ctx context.Context, parentID, childID primitive.ObjectID) error {
+	ctx, span := start(ctx, "AddChild", tracing.ParentID(parentID), tracing.ChildID(childID))
+	defer span.End()
+
+	features, err := s.validateDependency(ctx, parentID, childID)
+	if err != nil {
+		return err
+	}
+
+	if anyProtected(features) {
+		return s.submitChangeRequest(ctx, &models.ChangeRequest{
+			Action:    models.ChangeRequestActionAddChild,
+			FeatureID: parentID,
+			ChildID:   &childID,
+			Diff: models.ChangeRequestDiff{
+				Features:     []models.FeatureChange{},
+				Dependencies: []models.DependencyChange{{ParentID: parentID, ChildID: childID}},
+			},
+		})
+	}
+
+	return s.createDependency(ctx, parentID, childID)
+}
 
+func (s *FeatureService) addChild(ctx context.Context, parentID, childID primitive.ObjectID) error {
+	if _, err := s.validateDependency(ctx, parentID, childID); err != nil {
+		return err
+	}
+	return s.createDependency(ctx, parentID, childID)
+}
+
+func (s *FeatureService) createDependency(ctx context.Context, parentID, childID primitive.ObjectID) error {
 	// Create the dependency
 	dependency := &models.FeatureDependency{
 		ParentID: parentID,
 		ChildID:  childID,
 	}
-	return s.dependencyRepo.Create(ctx, dependency)
+	err := s.dependencyRepo.Create(ctx, dependency)
+	s.graph.invalidate()
+	// The unique index catches a dependency created since validateDependency
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDependencyExists
+	}
+	return err
 }
 
+// validateDependency checks that childID may depend on parentID and returns
+// both features.
+func (s *FeatureService) validateDependency(ctx context.Context, parentID, childID primitive.ObjectID) ([]*models.Feature, error) {
+	features, err := s.getFeatures(ctx, parentID, childID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkUnmanaged(features...); err != nil {
+		return nil, err
+	}
+
+	// Check for cyclic dependency
+	if err := s.checkCyclicDependency(ctx, parentID, childID); err != nil {
+		return nil, err
+	}
+
+	// Check if dependency already exists
+	exists, err := s.dependencyRepo.Exists(ctx, parentID, childID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrDependencyExists
+	}
+	return features, nil
+}
+
+// DisableFeature disables the feature and every enabled feature that
+// depends on it. If any feature in that cascade is protected a pending change
+// request is created instead and a *PendingChangeError is returned.
 func (s *FeatureService) DisableFeature(ctx context.Context, id primitive.ObjectID) error {
-	// Queue for BFS
-	queue := []primitive.ObjectID{id}
-	// Track all features to disable
-	featuresToDisable := make([]primitive.ObjectID, 0)
-	// Track disabled features for logging
-	disabledFeatures := make([]string, 0)
+	ctx, span := start(ctx, "DisableFeature", tracing.FeatureID(id))
+	defer span.End()
 
-	// First collect all features to disable
-	for len(queue) > 0 {
-		currentID := queue[0]
-		queue = queue[1:]
+	_, err := s.requestDisable(ctx, id)
+	return err
+}
 
-		feature, err := s.featureRepo.GetByID(ctx, currentID)
+// requestDisable does the work of DisableFeature and also returns the
+// cascade it disabled, or would disable once the change request is approved.
+func (s *FeatureService) requestDisable(ctx context.Context, id primitive.ObjectID) ([]*models.Feature, error) {
+	cascade, err := s.collectDisableCascade(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkUnmanaged(cascade...); err != nil {
+		return nil, err
+	}
+
+	if anyProtected(cascade) {
+		return cascade, s.submitChangeRequest(ctx, &models.ChangeRequest{
+			Action:    models.ChangeRequestActionDisable,
+			FeatureID: id,
+			Diff:      models.ChangeRequestDiff{Features: enabledChanges(cascade, false)},
+		})
+	}
+
+	return cascade, s.applyDisable(ctx, cascade)
+}
+
+// PreviewDisable returns the features DisableFeature would disable, without
+// changing anything.
+func (s *FeatureService) PreviewDisable(ctx context.Context, id primitive.ObjectID) ([]*models.Feature, error) {
+	ctx, span := start(ctx, "PreviewDisable", tracing.FeatureID(id))
+	defer span.End()
+
+	return s.collectDisableCascade(ctx, id)
+}
+
+func (s *FeatureService) disableFeature(ctx context.Context, id primitive.ObjectID) error {
+	cascade, err := s.collectDisableCascade(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.checkUnmanaged(cascade...); err != nil {
+		return err
+	}
+	return s.applyDisable(ctx, cascade)
+}
+
+// collectDisableCascade returns the feature and all of its enabled
+// descendants, in BFS order. Already disabled features stop the traversal.
+// The graph is loaded afresh and each level of the traversal with one
+// query.
+func (s *FeatureService) collectDisableCascade(ctx context.Context, id primitive.ObjectID) ([]*models.Feature, error) {
+	graph, err := s.currentDependencyGraph(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Track all features to disable
+	featuresToDisable := make([]*models.Feature, 0)
+	// A feature reachable through several parents is visited once
+	seen := map[primitive.ObjectID]bool{id: true}
+	level := []primitive.ObjectID{id}
+	// Distance from the disabled feature, reported as the cascade depth
+	depth, maxDepth := 0, 0
+
+	for ; len(level) > 0; depth++ {
+		features, err := s.getFeatures(ctx, level...)
 		if err != nil {
-			return fmt.Errorf("failed to get feature: %w", err)
+			return nil, err
 		}
 
-		// Skip if already disabled
-		if !feature.IsEnabled {
-			continue
+		var enabled []primitive.ObjectID
+		for _, feature := range features {
+			// Skip if already disabled
+			if !feature.IsEnabled {
+				continue
+			}
+			featuresToDisable = append(featuresToDisable, feature)
+			enabled = append(enabled, feature.ID)
+			maxDepth = depth
 		}
 
-		// Add to features to disable
-		featuresToDisable = append(featuresToDisable, currentID)
-		disabledFeatures = append(disabledFeatures, feature.Name)
-
-		// Get children and add to queue
-		children, err := s.dependencyRepo.GetChildren(ctx, currentID)
-		if err != nil {
-			return fmt.Errorf("failed to get children: %w", err)
+		var next []primitive.ObjectID
+		for _, childID := range graph.ChildrenOf(enabled...) {
+			if !seen[childID] {
+				seen[childID] = true
+				next = append(next, childID)
+			}
 		}
-		queue = append(queue, children...)
+		level = next
+	}
+
+	tracing.SetAttributes(ctx,
+		tracing.CascadeSizeKey.Int(len(featuresToDisable)),
+		tracing.CascadeDepthKey.Int(maxDepth),
+	)
+	return featuresToDisable, nil
+}
+
+func (s *FeatureService) applyDisable(ctx context.Context, cascade []*models.Feature) error {
+	featuresToDisable := make([]primitive.ObjectID, len(cascade))
+	// Track disabled features for logging
+	disabledFeatures := make([]string, len(cascade))
+	for i, feature := range cascade {
+		featuresToDisable[i] = feature.ID
+		disabledFeatures[i] = feature.Name
+	}
+
+	if len(featuresToDisable) == 0 {
+		return nil
 	}
 
 	// Disable all features in one bulk update
-	if len(featuresToDisable) > 0 {
-		if err := s.featureRepo.BulkUpdate(ctx, featuresToDisable, bson.M{
-			"is_enabled": false,
-			"updated_at": time.Now(),
-		}); err != nil {
-			return fmt.Errorf("failed to bulk disable features: %w", err)
-		}
+	disabled, err := s.featureRepo.SetEnabled(ctx, featuresToDisable, false)
+	if err != nil {
+		return fmt.Errorf("failed to bulk disable features: %w", err)
+	}
+	// Someone else disabled the cascade first.
+	if disabled == 0 {
+		return nil
 	}
 
-	log.Printf("Successfully disabled %d features: %v", len(disabledFeatures), disabledFeatures)
+	metrics.FeatureOperations.WithLabelValues("disable", "applied").Inc()
+	metrics.DisableCascadeSize.Observe(float64(disabled))
+	slog.InfoContext(ctx, "features disabled", "count", disabled, "features", disabledFeatures)
 	return nil
 }
 
+// EnableFeature enables the feature once all of its parents are enabled. If
+// the feature is protected a pending change request is created instead and a
+// *PendingChangeError is returned.
 func (s *FeatureService) EnableFeature(ctx context.Context, id primitive.ObjectID) error {
-	feature, err := s.featureRepo.GetByID(ctx, id)
+	ctx, span := start(ctx, "EnableFeature", tracing.FeatureID(id))
+	defer span.End()
+
+	feature, err := s.getFeature(ctx, id)
 	if err != nil {
 		return err
 	}
+	if err := s.checkUnmanaged(feature); err != nil {
+		return err
+	}
+
+	if err := s.checkParentsEnabled(ctx, id); err != nil {
+		return err
+	}
+
+	if feature.Protected {
+		return s.submitChangeRequest(ctx, &models.ChangeRequest{
+			Action:    models.ChangeRequestActionEnable,
+			FeatureID: id,
+			Diff:      models.ChangeRequestDiff{Features: enabledChanges([]*models.Feature{feature}, true)},
+		})
+	}
+
+	return s.applyEnable(ctx, feature)
+}
 
-	// Get all parents
-	parents, err := s.dependencyRepo.GetParents(ctx, id)
+func (s *FeatureService) enableFeature(ctx context.Context, id primitive.ObjectID) error {
+	feature, err := s.getFeature(ctx, id)
 	if err != nil {
-		return fmt.Errorf("failed to get parents: %w", err)
+		return err
+	}
+	if err := s.checkUnmanaged(feature); err != nil {
+		return err
 	}
 
-	// Check if any parent is disabled
-	for _, parentID := range parents {
-		parent, err := s.featureRepo.GetByID(ctx, parentID)
-		if err != nil {
-			return fmt.Errorf("failed to get parent feature: %w", err)
-		}
+	if err := s.checkParentsEnabled(ctx, id); err != nil {
+		return err
+	}
+
+	return s.applyEnable(ctx, feature)
+}
+
+func (s *FeatureService) checkParentsEnabled(ctx context.Context, id primitive.ObjectID) error {
+	graph, err := s.currentDependencyGraph(ctx)
+	if err != nil {
+		return err
+	}
+
+	parents, err := s.getFeatures(ctx, graph.ParentsOf(id)...)
+	if err != nil {
+		return err
+	}
+
+	// Report every disabled parent, not just the first
+	var disabled []primitive.ObjectID
+	for _, parent := range parents {
 		if !parent.IsEnabled {
-			return errors.New("cannot enable feature: parent feature is disabled")
+			disabled = append(disabled, parent.ID)
 		}
 	}
+	if len(disabled) > 0 {
+		return &ParentDisabledError{FeatureID: id, ParentIDs: disabled}
+	}
+	return nil
+}
 
+func (s *FeatureService) applyEnable(ctx context.Context, feature *models.Feature) error {
+	if !feature.IsEnabled {
+		feature.Version++
+	}
 	feature.IsEnabled = true
 	feature.UpdatedAt = time.Now()
-	return s.featureRepo.Update(ctx, feature)
+	if _, err := s.featureRepo.SetEnabled(ctx, []primitive.ObjectID{feature.ID}, true); err != nil {
+		return err
+	}
+	metrics.FeatureOperations.WithLabelValues("enable", "applied").Inc()
+	slog.InfoContext(ctx, "feature enabled", "feature", feature.Name)
+	return nil
 }
 
+// childrenFunc returns the direct children of a feature. It lets the cycle
+// check run against the repository or against an in-memory graph.
+type childrenFunc func(ctx context.Context, id primitive.ObjectID) ([]primitive.ObjectID, error)
+
 func (s *FeatureService) checkCyclicDependency(ctx context.Context, parentID, childID primitive.ObjectID) error {
+	graph, err := s.currentDependencyGraph(ctx)
+	if err != nil {
+		return err
+	}
+	err = detectCycle(ctx, parentID, childID, graph.Children)
+	if errors.Is(err, ErrCycle) {
+		metrics.CycleRejections.Inc()
+	}
+	return err
+}
+
+// detectCycle reports whether adding the edge parentID -> childID would
+// create a cycle, i.e. whether parentID is reachable from childID. The
+// returned *CycleError holds the path around the cycle.
+func detectCycle(ctx context.Context, parentID, childID primitive.ObjectID, children childrenFunc) error {
 	if parentID == childID {
-		return errors.New("cannot add self as child")
+		return &CycleError{Path: []primitive.ObjectID{parentID, childID}}
 	}
 
 	visited := make(map[primitive.ObjectID]bool)
-	return s.dfs(ctx, childID, parentID, visited)
+	path, err := dfs(ctx, childID, parentID, visited, children)
+	if err != nil {
+		return err
+	}
+	if path == nil {
+		return nil
+	}
+	path = append(path, parentID)
+	slices.Reverse(path)
+	return &CycleError{Path: path}
 }
 
-func (s *FeatureService) dfs(ctx context.Context, current, target primitive.ObjectID, visited map[primitive.ObjectID]bool) error {
+// dfs returns the path from target back to current, or nil if target is not
+// reachable. It is built backwards so each level appends instead of
+// copying the path.
+func dfs(ctx context.Context, current, target primitive.ObjectID, visited map[primitive.ObjectID]bool, children childrenFunc) ([]primitive.ObjectID, error) {
 	if current == target {
-		return errors.New("cyclic dependency detected")
+		return []primitive.ObjectID{current}, nil
 	}
 
 	if visited[current] {
-		return nil
+		return nil, nil
 	}
 
 	visited[current] = true
 
 	// Get children of current feature
-	children, err := s.dependencyRepo.GetChildren(ctx, current)
+	childIDs, err := children(ctx, current)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	for _, childID := range children {
-		if err := s.dfs(ctx, childID, target, visited); err != nil {
-			return err
+	for _, childID := range childIDs {
+		path, err := dfs(ctx, childID, target, visited, children)
+		if err != nil {
+			return nil, err
+		}
+		if path != nil {
+			return append(path, current), nil
 		}
 	}
 
-	return nil
+	return nil, nil
 }
 
 func (s *FeatureService) GetFeatureStatus(ctx context.Context, id primitive.ObjectID) (*models.Feature, error) {
+	ctx, span := start(ctx, "GetFeatureStatus", tracing.FeatureID(id))
+	defer span.End()
+
+	feature, err := s.getFeature(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return feature, nil
+}
+
+// SetProtected marks a feature as protected, so that enabling, disabling or
+// adding dependencies to it requires an approved change request. With
+// approvals disabled features can only be unprotected.
+func (s *FeatureService) SetProtected(ctx context.Context, id primitive.ObjectID, protected bool) (*models.Feature, error) {
+	ctx, span := start(ctx, "SetProtected", tracing.FeatureID(id))
+	defer span.End()
+
+	feature, err := s.getFeature(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkUnmanaged(feature); err != nil {
+		return nil, err
+	}
+	if err := s.checkProtectable(protected); err != nil {
+		return nil, err
+	}
+
+	feature.Protected = protected
+	if err := s.featureRepo.SetProtected(ctx, feature); err != nil {
+		return nil, err
+	}
+	return feature, nil
+}
+
+// SetTargeting replaces the rules and variants of a feature. If the feature
+// is protected a pending change request is created instead and a
+// *PendingChangeError is returned.
+func (s *FeatureService) SetTargeting(ctx context.Context, id primitive.ObjectID, targeting models.Targeting) (*models.Feature, error) {
+	ctx, span := start(ctx, "SetTargeting", tracing.FeatureID(id))
+	defer span.End()
+
+	if err := targeting.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTargeting, err)
+	}
+
+	feature, err := s.getFeature(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkUnmanaged(feature); err != nil {
+		return nil, err
+	}
+	if err := checkOverrideVariants(feature, &targeting); err != nil {
+		return nil, err
+	}
+	if err := s.checkSegments(ctx, &targeting); err != nil {
+		return nil, err
+	}
+
+	if feature.Protected {
+		return nil, s.submitChangeRequest(ctx, &models.ChangeRequest{
+			Action:    models.ChangeRequestActionSetTargeting,
+			FeatureID: id,
+			Targeting: &targeting,
+			Diff: models.ChangeRequestDiff{Features: []models.FeatureChange{{
+				FeatureID: id,
+				Name:      feature.Name,
+				Field:     "targeting",
+				From:      feature.Targeting,
+				To:        targeting,
+			}}},
+		})
+	}
+
+	return s.applyTargeting(ctx, feature, targeting)
+}
+
+func (s *FeatureService) setTargeting(ctx context.Context, id primitive.ObjectID, targeting models.Targeting) error {
+	feature, err := s.getFeature(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.checkUnmanaged(feature); err != nil {
+		return err
+	}
+	if err := checkOverrideVariants(feature, &targeting); err != nil {
+		return err
+	}
+	// A segment may have been deleted while the request was pending.
+	if err := s.checkSegments(ctx, &targeting); err != nil {
+		return err
+	}
+	_, err = s.applyTargeting(ctx, feature, targeting)
+	return err
+}
+
+func (s *FeatureService) applyTargeting(ctx context.Context, feature *models.Feature, targeting models.Targeting) (*models.Feature, error) {
+	feature.Targeting = targeting
+	feature.Version++
+	if err := s.featureRepo.SetTargeting(ctx, feature); err != nil {
+		return nil, err
+	}
+	return feature, nil
+}
+
+// SetMetadata replaces the metadata of a feature. Metadata does not affect
+// evaluation, so protection and GitOps ownership do not apply and the
+// feature's Version is unchanged.
+func (s *FeatureService) SetMetadata(ctx context.Context, id primitive.ObjectID, metadata models.Metadata) (*models.Feature, error) {
+	ctx, span := start(ctx, "SetMetadata", tracing.FeatureID(id))
+	defer span.End()
+
+	if err := metadata.Validate(s.attributeSchema); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMetadata, err)
+	}
+	feature, err := s.featureRepo.UpdateMetadata(ctx, id, metadata)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, &NotFoundError{Resource: "feature", ID: id.Hex()}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update metadata: %w", err)
+	}
+	return feature, nil
+}
+
+// getFeature loads a feature, reporting a missing one as a *NotFoundError.
+func (s *FeatureService) getFeature(ctx context.Context, id primitive.ObjectID) (*models.Feature, error) {
 	feature, err := s.featureRepo.GetByID(ctx, id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, &NotFoundError{Resource: "feature", ID: id.Hex()}
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get feature: %w", err)
 	}
 	return feature, nil
 }
+
+// getFeatures loads features in one query and returns them in the order of
+// ids. A missing feature is reported as a *NotFoundError.
+func (s *FeatureService) getFeatures(ctx context.Context, ids ...primitive.ObjectID) ([]*models.Feature, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	found, err := s.featureRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get features: %w", err)
+	}
+	byID := make(map[primitive.ObjectID]*models.Feature, len(found))
+	for _, feature := range found {
+		byID[feature.ID] = feature
+	}
+
+	features := make([]*models.Feature, len(ids))
+	for i, id := range ids {
+		feature, ok := byID[id]
+		if !ok {
+			return nil, &NotFoundError{Resource: "feature", ID: id.Hex()}
+		}
+		features[i] = feature
+	}
+	return features, nil
+}
+
+func anyProtected(features []*models.Feature) bool {
+	for _, feature := range features {
+		if feature.Protected {
+			return true
+		}
+	}
+	return false
+}
+
+func enabledChanges(features []*models.Feature, enabled bool) []models.FeatureChange {
+	changes := make([]models.FeatureChange, len(features))
+	for i, feature := range features {
+		changes[i] = models.FeatureChange{
+			FeatureID: feature.ID,
+			Name:      feature.Name,
+			Field:     "is_enabled",
+			From:      feature.IsEnabled,
+			To:        enabled,
+		}
+	}
+	return changes
+}
@@ -2,12 +2,22 @@ package services
 
 import (
 	"context"
+	"feature-flags/internal/auth"
+	"feature-flags/internal/evaluation"
+	"feature-flags/internal/events"
+	"feature-flags/internal/metrics"
 	"feature-flags/internal/models"
 	"feature-flags/internal/repository/mongodb"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -17,7 +27,7 @@ var (
 	testDBName   = "feature_flags_test"
 )
 
-func setupTestDB(t *testing.T) (*mongo.Database, func()) {
+func setupTestDB(t testing.TB) (*mongo.Database, func()) {
 	ctx := context.Background()
 	client, err := mongo.Connect(ctx, options.Client().ApplyURI(testMongoURI))
 	require.NoError(t, err)
@@ -40,7 +50,8 @@ func setupFeatureService(t *testing.T) (*FeatureService, func()) {
 
 	featureRepo := mongodb.NewFeatureRepository(db)
 	dependencyRepo := mongodb.NewFeatureDependencyRepository(db)
-	service := NewFeatureService(featureRepo, dependencyRepo)
+	changeRequestRepo := mongodb.NewChangeRequestRepository(db)
+	service := NewFeatureService(featureRepo, dependencyRepo, changeRequestRepo, mongodb.NewTenantPlanRepository(db), mongodb.NewSegmentRepository(db), mongodb.NewEventRepository(db), mongodb.NewSignalRepository(db), mongodb.NewAuditRepository(db), mongodb.NewTxManager(db))
 
 	return service, cleanup
 }
@@ -100,6 +111,7 @@ func TestFeatureService_AddChild(t *testing.T) {
 	err = service.AddChild(ctx, parent.ID, child.ID)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "dependency already exists")
+	assert.ErrorIs(t, err, ErrConflict)
 
 	// Test adding self as child (should fail)
 	err = service.AddChild(ctx, parent.ID, parent.ID)
@@ -136,8 +148,14 @@ func TestFeatureService_DisableFeature(t *testing.T) {
 	require.NoError(t, err)
 
 	// Disable parent feature
+	disables := testutil.ToFloat64(metrics.FeatureOperations.WithLabelValues("disable", "applied"))
 	err = service.DisableFeature(ctx, parent.ID)
 	require.NoError(t, err)
+	assert.Equal(t, disables+1, testutil.ToFloat64(metrics.FeatureOperations.WithLabelValues("disable", "applied")))
+
+	// Disabling it again changes nothing, so nothing is counted.
+	require.NoError(t, service.DisableFeature(ctx, parent.ID))
+	assert.Equal(t, disables+1, testutil.ToFloat64(metrics.FeatureOperations.WithLabelValues("disable", "applied")))
 
 	// Verify both features are disabled
 	parentStatus, err := service.GetFeatureStatus(ctx, parent.ID)
@@ -149,6 +167,39 @@ func TestFeatureService_DisableFeature(t *testing.T) {
 	assert.False(t, childStatus.IsEnabled)
 }
 
+func TestFeatureService_PreviewDisable(t *testing.T) {
+	service, cleanup := setupFeatureService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// root -> a -> c and root -> b -> c, with b already disabled
+	root := &models.Feature{Name: "root", Type: models.FeatureTypeBasic, IsEnabled: true}
+	a := &models.Feature{Name: "a", Type: models.FeatureTypeBasic, IsEnabled: true}
+	b := &models.Feature{Name: "b", Type: models.FeatureTypeBasic}
+	c := &models.Feature{Name: "c", Type: models.FeatureTypeBasic, IsEnabled: true}
+	for _, feature := range []*models.Feature{root, a, b, c} {
+		require.NoError(t, service.CreateFeature(ctx, feature))
+	}
+	require.NoError(t, service.AddChild(ctx, root.ID, a.ID))
+	require.NoError(t, service.AddChild(ctx, root.ID, b.ID))
+	require.NoError(t, service.AddChild(ctx, a.ID, c.ID))
+	require.NoError(t, service.AddChild(ctx, b.ID, c.ID))
+
+	cascade, err := service.PreviewDisable(ctx, root.ID)
+	require.NoError(t, err)
+	names := make([]string, len(cascade))
+	for i, feature := range cascade {
+		names[i] = feature.Name
+	}
+	assert.Equal(t, []string{"root", "a", "c"}, names)
+
+	err = service.EnableFeature(ctx, c.ID)
+	var parentDisabled *ParentDisabledError
+	require.ErrorAs(t, err, &parentDisabled)
+	assert.Equal(t, []primitive.ObjectID{b.ID}, parentDisabled.ParentIDs)
+}
+
 func TestFeatureService_EnableFeature(t *testing.T) {
 	service, cleanup := setupFeatureService(t)
 	defer cleanup()
@@ -181,6 +232,9 @@ func TestFeatureService_EnableFeature(t *testing.T) {
 	err = service.EnableFeature(ctx, child.ID)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "parent feature is disabled")
+	var parentDisabled *ParentDisabledError
+	require.ErrorAs(t, err, &parentDisabled)
+	assert.Equal(t, []primitive.ObjectID{parent.ID}, parentDisabled.ParentIDs)
 
 	// Enable parent
 	err = service.EnableFeature(ctx, parent.ID)
@@ -239,7 +293,878 @@ func TestFeatureService_CyclicDependency(t *testing.T) {
 	require.NoError(t, err)
 
 	// This should fail due to cyclic dependency
+	rejections := testutil.ToFloat64(metrics.CycleRejections)
 	err = service.AddChild(ctx, feature3.ID, feature1.ID)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "cyclic dependency detected")
+	var cycle *CycleError
+	require.ErrorAs(t, err, &cycle)
+	assert.Equal(t, []primitive.ObjectID{feature3.ID, feature1.ID, feature2.ID, feature3.ID}, cycle.Path)
+	assert.Equal(t, rejections+1, testutil.ToFloat64(metrics.CycleRejections))
+}
+
+func TestFeatureService_Keys(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, mongodb.EnsureIndexes(ctx, db))
+	service := NewFeatureService(mongodb.NewFeatureRepository(db), mongodb.NewFeatureDependencyRepository(db), mongodb.NewChangeRequestRepository(db), mongodb.NewTenantPlanRepository(db), mongodb.NewSegmentRepository(db), mongodb.NewEventRepository(db), mongodb.NewSignalRepository(db), mongodb.NewAuditRepository(db), mongodb.NewTxManager(db))
+
+	checkout := &models.Feature{Name: "New Checkout", Type: models.FeatureTypeBasic}
+	require.NoError(t, service.CreateFeature(ctx, checkout))
+	assert.Equal(t, "new-checkout", checkout.Key)
+
+	found, err := service.GetFeatureByKey(ctx, "new-checkout")
+	require.NoError(t, err)
+	assert.Equal(t, checkout.ID, found.ID)
+
+	err = service.CreateFeature(ctx, &models.Feature{Name: "new checkout", Type: models.FeatureTypeBasic})
+	assert.ErrorIs(t, err, ErrFeatureExists)
+	assert.ErrorIs(t, err, ErrConflict)
+
+	err = service.CreateFeature(ctx, &models.Feature{Key: "New Checkout", Name: "checkout", Type: models.FeatureTypeBasic})
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	_, err = service.GetFeatureByKey(ctx, "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestFeatureService_ListFeatures(t *testing.T) {
+	service, cleanup := setupFeatureService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	for _, feature := range []*models.Feature{
+		{Name: "search", Type: models.FeatureTypeBasic, IsEnabled: true},
+		{Name: "checkout", Type: models.FeatureTypePremium, IsEnabled: true},
+		{Name: "reports", Type: models.FeatureTypePremium},
+	} {
+		require.NoError(t, service.CreateFeature(ctx, feature))
+	}
+
+	names := func(filter models.FeatureFilter) []string {
+		features, err := service.ListFeatures(ctx, filter)
+		require.NoError(t, err)
+		names := make([]string, len(features))
+		for i, feature := range features {
+			names[i] = feature.Name
+		}
+		return names
+	}
+	enabled, disabled := true, false
+
+	assert.Equal(t, []string{"checkout", "reports", "search"}, names(models.FeatureFilter{}))
+	assert.Equal(t, []string{"checkout", "reports"}, names(models.FeatureFilter{Type: models.FeatureTypePremium}))
+	assert.Equal(t, []string{"checkout", "search"}, names(models.FeatureFilter{Enabled: &enabled}))
+	assert.Equal(t, []string{"reports"}, names(models.FeatureFilter{Type: models.FeatureTypePremium, Enabled: &disabled}))
+}
+
+func TestFeatureService_Metadata(t *testing.T) {
+	service, cleanup := setupFeatureService(t)
+	defer cleanup()
+
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "alice"})
+	checkout := &models.Feature{Name: "checkout", Type: models.FeatureTypeBasic, Metadata: models.Metadata{Tags: []string{"payments"}, Owner: "team-a"}}
+	search := &models.Feature{Name: "search", Type: models.FeatureTypeBasic, Metadata: models.Metadata{Tags: []string{"payments", "q3"}, Owner: "team-b"}}
+	require.NoError(t, service.CreateFeature(ctx, checkout))
+	require.NoError(t, service.CreateFeature(ctx, search))
+	assert.Equal(t, "alice", checkout.CreatedBy)
+	assert.Equal(t, int64(1), checkout.Version)
+
+	err := service.CreateFeature(ctx, &models.Feature{Name: "bad", Type: models.FeatureTypeBasic, Metadata: models.Metadata{Tags: []string{"two words"}}})
+	assert.ErrorIs(t, err, ErrInvalidMetadata)
+
+	// Metadata edits leave the version alone; evaluation changes bump it.
+	updated, err := service.SetMetadata(ctx, checkout.ID, models.Metadata{Description: "New checkout", Tags: []string{"payments", "q3"}, Owner: "team-a"})
+	require.NoError(t, err)
+	assert.Equal(t, "New checkout", updated.Description)
+	assert.Equal(t, int64(1), updated.Version)
+
+	require.NoError(t, service.EnableFeature(ctx, checkout.ID))
+	enabled, err := service.GetFeatureStatus(ctx, checkout.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), enabled.Version)
+	assert.Equal(t, "New checkout", enabled.Description)
+
+	_, err = service.SetMetadata(ctx, checkout.ID, models.Metadata{Links: []models.Link{{URL: "ftp://example.com"}}})
+	assert.ErrorIs(t, err, ErrInvalidMetadata)
+	_, err = service.SetMetadata(ctx, primitive.NewObjectID(), models.Metadata{})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	names := func(filter models.FeatureFilter) []string {
+		features, err := service.ListFeatures(ctx, filter)
+		require.NoError(t, err)
+		names := make([]string, len(features))
+		for i, feature := range features {
+			names[i] = feature.Name
+		}
+		return names
+	}
+	assert.Equal(t, []string{"checkout", "search"}, names(models.FeatureFilter{Tags: []string{"payments", "q3"}}))
+	assert.Equal(t, []string{"search"}, names(models.FeatureFilter{Owner: "team-b"}))
+	assert.Empty(t, names(models.FeatureFilter{Tags: []string{"q4"}}))
+}
+
+func TestFeatureService_Entitlements(t *testing.T) {
+	service, cleanup := setupFeatureService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	search := &models.Feature{Name: "search", Type: models.FeatureTypeBasic, IsEnabled: true}
+	reports := &models.Feature{Name: "reports", Type: models.FeatureTypePremium, IsEnabled: true}
+	audit := &models.Feature{Name: "audit", Type: models.FeatureTypeEnterprise}
+	for _, feature := range []*models.Feature{search, reports, audit} {
+		require.NoError(t, service.CreateFeature(ctx, feature))
+	}
+	err := service.CreateFeature(ctx, &models.Feature{Name: "gold", Type: "gold"})
+	assert.ErrorIs(t, err, ErrInvalidFeatureType)
+
+	names := func(e *models.Entitlements) []string {
+		names := make([]string, len(e.Features))
+		for i, feature := range e.Features {
+			names[i] = feature.Name
+		}
+		return names
+	}
+	entitlements, err := service.Entitlements(ctx, "acme", "premium")
+	require.NoError(t, err)
+	assert.False(t, entitlements.Override)
+	assert.Equal(t, []string{"reports", "search"}, names(entitlements))
+	_, err = service.Entitlements(ctx, "acme", "")
+	assert.ErrorIs(t, err, ErrInvalidPlan)
+	_, err = service.Entitlements(ctx, "acme", "gold")
+	assert.ErrorIs(t, err, ErrInvalidPlan)
+
+	on := func(tenant, plan string) bool {
+		result, err := service.Evaluate(ctx, "reports", evaluation.Context{"tenantId": tenant, "plan": plan})
+		require.NoError(t, err)
+		return result.On()
+	}
+	assert.False(t, on("acme", "basic"))
+
+	// An override takes precedence over the plan in the request or context.
+	assert.ErrorIs(t, service.SetTenantPlan(ctx, &models.TenantPlan{TenantID: "acme", Plan: "gold"}), ErrInvalidPlan)
+	require.NoError(t, service.SetTenantPlan(ctx, &models.TenantPlan{TenantID: "acme", Plan: "enterprise", Reason: "trial"}))
+	entitlements, err = service.Entitlements(ctx, "acme", "basic")
+	require.NoError(t, err)
+	assert.True(t, entitlements.Override)
+	assert.Equal(t, "enterprise", entitlements.Plan)
+	assert.Equal(t, []string{"audit", "reports", "search"}, names(entitlements))
+	assert.True(t, on("acme", "basic"))
+	assert.False(t, on("globex", "basic"))
+
+	plans, err := service.ListTenantPlans(ctx)
+	require.NoError(t, err)
+	require.Len(t, plans, 1)
+	assert.Equal(t, "trial", plans[0].Reason)
+
+	require.NoError(t, service.DeleteTenantPlan(ctx, "acme"))
+	assert.ErrorIs(t, service.DeleteTenantPlan(ctx, "acme"), ErrNotFound)
+	_, err = service.GetTenantPlan(ctx, "acme")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.False(t, on("acme", "basic"))
+}
+
+func TestFeatureService_Overrides(t *testing.T) {
+	service, cleanup := setupFeatureService(t)
+	defer cleanup()
+
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "alice"})
+	beta := &models.Feature{Name: "beta", Type: models.FeatureTypeBasic, Targeting: models.Targeting{
+		Variants:       []models.Variant{{Name: "grid", Value: "grid"}, {Name: "list", Value: "list"}},
+		DefaultVariant: "list",
+	}}
+	require.NoError(t, service.CreateFeature(ctx, beta))
+
+	updated, err := service.AddOverrides(ctx, beta.ID, []models.Override{
+		{Attribute: "tenantId", Value: "acme", Action: models.OverrideActionAllow, Variant: "grid"},
+		{Attribute: "tenantId", Value: "globex", Action: models.OverrideActionDeny},
+	})
+	require.NoError(t, err)
+	require.Len(t, updated.Overrides, 2)
+	assert.Equal(t, "alice", updated.Overrides[0].CreatedBy)
+	assert.Equal(t, int64(2), updated.Version)
+
+	result, err := service.Evaluate(ctx, "beta", evaluation.Context{"tenantId": "acme"})
+	require.NoError(t, err)
+	assert.Equal(t, "grid", result.Value)
+	assert.Equal(t, evaluation.ReasonOverride, result.Reason)
+
+	// Targeting must keep the variants overrides serve.
+	_, err = service.SetTargeting(ctx, beta.ID, models.Targeting{})
+	assert.ErrorIs(t, err, ErrInvalidTargeting)
+
+	// Adding an override with the same key replaces it.
+	updated, err = service.AddOverrides(ctx, beta.ID, []models.Override{{Attribute: "tenantId", Value: "acme", Action: models.OverrideActionDeny}})
+	require.NoError(t, err)
+	require.Len(t, updated.Overrides, 2)
+	assert.Equal(t, models.OverrideActionDeny, updated.Overrides[1].Action)
+
+	_, err = service.AddOverrides(ctx, beta.ID, []models.Override{{Attribute: "tenantId", Value: "x", Action: models.OverrideActionAllow, Variant: "cards"}})
+	assert.ErrorIs(t, err, ErrInvalidOverride)
+
+	tooMany := make([]models.Override, models.MaxOverrides-1)
+	for i := range tooMany {
+		tooMany[i] = models.Override{Attribute: "userId", Value: strconv.Itoa(i), Action: models.OverrideActionAllow}
+	}
+	_, err = service.AddOverrides(ctx, beta.ID, tooMany)
+	var limit *OverrideLimitError
+	require.ErrorAs(t, err, &limit)
+	assert.Equal(t, models.MaxOverrides+1, limit.Count)
+
+	// Removing the last override clears them, and unknown keys are ignored.
+	updated, err = service.RemoveOverrides(ctx, beta.ID, []models.OverrideKey{
+		{Attribute: "tenantId", Value: "acme"}, {Attribute: "tenantId", Value: "globex"}, {Attribute: "tenantId", Value: "hooli"},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, updated.Overrides)
+	stored, err := service.GetFeatureStatus(ctx, beta.ID)
+	require.NoError(t, err)
+	assert.Empty(t, stored.Overrides)
+
+	// Overrides on protected features need approval.
+	_, err = service.SetProtected(ctx, beta.ID, true)
+	require.NoError(t, err)
+	_, err = service.AddOverrides(ctx, beta.ID, []models.Override{{Attribute: "tenantId", Value: "acme", Action: models.OverrideActionAllow}})
+	var pending *PendingChangeError
+	require.ErrorAs(t, err, &pending)
+	assert.Equal(t, models.ChangeRequestActionAddOverrides, pending.ChangeRequest.Action)
+
+	approver := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "bob"})
+	_, err = service.ApproveChangeRequest(approver, pending.ChangeRequest.ID, "")
+	require.NoError(t, err)
+	stored, err = service.GetFeatureStatus(ctx, beta.ID)
+	require.NoError(t, err)
+	require.Len(t, stored.Overrides, 1)
+	assert.Equal(t, "alice", stored.Overrides[0].CreatedBy)
+}
+
+// Writes from a stale copy of a feature only store the fields they own, so
+// they do not undo a disable made in the meantime.
+func TestFeatureService_WritesKeepOtherFields(t *testing.T) {
+	service, cleanup := setupFeatureService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	banner := &models.Feature{Name: "banner", Type: models.FeatureTypeBasic, IsEnabled: true, Targeting: models.Targeting{
+		Variants:       []models.Variant{{Name: "a", Value: "A"}},
+		DefaultVariant: "a",
+	}}
+	require.NoError(t, service.CreateFeature(ctx, banner))
+	stale, err := service.GetFeatureStatus(ctx, banner.ID)
+	require.NoError(t, err)
+	require.NoError(t, service.DisableFeature(ctx, banner.ID))
+
+	stale.Targeting = models.Targeting{}
+	require.NoError(t, service.featureRepo.SetTargeting(ctx, stale))
+	stale.Protected = true
+	require.NoError(t, service.featureRepo.SetProtected(ctx, stale))
+
+	stored, err := service.GetFeatureStatus(ctx, banner.ID)
+	require.NoError(t, err)
+	assert.False(t, stored.IsEnabled)
+	assert.True(t, stored.Protected)
+	assert.Empty(t, stored.Variants)
+	assert.Empty(t, stored.DefaultVariant)
+	assert.Equal(t, banner.Version+2, stored.Version)
+}
+
+// Override writes from a stale copy of a feature are rejected rather than
+// dropping overrides added in the meantime.
+func TestFeatureService_OverridesStaleWrite(t *testing.T) {
+	service, cleanup := setupFeatureService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	beta := &models.Feature{Name: "beta", Type: models.FeatureTypeBasic, IsEnabled: true}
+	require.NoError(t, service.CreateFeature(ctx, beta))
+	stale, err := service.GetFeatureStatus(ctx, beta.ID)
+	require.NoError(t, err)
+	_, err = service.AddOverrides(ctx, beta.ID, []models.Override{{Attribute: "tenantId", Value: "acme", Action: models.OverrideActionAllow}})
+	require.NoError(t, err)
+
+	_, err = service.applyOverrides(ctx, stale, []models.Override{{Attribute: "tenantId", Value: "globex", Action: models.OverrideActionDeny}})
+	assert.ErrorIs(t, err, ErrConcurrentChange)
+	assert.ErrorIs(t, err, ErrConflict)
+
+	stored, err := service.GetFeatureStatus(ctx, beta.ID)
+	require.NoError(t, err)
+	require.Len(t, stored.Overrides, 1)
+	assert.Equal(t, "acme", stored.Overrides[0].Value)
+	assert.Equal(t, beta.Version+1, stored.Version)
+}
+
+func TestFeatureService_Segments(t *testing.T) {
+	service, cleanup := setupFeatureService(t)
+	defer cleanup()
+
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "alice"})
+	staff := &models.Segment{
+		Key:   "internal-employees",
+		Rules: []models.TargetingRule{{Attribute: "email_domain", Operator: models.RuleOperatorIn, Values: []string{"example.com"}}},
+	}
+	require.NoError(t, service.CreateSegment(ctx, staff))
+	assert.Equal(t, "internal-employees", staff.Name)
+	assert.Equal(t, int64(1), staff.Version)
+	assert.ErrorIs(t, service.CreateSegment(ctx, &models.Segment{Key: "internal-employees"}), ErrSegmentExists)
+	assert.ErrorIs(t, service.CreateSegment(ctx, &models.Segment{Key: "Staff"}), ErrInvalidKey)
+
+	inStaff := models.Targeting{Rules: []models.TargetingRule{{Operator: models.RuleOperatorInSegment, Values: []string{"internal-employees"}}}}
+	dashboard := &models.Feature{Name: "dashboard", Type: models.FeatureTypeBasic, IsEnabled: true, Targeting: inStaff}
+	require.NoError(t, service.CreateFeature(ctx, dashboard))
+	reports := &models.Feature{Name: "reports", Type: models.FeatureTypeBasic, IsEnabled: true, Targeting: inStaff}
+	require.NoError(t, service.CreateFeature(ctx, reports))
+	search := &models.Feature{Name: "search", Type: models.FeatureTypeBasic, IsEnabled: true}
+	require.NoError(t, service.CreateFeature(ctx, search))
+
+	// Rules may only reference existing segments.
+	_, err := service.SetTargeting(ctx, search.ID, models.Targeting{
+		Rules: []models.TargetingRule{{Operator: models.RuleOperatorNotInSegment, Values: []string{"churned"}}},
+	})
+	assert.ErrorIs(t, err, ErrInvalidTargeting)
+
+	result, err := service.Evaluate(ctx, "dashboard", evaluation.Context{"targetingKey": "dev-1", "email_domain": "example.com"})
+	require.NoError(t, err)
+	assert.True(t, result.On())
+
+	// An edit applies to every feature using the segment.
+	updated, err := service.UpdateSegment(ctx, "internal-employees", &models.Segment{Name: "Staff", Included: []string{"contractor-1"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), updated.Version)
+	assert.Empty(t, updated.Rules)
+	for _, key := range []string{"dashboard", "reports"} {
+		result, err = service.Evaluate(ctx, key, evaluation.Context{"targetingKey": "dev-1", "email_domain": "example.com"})
+		require.NoError(t, err)
+		assert.False(t, result.On(), key)
+		result, err = service.Evaluate(ctx, key, evaluation.Context{"targetingKey": "contractor-1"})
+		require.NoError(t, err)
+		assert.True(t, result.On(), key)
+	}
+
+	// A replace from a stale read does not undo the edit.
+	stale := *staff
+	stale.Version++
+	replaced, err := service.segmentRepo.Update(ctx, &stale)
+	require.NoError(t, err)
+	assert.False(t, replaced)
+	stored, err := service.GetSegment(ctx, "internal-employees")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"contractor-1"}, stored.Included)
+
+	used, err := service.SegmentUsage(ctx, "internal-employees")
+	require.NoError(t, err)
+	require.Len(t, used, 2)
+	assert.Equal(t, "dashboard", used[0].Name)
+	assert.Equal(t, "reports", used[1].Name)
+	_, err = service.SegmentUsage(ctx, "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Segments in use cannot be deleted.
+	err = service.DeleteSegment(ctx, "internal-employees")
+	var inUse *SegmentInUseError
+	require.ErrorAs(t, err, &inUse)
+	assert.Equal(t, []string{"dashboard", "reports"}, inUse.Features)
+
+	for _, feature := range []*models.Feature{dashboard, reports} {
+		_, err = service.SetTargeting(ctx, feature.ID, models.Targeting{})
+		require.NoError(t, err)
+	}
+	require.NoError(t, service.DeleteSegment(ctx, "internal-employees"))
+	assert.ErrorIs(t, service.DeleteSegment(ctx, "internal-employees"), ErrNotFound)
+}
+
+func TestFeatureService_Experiments(t *testing.T) {
+	service, cleanup := setupFeatureService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	banner := &models.Feature{Name: "banner", Type: models.FeatureTypeBasic, IsEnabled: true, Targeting: models.Targeting{
+		Variants: []models.Variant{{Name: "control", Value: "old", Weight: 50}, {Name: "treatment", Value: "new", Weight: 50}},
+	}}
+	require.NoError(t, service.CreateFeature(ctx, banner))
+
+	recorder := events.NewRecorder(events.Config{FlushInterval: 10 * time.Millisecond}, service.eventRepo)
+	service.SetExposureRecorder(recorder)
+	recordCtx, stop := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		recorder.Run(recordCtx)
+	}()
+
+	variants := make(map[string]string)
+	for i := 0; i < 100; i++ {
+		key := "user-" + strconv.Itoa(i)
+		result, err := service.Evaluate(ctx, "banner", evaluation.Context{"targetingKey": key})
+		require.NoError(t, err)
+		variants[key] = result.Variant
+	}
+	// Evaluations without a targeting key, or of unknown flags, are not
+	// exposures.
+	_, err := service.Evaluate(ctx, "banner", evaluation.Context{})
+	require.NoError(t, err)
+	_, err = service.Evaluate(ctx, "missing", evaluation.Context{"targetingKey": "user-0"})
+	require.NoError(t, err)
+	stop()
+	<-done
+	assert.Equal(t, int64(100), recorder.Status().Written)
+
+	var conversions []models.Conversion
+	converted := map[string]int{}
+	for i := 0; i < 100; i += 4 {
+		key := "user-" + strconv.Itoa(i)
+		conversions = append(conversions, models.Conversion{Event: "click", TargetingKey: key})
+		converted[variants[key]]++
+	}
+	require.NoError(t, service.TrackConversions(ctx, conversions))
+	assert.ErrorIs(t, service.TrackConversions(ctx, []models.Conversion{{Event: "click"}}), ErrInvalidEvent)
+	assert.ErrorIs(t, service.TrackConversions(ctx, nil), ErrInvalidEvent)
+	future := []models.Conversion{{Event: "click", TargetingKey: "user-1", Timestamp: time.Now().Add(time.Hour)}}
+	assert.ErrorIs(t, service.TrackConversions(ctx, future), ErrInvalidEvent)
+
+	results, err := service.ExperimentResults(ctx, "banner", "click", ExperimentOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "control", results.Control)
+	assert.Equal(t, DefaultConfidence, results.Confidence)
+	require.Len(t, results.Variants, 2)
+	exposed := 0
+	for _, variant := range results.Variants {
+		exposed += variant.Exposed
+		assert.Equal(t, converted[variant.Variant], variant.Converted, variant.Variant)
+	}
+	assert.Equal(t, 100, exposed)
+	assert.Nil(t, results.Variants[0].Difference)
+	assert.NotNil(t, results.Variants[1].Difference)
+
+	_, err = service.ExperimentResults(ctx, "banner", "", ExperimentOptions{})
+	assert.ErrorIs(t, err, ErrInvalidExperiment)
+	_, err = service.ExperimentResults(ctx, "banner", "click", ExperimentOptions{Control: "missing"})
+	assert.ErrorIs(t, err, ErrInvalidExperiment)
+	_, err = service.ExperimentResults(ctx, "missing", "click", ExperimentOptions{})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	count := 0
+	require.NoError(t, service.ExportConversions(ctx, models.EventFilter{Event: "click"}, func(*models.Conversion) error {
+		count++
+		return nil
+	}))
+	assert.Equal(t, len(conversions), count)
+}
+
+func TestFeatureService_KillSwitch(t *testing.T) {
+	service, cleanup := setupFeatureService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	checkout := &models.Feature{Name: "checkout", Type: models.FeatureTypeBasic, IsEnabled: true}
+	require.NoError(t, service.CreateFeature(ctx, checkout))
+	express := &models.Feature{Name: "express-checkout", Type: models.FeatureTypeBasic, IsEnabled: true}
+	require.NoError(t, service.CreateFeature(ctx, express))
+	require.NoError(t, service.AddChild(ctx, checkout.ID, express.ID))
+	search := &models.Feature{Name: "search", Type: models.FeatureTypeBasic, IsEnabled: true, Protected: true}
+	require.NoError(t, service.CreateFeature(ctx, search))
+
+	_, err := service.SetGuard(ctx, checkout.ID, &models.GuardRule{Metric: "latency", Threshold: 1, Window: "5m"})
+	assert.ErrorIs(t, err, ErrInvalidGuard)
+	_, err = service.SetGuard(ctx, checkout.ID, &models.GuardRule{Metric: models.GuardMetricErrorRate, Threshold: 0.1, Window: "5m", MinRequests: 10})
+	require.NoError(t, err)
+	_, err = service.SetGuard(ctx, search.ID, &models.GuardRule{Metric: models.GuardMetricErrorCount, Threshold: 5, Window: "5m"})
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, service.ReportSignals(ctx, []models.Signal{{FlagKey: "checkout"}}), ErrInvalidSignal)
+	assert.ErrorIs(t, service.ReportSignals(ctx, nil), ErrInvalidSignal)
+	future := []models.Signal{{FlagKey: "checkout", Errors: 1, Timestamp: time.Now().Add(time.Hour)}}
+	assert.ErrorIs(t, service.ReportSignals(ctx, future), ErrInvalidSignal)
+
+	// Below the thresholds nothing trips.
+	require.NoError(t, service.ReportSignals(ctx, []models.Signal{
+		{FlagKey: "checkout", Errors: 1, Successes: 99},
+		{FlagKey: "search", Errors: 4},
+	}))
+	trips, err := service.CheckGuards(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, trips)
+
+	disabled := testutil.ToFloat64(metrics.KillSwitchTrips.WithLabelValues("disabled"))
+	require.NoError(t, service.ReportSignals(ctx, []models.Signal{
+		{FlagKey: "checkout", Errors: 30, Successes: 70},
+		{FlagKey: "search", Errors: 1},
+	}))
+	trips, err = service.CheckGuards(ctx)
+	require.NoError(t, err)
+	require.Len(t, trips, 2)
+	assert.Equal(t, disabled+1, testutil.ToFloat64(metrics.KillSwitchTrips.WithLabelValues("disabled")))
+
+	// The unprotected feature is disabled with its cascade.
+	trip := trips[0]
+	assert.Equal(t, "checkout", trip.FeatureKey)
+	assert.Equal(t, KillSwitchActor, trip.Actor)
+	assert.Contains(t, trip.Reason, "error_rate 0.155 reached threshold 0.1")
+	assert.Equal(t, []string{"checkout", "express-checkout"}, trip.Disabled)
+	childStatus, err := service.GetFeatureStatus(ctx, express.ID)
+	require.NoError(t, err)
+	assert.False(t, childStatus.IsEnabled)
+
+	// The protected one gets a change request instead.
+	trip = trips[1]
+	assert.Equal(t, "search", trip.FeatureKey)
+	require.NotNil(t, trip.ChangeRequestID)
+	request, err := service.GetChangeRequest(ctx, *trip.ChangeRequestID)
+	require.NoError(t, err)
+	assert.Equal(t, KillSwitchActor, request.RequestedBy)
+	assert.Empty(t, trip.Disabled)
+	assert.Equal(t, []string{"search"}, trip.Pending)
+	searchStatus, err := service.GetFeatureStatus(ctx, search.ID)
+	require.NoError(t, err)
+	assert.True(t, searchStatus.IsEnabled)
+
+	// Releasing a claim puts back when the guard last tripped.
+	stored, err := service.getFeature(ctx, search.ID)
+	require.NoError(t, err)
+	previous := stored.Guard.TrippedAt
+	require.NotNil(t, previous)
+	claimedAt := time.Now().Add(time.Minute)
+	claimed, err := service.featureRepo.ClaimGuardTrip(ctx, search.ID, previous, claimedAt)
+	require.NoError(t, err)
+	require.True(t, claimed)
+	require.NoError(t, service.featureRepo.ReleaseGuardTrip(ctx, search.ID, claimedAt, previous))
+	stored, err = service.getFeature(ctx, search.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.Guard.TrippedAt)
+	assert.True(t, previous.Equal(*stored.Guard.TrippedAt))
+
+	// Signals counted by a trip are not counted again.
+	trips, err = service.CheckGuards(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, trips)
+
+	// No further request is filed while the first one is pending.
+	require.NoError(t, service.ReportSignals(ctx, []models.Signal{{FlagKey: "search", Errors: 10}}))
+	trips, err = service.CheckGuards(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, trips)
+	requests, err := service.ListChangeRequests(ctx, models.ChangeRequestStatusPending)
+	require.NoError(t, err)
+	assert.Len(t, requests, 1)
+
+	entries, err := service.ListAuditLog(ctx, models.AuditFilter{FeatureKey: "checkout"})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, models.AuditActionKillSwitch, entries[0].Action)
+
+	feature, err := service.SetGuard(ctx, checkout.ID, nil)
+	require.NoError(t, err)
+	assert.Nil(t, feature.Guard)
+	guarded, err := service.ListFeatures(ctx, models.FeatureFilter{Guarded: true})
+	require.NoError(t, err)
+	require.Len(t, guarded, 1)
+	assert.Equal(t, "search", guarded[0].Key)
+}
+
+// A trip whose cascade reaches a GitOps-managed feature fails once and
+// keeps its claim, since retrying cannot disable the managed feature.
+func TestFeatureService_KillSwitchManaged(t *testing.T) {
+	service, cleanup := setupFeatureService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	payments := &models.Feature{Name: "payments", Type: models.FeatureTypeBasic, IsEnabled: true}
+	require.NoError(t, service.CreateFeature(ctx, payments))
+	refunds := &models.Feature{Name: "refunds", Type: models.FeatureTypeBasic, IsEnabled: true}
+	require.NoError(t, service.CreateFeature(ctx, refunds))
+	require.NoError(t, service.AddChild(ctx, payments.ID, refunds.ID))
+	_, err := service.SetGuard(ctx, payments.ID, &models.GuardRule{Metric: models.GuardMetricErrorCount, Threshold: 5, Window: "5m"})
+	require.NoError(t, err)
+	service.SetManagedFeatures([]string{refunds.FlagKey()})
+
+	require.NoError(t, service.ReportSignals(ctx, []models.Signal{{FlagKey: "payments", Errors: 5}}))
+	trips, err := service.CheckGuards(ctx)
+	require.NoError(t, err)
+	require.Len(t, trips, 1)
+	assert.Contains(t, trips[0].Error, "managed")
+
+	stored, err := service.getFeature(ctx, payments.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.IsEnabled)
+	assert.NotNil(t, stored.Guard.TrippedAt)
+	trips, err = service.CheckGuards(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, trips)
+}
+
+func TestFeatureService_Rollout(t *testing.T) {
+	service, cleanup := setupFeatureService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	search := &models.Feature{Name: "search", Type: models.FeatureTypeBasic, IsEnabled: true}
+	require.NoError(t, service.CreateFeature(ctx, search))
+	suggest := &models.Feature{Name: "suggest", Type: models.FeatureTypeBasic, IsEnabled: true}
+	require.NoError(t, service.CreateFeature(ctx, suggest))
+	require.NoError(t, service.AddChild(ctx, search.ID, suggest.ID))
+	billing := &models.Feature{Name: "billing", Type: models.FeatureTypeBasic, IsEnabled: true, Protected: true}
+	require.NoError(t, service.CreateFeature(ctx, billing))
+
+	_, err := service.SetRollout(ctx, search.ID, &models.RolloutPlan{Steps: []models.RolloutStep{{Percentage: 100}}})
+	assert.ErrorIs(t, err, ErrInvalidRollout)
+	_, err = service.PauseRollout(ctx, search.ID)
+	assert.ErrorIs(t, err, ErrRolloutState)
+
+	steps := []models.RolloutStep{{Percentage: 5, Hold: "1ms"}, {Percentage: 25, Hold: "1ms"}, {Percentage: 100}}
+	feature, err := service.SetRollout(ctx, search.ID, &models.RolloutPlan{Steps: steps, Status: models.RolloutStatusCompleted, Step: 2})
+	require.NoError(t, err)
+	assert.Equal(t, models.RolloutStatusActive, feature.Rollout.Status)
+	assert.Equal(t, 0, feature.Rollout.Step)
+
+	// A plan set from a stale read does not overwrite a change made since.
+	stale, err := service.getFeature(ctx, search.ID)
+	require.NoError(t, err)
+	_, err = service.PauseRollout(ctx, search.ID)
+	require.NoError(t, err)
+	_, err = service.applyRollout(ctx, stale, nil)
+	assert.ErrorIs(t, err, ErrConcurrentChange)
+	_, err = service.ResumeRollout(ctx, search.ID)
+	require.NoError(t, err)
+
+	// Protected features need approval to start a plan.
+	_, err = service.SetRollout(ctx, billing.ID, &models.RolloutPlan{Steps: steps})
+	var pending *PendingChangeError
+	require.ErrorAs(t, err, &pending)
+	assert.Equal(t, models.ChangeRequestActionSetRollout, pending.ChangeRequest.Action)
+
+	// Paused plans do not advance.
+	_, err = service.PauseRollout(ctx, search.ID)
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+	advanced, err := service.AdvanceRollouts(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, advanced)
+	_, err = service.PauseRollout(ctx, search.ID)
+	assert.ErrorIs(t, err, ErrRolloutState)
+
+	_, err = service.ResumeRollout(ctx, search.ID)
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+	advanced, err = service.AdvanceRollouts(ctx)
+	require.NoError(t, err)
+	require.Len(t, advanced, 1)
+	assert.Equal(t, RolloutActor, advanced[0].Actor)
+	assert.Equal(t, "advanced to step 2 of 3 (25%)", advanced[0].Reason)
+
+	feature, err = service.GetFeatureStatus(ctx, search.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 25.0, feature.Rollout.Percentage())
+
+	// Aborting rolls back to 0% and disables the cascade.
+	feature, err = service.AbortRollout(ctx, search.ID)
+	require.NoError(t, err)
+	assert.False(t, feature.IsEnabled)
+	assert.Equal(t, models.RolloutStatusAborted, feature.Rollout.Status)
+	child, err := service.GetFeatureStatus(ctx, suggest.ID)
+	require.NoError(t, err)
+	assert.False(t, child.IsEnabled)
+
+	entries, err := service.ListAuditLog(ctx, models.AuditFilter{FeatureKey: "search", Action: models.AuditActionRolloutAbort})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, []string{"search", "suggest"}, entries[0].Disabled)
+
+	_, err = service.AbortRollout(ctx, search.ID)
+	assert.ErrorIs(t, err, ErrRolloutState)
+	feature, err = service.SetRollout(ctx, search.ID, nil)
+	require.NoError(t, err)
+	assert.Nil(t, feature.Rollout)
+}
+
+func TestBootstrap_BackfillsKeys(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	_, err := db.Collection("features").InsertMany(ctx, []interface{}{
+		bson.M{"name": "search", "type": "basic"},
+		bson.M{"name": "New Checkout", "type": "basic"},
+		bson.M{"name": "new checkout", "type": "basic"},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, mongodb.Bootstrap(ctx, db))
+	require.NoError(t, mongodb.CheckIndexes(ctx, db))
+	require.NoError(t, mongodb.CheckMigrations(ctx, db))
+	// Applied migrations are not run again.
+	require.NoError(t, mongodb.Bootstrap(ctx, db))
+
+	service := NewFeatureService(mongodb.NewFeatureRepository(db), mongodb.NewFeatureDependencyRepository(db), mongodb.NewChangeRequestRepository(db), mongodb.NewTenantPlanRepository(db), mongodb.NewSegmentRepository(db), mongodb.NewEventRepository(db), mongodb.NewSignalRepository(db), mongodb.NewAuditRepository(db), mongodb.NewTxManager(db))
+	features, err := service.ListFeatures(ctx, models.FeatureFilter{})
+	require.NoError(t, err)
+	require.Len(t, features, 3)
+
+	keys := make(map[string]string, len(features))
+	for _, feature := range features {
+		keys[feature.Name] = feature.Key
+	}
+	assert.Equal(t, "search", keys["search"])
+	assert.Equal(t, "new-checkout", keys["New Checkout"])
+	assert.True(t, strings.HasPrefix(keys["new checkout"], "new-checkout-"))
+
+	found, err := service.GetFeatureByKey(ctx, "new-checkout")
+	require.NoError(t, err)
+	assert.Equal(t, "New Checkout", found.Name)
+}
+
+func TestDetectCycle(t *testing.T) {
+	ctx := context.Background()
+	a, b, c, d := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	graph := map[primitive.ObjectID][]primitive.ObjectID{a: {b, d}, b: {c}}
+	children := func(_ context.Context, id primitive.ObjectID) ([]primitive.ObjectID, error) {
+		return graph[id], nil
+	}
+
+	var cycle *CycleError
+	require.ErrorAs(t, detectCycle(ctx, c, a, children), &cycle)
+	assert.Equal(t, []primitive.ObjectID{c, a, b, c}, cycle.Path)
+	assert.ErrorIs(t, cycle, ErrCycle)
+
+	require.ErrorAs(t, detectCycle(ctx, a, a, children), &cycle)
+	assert.Equal(t, []primitive.ObjectID{a, a}, cycle.Path)
+
+	assert.NoError(t, detectCycle(ctx, d, c, children))
+}
+
+func TestFeatureService_NotFound(t *testing.T) {
+	service, cleanup := setupFeatureService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	missing := primitive.NewObjectID()
+
+	err := service.EnableFeature(ctx, missing)
+	assert.ErrorIs(t, err, ErrNotFound)
+	var notFound *NotFoundError
+	require.ErrorAs(t, err, &notFound)
+	assert.Equal(t, missing.Hex(), notFound.ID)
+
+	_, err = service.GetFeatureStatus(ctx, missing)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	feature := &models.Feature{Name: "feature", Type: models.FeatureTypeBasic}
+	require.NoError(t, service.CreateFeature(ctx, feature))
+	assert.ErrorIs(t, service.RemoveChild(ctx, feature.ID, feature.ID), ErrNotFound)
+
+	assert.ErrorIs(t, service.AddChild(ctx, feature.ID, missing), ErrNotFound)
+}
+
+func TestFeatureService_ProtectedFeatureChangeRequest(t *testing.T) {
+	service, cleanup := setupFeatureService(t)
+	defer cleanup()
+
+	requester := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "alice"})
+	approver := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "bob"})
+
+	// Create protected parent feature
+	parent := &models.Feature{
+		Name:      "parent-feature",
+		Type:      models.FeatureTypeBasic,
+		IsEnabled: true,
+		Protected: true,
+	}
+	err := service.CreateFeature(requester, parent)
+	require.NoError(t, err)
+
+	// Create child feature
+	child := &models.Feature{
+		Name:      "child-feature",
+		Type:      models.FeatureTypeBasic,
+		IsEnabled: true,
+	}
+	err = service.CreateFeature(requester, child)
+	require.NoError(t, err)
+
+	// Adding a dependency to a protected feature needs approval
+	err = service.AddChild(requester, parent.ID, child.ID)
+	var pending *PendingChangeError
+	require.ErrorAs(t, err, &pending)
+	_, err = service.ApproveChangeRequest(approver, pending.ChangeRequest.ID, "")
+	require.NoError(t, err)
+
+	// Disabling the protected parent creates a change request with the cascade
+	err = service.DisableFeature(requester, parent.ID)
+	require.ErrorAs(t, err, &pending)
+	request := pending.ChangeRequest
+	assert.Equal(t, models.ChangeRequestStatusPending, request.Status)
+	assert.Equal(t, "alice", request.RequestedBy)
+	require.Len(t, request.Diff.Features, 2)
+	assert.Equal(t, parent.ID, request.Diff.Features[0].FeatureID)
+	assert.Equal(t, child.ID, request.Diff.Features[1].FeatureID)
+
+	// Nothing is applied yet
+	parentStatus, err := service.GetFeatureStatus(requester, parent.ID)
+	require.NoError(t, err)
+	assert.True(t, parentStatus.IsEnabled)
+
+	// Self-approval is blocked
+	_, err = service.ApproveChangeRequest(requester, request.ID, "lgtm")
+	assert.ErrorIs(t, err, ErrSelfApproval)
+
+	// Approval applies the disable and its cascade
+	approved, err := service.ApproveChangeRequest(approver, request.ID, "lgtm")
+	require.NoError(t, err)
+	assert.Equal(t, models.ChangeRequestStatusApproved, approved.Status)
+	assert.Equal(t, "bob", approved.ReviewedBy)
+
+	childStatus, err := service.GetFeatureStatus(requester, child.ID)
+	require.NoError(t, err)
+	assert.False(t, childStatus.IsEnabled)
+
+	// A resolved request cannot be reviewed again
+	_, err = service.RejectChangeRequest(approver, request.ID, "")
+	assert.ErrorIs(t, err, ErrChangeRequestClosed)
+}
+
+func TestFeatureService_ApproveChangeRequestFailedApply(t *testing.T) {
+	service, cleanup := setupFeatureService(t)
+	defer cleanup()
+
+	requester := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "alice"})
+	approver := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "bob"})
+
+	feature := &models.Feature{Name: "billing", Type: models.FeatureTypeBasic, IsEnabled: true, Protected: true}
+	require.NoError(t, service.CreateFeature(requester, feature))
+	err := service.DisableFeature(requester, feature.ID)
+	var pending *PendingChangeError
+	require.ErrorAs(t, err, &pending)
+
+	// The feature is gone by approval time, so the change cannot apply.
+	require.NoError(t, service.featureRepo.Delete(requester, feature.ID))
+	_, err = service.ApproveChangeRequest(approver, pending.ChangeRequest.ID, "")
+	require.Error(t, err)
+
+	// With or without a transaction the request is still pending.
+	request, err := service.GetChangeRequest(requester, pending.ChangeRequest.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ChangeRequestStatusPending, request.Status)
+	assert.Empty(t, request.ReviewedBy)
+}
+
+func TestFeatureService_ApprovalsDisabled(t *testing.T) {
+	service, cleanup := setupFeatureService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	protected := &models.Feature{Name: "billing", Type: models.FeatureTypeBasic, Protected: true}
+	require.NoError(t, service.CreateFeature(ctx, protected))
+
+	service.DisableApprovals()
+	err := service.CreateFeature(ctx, &models.Feature{Name: "payments", Type: models.FeatureTypeBasic, Protected: true})
+	assert.ErrorIs(t, err, ErrApprovalsDisabled)
+	feature := &models.Feature{Name: "search", Type: models.FeatureTypeBasic}
+	require.NoError(t, service.CreateFeature(ctx, feature))
+	_, err = service.SetProtected(ctx, feature.ID, true)
+	assert.ErrorIs(t, err, ErrApprovalsDisabled)
+
+	// Features protected before can still be unprotected.
+	unprotected, err := service.SetProtected(ctx, protected.ID, false)
+	require.NoError(t, err)
+	assert.False(t, unprotected.Protected)
 }
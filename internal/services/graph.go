@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"feature-flags/internal/logging"
+	"feature-flags/internal/metrics"
+	"feature-flags/internal/models"
+	"feature-flags/internal/repository/mongodb"
+	"feature-flags/internal/tracing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// dependencyGraph is an immutable adjacency list of every dependency edge.
+type dependencyGraph struct {
+	children map[primitive.ObjectID][]primitive.ObjectID
+	parents  map[primitive.ObjectID][]primitive.ObjectID
+	edges    int
+}
+
+func newDependencyGraph(dependencies []models.FeatureDependency) *dependencyGraph {
+	g := &dependencyGraph{
+		children: make(map[primitive.ObjectID][]primitive.ObjectID),
+		parents:  make(map[primitive.ObjectID][]primitive.ObjectID),
+		edges:    len(dependencies),
+	}
+	for _, d := range dependencies {
+		g.children[d.ParentID] = append(g.children[d.ParentID], d.ChildID)
+		g.parents[d.ChildID] = append(g.parents[d.ChildID], d.ParentID)
+	}
+	return g
+}
+
+// Children is a childrenFunc. It never fails. The graph is shared, so
+// callers must not modify the returned slice; the same goes for Parents.
+func (g *dependencyGraph) Children(_ context.Context, id primitive.ObjectID) ([]primitive.ObjectID, error) {
+	return g.children[id], nil
+}
+
+func (g *dependencyGraph) Parents(id primitive.ObjectID) []primitive.ObjectID {
+	return g.parents[id]
+}
+
+// ChildrenOf returns the children of every feature in ids, each once, in
+// the order they are first reached. ParentsOf does the same for parents.
+func (g *dependencyGraph) ChildrenOf(ids ...primitive.ObjectID) []primitive.ObjectID {
+	return neighbours(g.children, ids)
+}
+
+func (g *dependencyGraph) ParentsOf(ids ...primitive.ObjectID) []primitive.ObjectID {
+	return neighbours(g.parents, ids)
+}
+
+func neighbours(adjacent map[primitive.ObjectID][]primitive.ObjectID, ids []primitive.ObjectID) []primitive.ObjectID {
+	var result []primitive.ObjectID
+	seen := make(map[primitive.ObjectID]bool)
+	for _, id := range ids {
+		for _, n := range adjacent[id] {
+			if !seen[n] {
+				seen[n] = true
+				result = append(result, n)
+			}
+		}
+	}
+	return result
+}
+
+// graphMaxAge bounds how long a cached dependency graph is used. Writes
+// made by other instances are seen within it even without change streams.
+const graphMaxAge = 10 * time.Second
+
+// graphCache holds the dependency graph in memory for reads. Every
+// dependency write invalidates it and the next read reloads it. Writes made
+// by other instances are picked up through WatchChanges, or once the graph
+// ages out.
+type graphCache struct {
+	mu       sync.Mutex
+	graph    *dependencyGraph
+	loadedAt time.Time
+	// generation counts invalidations, so a load that raced with a write
+	// is not cached.
+	generation uint64
+}
+
+func (c *graphCache) get(ctx context.Context, load func(ctx context.Context) ([]models.FeatureDependency, error)) (*dependencyGraph, error) {
+	c.mu.Lock()
+	graph, generation := c.graph, c.generation
+	if graph != nil && time.Since(c.loadedAt) > graphMaxAge {
+		graph = nil
+	}
+	c.mu.Unlock()
+	if graph != nil {
+		return graph, nil
+	}
+
+	loadedAt := time.Now()
+	dependencies, err := load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dependency graph: %w", err)
+	}
+	graph = newDependencyGraph(dependencies)
+	metrics.DependencyGraphLoads.Inc()
+
+	c.mu.Lock()
+	if c.generation == generation {
+		c.graph = graph
+		c.loadedAt = loadedAt
+	}
+	c.mu.Unlock()
+	return graph, nil
+}
+
+func (c *graphCache) invalidate() {
+	c.mu.Lock()
+	c.graph = nil
+	c.generation++
+	c.mu.Unlock()
+}
+
+// dependencyGraph returns the cached dependency graph, loading it if needed.
+// It may lag behind other instances, so only reads use it.
+func (s *FeatureService) dependencyGraph(ctx context.Context) (*dependencyGraph, error) {
+	return s.graph.get(ctx, s.dependencyRepo.List)
+}
+
+// currentDependencyGraph loads the dependency graph with one query,
+// bypassing the cache. The cycle, cascade and parent checks of writes use
+// it, so they see edges another instance has just stored, and within a
+// transaction the transaction's own writes.
+func (s *FeatureService) currentDependencyGraph(ctx context.Context) (*dependencyGraph, error) {
+	dependencies, err := s.dependencyRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dependency graph: %w", err)
+	}
+	return newDependencyGraph(dependencies), nil
+}
+
+// LoadDependencyGraph loads the dependency graph cache, so the first
+// traversal after startup does not pay for it.
+func (s *FeatureService) LoadDependencyGraph(ctx context.Context) error {
+	ctx, span := start(ctx, "LoadDependencyGraph")
+	defer span.End()
+
+	graph, err := s.dependencyGraph(ctx)
+	if err != nil {
+		return err
+	}
+	span.SetAttributes(tracing.DependencyCountKey.Int(graph.edges))
+	return nil
+}
+
+// WatchChanges invalidates the dependency graph and evaluation snapshot
+// caches whenever another instance changes a feature, dependency or
+// segment, until ctx is done. It returns mongodb.ErrChangeStreamsUnsupported
+// at once against a standalone MongoDB server, where both caches see other
+// instances' writes only once they age out.
+func (s *FeatureService) WatchChanges(ctx context.Context) error {
+	watches := []struct {
+		collection string
+		watch      func(context.Context, func()) error
+		onChange   func()
+	}{
+		{"feature_dependencies", s.dependencyRepo.Watch, func() {
+			s.graph.invalidate()
+			s.snapshots.invalidate()
+		}},
+		{"features", s.featureRepo.Watch, s.snapshots.invalidate},
+		{"segments", s.segmentRepo.Watch, s.snapshots.invalidate},
+	}
+
+	errs := make(chan error, len(watches))
+	for _, w := range watches {
+		go func() {
+			errs <- watchCollection(ctx, w.collection, w.watch, w.onChange)
+		}()
+	}
+	var err error
+	for range watches {
+		if watchErr := <-errs; err == nil {
+			err = watchErr
+		}
+	}
+	return err
+}
+
+// watchCollection runs watch until ctx is done, restarting the change
+// stream when it fails.
+func watchCollection(ctx context.Context, collection string, watch func(context.Context, func()) error, onChange func()) error {
+	for {
+		err := watch(ctx, onChange)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if errors.Is(err, mongodb.ErrChangeStreamsUnsupported) {
+			return err
+		}
+		// Changes made while the stream was down were missed.
+		onChange()
+		slog.WarnContext(ctx, "change stream failed, restarting", "collection", collection, logging.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Second):
+		}
+	}
+}
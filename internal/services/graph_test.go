@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"feature-flags/internal/models"
+	"feature-flags/internal/repository/mongodb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGraphCache(t *testing.T) {
+	a, b, c := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	edges := []models.FeatureDependency{{ParentID: a, ChildID: b}}
+	loads := 0
+	load := func(context.Context) ([]models.FeatureDependency, error) {
+		loads++
+		return edges, nil
+	}
+
+	var cache graphCache
+	ctx := context.Background()
+	graph, err := cache.get(ctx, load)
+	require.NoError(t, err)
+	children, _ := graph.Children(ctx, a)
+	assert.Equal(t, []primitive.ObjectID{b}, children)
+	assert.Equal(t, []primitive.ObjectID{a}, graph.Parents(b))
+
+	_, err = cache.get(ctx, load)
+	require.NoError(t, err)
+	assert.Equal(t, 1, loads, "cached graph is reused")
+
+	edges = append(edges, models.FeatureDependency{ParentID: a, ChildID: c})
+	cache.invalidate()
+	graph, err = cache.get(ctx, load)
+	require.NoError(t, err)
+	children, _ = graph.Children(ctx, a)
+	assert.Equal(t, []primitive.ObjectID{b, c}, children)
+	assert.Equal(t, 2, loads)
+
+	// Writes by other instances are seen once the graph ages out.
+	cache.loadedAt = time.Now().Add(-graphMaxAge - time.Second)
+	_, err = cache.get(ctx, load)
+	require.NoError(t, err)
+	assert.Equal(t, 3, loads)
+}
+
+func TestDependencyGraph_ChildrenOfAndParentsOf(t *testing.T) {
+	a, b, c, d := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	graph := newDependencyGraph([]models.FeatureDependency{
+		{ParentID: a, ChildID: c},
+		{ParentID: b, ChildID: c},
+		{ParentID: b, ChildID: d},
+	})
+
+	assert.Equal(t, []primitive.ObjectID{c, d}, graph.ChildrenOf(a, b))
+	assert.Equal(t, []primitive.ObjectID{a, b}, graph.ParentsOf(c, d))
+	assert.Empty(t, graph.ChildrenOf(c, d))
+	assert.Empty(t, graph.ParentsOf())
+}
+
+func TestGraphCache_InvalidatedDuringLoad(t *testing.T) {
+	var cache graphCache
+	ctx := context.Background()
+
+	// A write lands while the graph is being read, so the loaded graph may
+	// miss it and must not be cached.
+	_, err := cache.get(ctx, func(context.Context) ([]models.FeatureDependency, error) {
+		cache.invalidate()
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	loaded := false
+	_, err = cache.get(ctx, func(context.Context) ([]models.FeatureDependency, error) {
+		loaded = true
+		return nil, nil
+	})
+	require.NoError(t, err)
+	assert.True(t, loaded)
+}
+
+// chain returns n features linked head -> ... -> tail.
+func chain(n int) (edges []models.FeatureDependency, head, tail primitive.ObjectID) {
+	ids := make([]primitive.ObjectID, n)
+	for i := range ids {
+		ids[i] = primitive.NewObjectID()
+	}
+	for i := 1; i < n; i++ {
+		edges = append(edges, models.FeatureDependency{ParentID: ids[i-1], ChildID: ids[i]})
+	}
+	return edges, ids[0], ids[n-1]
+}
+
+// BenchmarkDetectCycle checks an edge that closes a 500-feature chain, so the
+// whole chain is walked. "repository" queries MongoDB once per feature, as
+// cycle checks did before the graph cache; "cached" walks the in-memory
+// graph.
+func BenchmarkDetectCycle(b *testing.B) {
+	edges, head, tail := chain(500)
+	ctx := context.Background()
+
+	b.Run("cached", func(b *testing.B) {
+		graph := newDependencyGraph(edges)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := detectCycle(ctx, tail, head, graph.Children); err == nil {
+				b.Fatal("expected a cycle")
+			}
+		}
+	})
+
+	b.Run("repository", func(b *testing.B) {
+		db, cleanup := setupTestDB(b)
+		defer cleanup()
+		repo := mongodb.NewFeatureDependencyRepository(db)
+		for _, edge := range edges {
+			require.NoError(b, repo.Create(ctx, &edge))
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := detectCycle(ctx, tail, head, repo.GetChildren); err == nil {
+				b.Fatal("expected a cycle")
+			}
+		}
+	})
+}
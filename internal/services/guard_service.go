@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"feature-flags/internal/auth"
+	"feature-flags/internal/logging"
+	"feature-flags/internal/metrics"
+	"feature-flags/internal/models"
+	"feature-flags/internal/tracing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MaxSignalBatch is the most signals one ReportSignals call accepts.
+const MaxSignalBatch = 1000
+
+// KillSwitchActor is recorded as the actor of the disables, change requests
+// and audit entries made when a guard rule trips.
+const KillSwitchActor = "system:kill-switch"
+
+// DefaultAuditLimit and MaxAuditLimit bound the entries ListAuditLog
+// returns.
+const (
+	DefaultAuditLimit = 100
+	MaxAuditLimit     = 1000
+)
+
+var (
+	ErrInvalidGuard  = errors.New("invalid guard rule")
+	ErrInvalidSignal = errors.New("invalid signal")
+)
+
+// SetGuard sets the guard rule of a feature, or removes it when guard is
+// nil. Setting a rule clears when it last tripped. Guards only act through
+// DisableFeature, so protection applies when they trip rather than here.
+// Features managed by GitOps sync cannot be disabled by the API, so they
+// cannot have guards either.
+func (s *FeatureService) SetGuard(ctx context.Context, id primitive.ObjectID, guard *models.GuardRule) (*models.Feature, error) {
+	ctx, span := start(ctx, "SetGuard", tracing.FeatureID(id))
+	defer span.End()
+
+	if guard != nil {
+		if err := guard.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidGuard, err)
+		}
+		guard.TrippedAt = nil
+	}
+	feature, err := s.getFeature(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkUnmanaged(feature); err != nil {
+		return nil, err
+	}
+
+	feature.Guard = guard
+	if err := s.featureRepo.SetGuard(ctx, feature); err != nil {
+		return nil, fmt.Errorf("failed to set guard: %w", err)
+	}
+	return feature, nil
+}
+
+// ReportSignals stores the error and success counts services report per
+// flag. Every signal is validated before any is stored; those without a
+// timestamp get the current time and those more than MaxClockSkew in the
+// future are rejected, as they would be counted again after a trip.
+func (s *FeatureService) ReportSignals(ctx context.Context, signals []models.Signal) error {
+	ctx, span := start(ctx, "ReportSignals", tracing.SignalCountKey.Int(len(signals)))
+	defer span.End()
+
+	if len(signals) == 0 {
+		return fmt.Errorf("%w: at least one signal is required", ErrInvalidSignal)
+	}
+	if len(signals) > MaxSignalBatch {
+		return fmt.Errorf("%w: at most %d signals may be sent at once", ErrInvalidSignal, MaxSignalBatch)
+	}
+	now := time.Now()
+	for i := range signals {
+		if err := signals[i].Validate(); err != nil {
+			return fmt.Errorf("%w: signals[%d]: %v", ErrInvalidSignal, i, err)
+		}
+		if signals[i].Timestamp.IsZero() {
+			signals[i].Timestamp = now
+		}
+		if err := checkTimestamp(signals[i].Timestamp, now); err != nil {
+			return fmt.Errorf("%w: signals[%d]: %v", ErrInvalidSignal, i, err)
+		}
+	}
+	if err := s.signalRepo.Insert(ctx, signals); err != nil {
+		return fmt.Errorf("failed to store signals: %w", err)
+	}
+	return nil
+}
+
+// CheckGuards checks the guard rule of every enabled feature against the
+// signals reported for it and disables, with DisableFeature and its full
+// cascade, each feature whose rule trips. Signals reported before a rule
+// last tripped are not counted again. Each trip is claimed in the database
+// first, so when several instances check at once only one acts on it; the
+// claim is released when the disable fails, so the next check tries again,
+// unless it failed because the cascade reaches a GitOps-managed feature,
+// which no retry fixes. A protected feature stays enabled until the change
+// request filed for it is approved, and no further request is filed while
+// that one is pending.
+//
+// It returns an audit entry per trip, also stored in the audit trail; a trip
+// that failed to disable its feature has Error set, and one that filed a
+// change request has Pending set instead of Disabled. The error joins the
+// failures to check a rule, to release a claim or to store an entry.
+func (s *FeatureService) CheckGuards(ctx context.Context) ([]*models.AuditEntry, error) {
+	ctx, span := start(ctx, "CheckGuards")
+	defer span.End()
+
+	enabled := true
+	features, err := s.featureRepo.Find(ctx, models.FeatureFilter{Enabled: &enabled, Guarded: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list guarded features: %w", err)
+	}
+
+	var (
+		trips []*models.AuditEntry
+		errs  []error
+	)
+	for _, feature := range features {
+		entry, err := s.checkGuard(ctx, feature)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("feature %s: %w", feature.FlagKey(), err))
+		}
+		if entry != nil {
+			trips = append(trips, entry)
+		}
+	}
+	tracing.SetAttributes(ctx, tracing.ResultCountKey.Int(len(trips)))
+	return trips, errors.Join(errs...)
+}
+
+// checkGuard trips the guard of feature if its metric reached the
+// threshold. It returns nil when the rule did not trip, a disable of the
+// feature already awaits approval or another instance claimed the trip.
+func (s *FeatureService) checkGuard(ctx context.Context, feature *models.Feature) (*models.AuditEntry, error) {
+	guard := feature.Guard
+	now := time.Now()
+	since := now.Add(-guard.WindowDuration())
+	if guard.TrippedAt != nil && guard.TrippedAt.After(since) {
+		since = *guard.TrippedAt
+	}
+
+	counts, err := s.signalRepo.Counts(ctx, feature.FlagKey(), since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count signals: %w", err)
+	}
+	value, tripped := guard.Check(counts)
+	if !tripped {
+		return nil, nil
+	}
+	_, err = s.changeRequestRepo.FindPending(ctx, feature.ID, models.ChangeRequestActionDisable)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("failed to find pending disable: %w", err)
+	}
+	claimed, err := s.featureRepo.ClaimGuardTrip(ctx, feature.ID, guard.TrippedAt, now)
+	if err != nil || !claimed {
+		return nil, err
+	}
+
+	entry := &models.AuditEntry{
+		Action:     models.AuditActionKillSwitch,
+		FeatureID:  feature.ID,
+		FeatureKey: feature.FlagKey(),
+		Actor:      KillSwitchActor,
+		Reason: fmt.Sprintf("%s %.4g reached threshold %.4g over %s (%d errors, %d successes)",
+			guard.Metric, value, guard.Threshold, guard.Window, counts.Errors, counts.Successes),
+	}
+
+	ctx = auth.WithPrincipal(ctx, &auth.Principal{Subject: KillSwitchActor})
+	cascade, err := s.requestDisable(ctx, feature.ID)
+	var (
+		pending    *PendingChangeError
+		releaseErr error
+	)
+	switch {
+	case errors.As(err, &pending):
+		entry.ChangeRequestID = &pending.ChangeRequest.ID
+		entry.Pending = flagKeys(cascade)
+		metrics.KillSwitchTrips.WithLabelValues("pending").Inc()
+	case err != nil:
+		entry.Error = err.Error()
+		metrics.KillSwitchTrips.WithLabelValues("failed").Inc()
+		// The feature is still enabled, so let the next check trip it again,
+		// unless only a manifest change can let the disable through.
+		if !errors.Is(err, ErrFeatureManaged) {
+			releaseErr = s.featureRepo.ReleaseGuardTrip(ctx, feature.ID, now, guard.TrippedAt)
+		}
+	default:
+		entry.Disabled = flagKeys(cascade)
+		metrics.KillSwitchTrips.WithLabelValues("disabled").Inc()
+	}
+
+	switch {
+	case pending != nil:
+		slog.WarnContext(ctx, "kill switch tripped; protected feature stays enabled until its change request is approved",
+			"feature", entry.FeatureKey, "reason", entry.Reason, "pending", entry.Pending,
+			"change_request_id", pending.ChangeRequest.ID.Hex())
+	case err != nil:
+		slog.ErrorContext(ctx, "kill switch tripped but failed to disable the feature",
+			"feature", entry.FeatureKey, "reason", entry.Reason, logging.Error(err))
+	default:
+		slog.WarnContext(ctx, "kill switch tripped",
+			"feature", entry.FeatureKey, "reason", entry.Reason, "disabled", entry.Disabled)
+	}
+	if releaseErr != nil {
+		releaseErr = fmt.Errorf("failed to release guard trip: %w", releaseErr)
+	}
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		return entry, errors.Join(releaseErr, fmt.Errorf("failed to record audit entry: %w", err))
+	}
+	return entry, releaseErr
+}
+
+// flagKeys returns the flag keys of features, in order.
+func flagKeys(features []*models.Feature) []string {
+	keys := make([]string, len(features))
+	for i, f := range features {
+		keys[i] = f.FlagKey()
+	}
+	return keys
+}
+
+// ListAuditLog returns the audit entries matching filter, newest first.
+// A zero limit returns DefaultAuditLimit entries and larger limits are
+// capped at MaxAuditLimit.
+func (s *FeatureService) ListAuditLog(ctx context.Context, filter models.AuditFilter) ([]*models.AuditEntry, error) {
+	ctx, span := start(ctx, "ListAuditLog", tracing.FlagKeyKey.String(filter.FeatureKey))
+	defer span.End()
+
+	switch {
+	case filter.Limit <= 0:
+		filter.Limit = DefaultAuditLimit
+	case filter.Limit > MaxAuditLimit:
+		filter.Limit = MaxAuditLimit
+	}
+	entries, err := s.auditRepo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit log: %w", err)
+	}
+	return entries, nil
+}
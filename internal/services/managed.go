@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	"feature-flags/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrFeatureManaged is returned when a change targets a feature that is
+// owned by GitOps sync. Such features can only be changed in their manifest.
+var ErrFeatureManaged = kindError(ErrConflict, "feature is managed by gitops sync")
+
+// managedSet holds the flag keys of the features declared in the synced
+// manifest directory. Keys, unlike names, stay the same when a manifest
+// renames a feature. The zero value manages nothing.
+type managedSet struct {
+	mu   sync.RWMutex
+	keys map[string]bool
+}
+
+// SetManagedFeatures replaces the set of features, by flag key, owned by
+// GitOps sync. Mutations to them through the API are rejected with
+// ErrFeatureManaged.
+func (s *FeatureService) SetManagedFeatures(keys []string) {
+	set := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		set[key] = true
+	}
+
+	s.managed.mu.Lock()
+	s.managed.keys = set
+	s.managed.mu.Unlock()
+}
+
+// ManagedFeatures returns the flag keys of the features owned by GitOps
+// sync.
+func (s *FeatureService) ManagedFeatures() []string {
+	s.managed.mu.RLock()
+	defer s.managed.mu.RUnlock()
+
+	keys := make([]string, 0, len(s.managed.keys))
+	for key := range s.managed.keys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s *FeatureService) isManaged(key string) bool {
+	s.managed.mu.RLock()
+	defer s.managed.mu.RUnlock()
+	return s.managed.keys[key]
+}
+
+func (s *FeatureService) checkUnmanaged(features ...*models.Feature) error {
+	for _, feature := range features {
+		if s.isManaged(feature.FlagKey()) {
+			return fmt.Errorf("%w: %s", ErrFeatureManaged, feature.FlagKey())
+		}
+	}
+	return nil
+}
+
+func (s *FeatureService) checkUnmanagedIDs(ctx context.Context, ids ...primitive.ObjectID) error {
+	features, err := s.getFeatures(ctx, ids...)
+	if err != nil {
+		return err
+	}
+	return s.checkUnmanaged(features...)
+}
+
+// checkChangesUnmanaged rejects manifest changes that would change a
+// managed feature or one of its dependencies.
+func (s *FeatureService) checkChangesUnmanaged(changes *manifestChanges) error {
+	if err := s.checkUnmanaged(changes.creates...); err != nil {
+		return err
+	}
+	if err := s.checkUnmanaged(changes.updates...); err != nil {
+		return err
+	}
+	ids := slices.Clone(changes.deletes)
+	for _, deps := range [][]models.FeatureDependency{changes.addEdges, changes.removeEdges} {
+		for _, d := range deps {
+			ids = append(ids, d.ParentID, d.ChildID)
+		}
+	}
+	for _, id := range ids {
+		if key, ok := changes.keys[id]; ok && s.isManaged(key) {
+			return fmt.Errorf("%w: %s", ErrFeatureManaged, key)
+		}
+	}
+	return nil
+}
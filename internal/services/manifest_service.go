@@ -0,0 +1,599 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"feature-flags/internal/manifest"
+	"feature-flags/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ManifestOptions controls how a manifest is reconciled against the server.
+type ManifestOptions struct {
+	// Prune deletes features and dependencies that are not in the manifest.
+	// Without it a manifest only creates and updates.
+	Prune bool
+	// Sync is set by GitOps sync, the only caller allowed to change managed
+	// features.
+	Sync bool
+}
+
+// ErrManifestProtected is returned when a manifest applied through the API
+// would change a protected feature. Such changes need a change request.
+var ErrManifestProtected = kindError(ErrConflict, "manifest changes protected features")
+
+// PartialApplyError is returned by ApplyManifest when a change failed
+// without a transaction to roll back the changes made before it. Applied
+// lists the changes that landed.
+type PartialApplyError struct {
+	Applied *manifest.Plan
+	Err     error
+}
+
+func (e *PartialApplyError) Error() string {
+	return fmt.Sprintf("manifest partly applied without a transaction: %v", e.Err)
+}
+
+func (e *PartialApplyError) Unwrap() error {
+	return e.Err
+}
+
+// manifestChanges is a plan together with the repository operations that
+// carry it out.
+type manifestChanges struct {
+	plan        *manifest.Plan
+	creates     []*models.Feature
+	updates     []*models.Feature
+	deletes     []primitive.ObjectID
+	addEdges    []models.FeatureDependency
+	removeEdges []models.FeatureDependency
+	// names holds the name of every feature the operations refer to.
+	names map[primitive.ObjectID]string
+	// keys holds the flag key of every feature the operations refer to.
+	keys map[primitive.ObjectID]string
+	// protected lists the currently protected features the operations
+	// change, delete or add or remove dependencies of.
+	protected []string
+}
+
+// ExportManifest returns the current features and dependencies as a manifest.
+func (s *FeatureService) ExportManifest(ctx context.Context) (*manifest.Manifest, error) {
+	ctx, span := start(ctx, "ExportManifest")
+	defer span.End()
+
+	features, err := s.featureRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list features: %w", err)
+	}
+	dependencies, err := s.dependencyRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dependencies: %w", err)
+	}
+
+	names := make(map[primitive.ObjectID]string, len(features))
+	m := &manifest.Manifest{
+		Features:     make([]manifest.Feature, 0, len(features)),
+		Dependencies: make([]manifest.Dependency, 0, len(dependencies)),
+	}
+	for _, f := range features {
+		names[f.ID] = f.Name
+		key := f.Key
+		if key == models.KeyFromName(f.Name) {
+			// Leave out keys the manifest would derive anyway.
+			key = ""
+		}
+		m.Features = append(m.Features, manifest.Feature{
+			Name:      f.Name,
+			Key:       key,
+			Type:      f.Type,
+			Enabled:   f.IsEnabled,
+			Protected: f.Protected,
+			Targeting: f.Targeting,
+		})
+	}
+	for _, d := range dependencies {
+		m.Dependencies = append(m.Dependencies, manifest.Dependency{Parent: names[d.ParentID], Child: names[d.ChildID]})
+	}
+
+	sort.Slice(m.Features, func(i, j int) bool { return m.Features[i].Name < m.Features[j].Name })
+	sort.Slice(m.Dependencies, func(i, j int) bool { return lessDependency(m.Dependencies[i], m.Dependencies[j]) })
+	return m, nil
+}
+
+// PlanManifest reports what ApplyManifest would change, without changing
+// anything.
+func (s *FeatureService) PlanManifest(ctx context.Context, m *manifest.Manifest, opts ManifestOptions) (*manifest.Plan, error) {
+	ctx, span := start(ctx, "PlanManifest")
+	defer span.End()
+
+	changes, err := s.diffManifest(ctx, m, opts)
+	if err != nil {
+		return nil, err
+	}
+	return changes.plan, nil
+}
+
+// ApplyManifest makes the server match the manifest. The plan is recomputed
+// and applied inside a single transaction, so either every change lands or
+// none does. Without transaction support a failed change leaves the ones
+// before it in place, and a *PartialApplyError lists them.
+//
+// Outside GitOps sync, whose manifests are reviewed in their repository, a
+// manifest that changes a protected feature is rejected with
+// ErrManifestProtected; such changes go through the feature endpoints and
+// their change requests. With approvals disabled it cannot protect
+// features either.
+func (s *FeatureService) ApplyManifest(ctx context.Context, m *manifest.Manifest, opts ManifestOptions) (*manifest.Plan, error) {
+	ctx, span := start(ctx, "ApplyManifest")
+	defer span.End()
+
+	// Traversals and evaluations during the transaction may cache a graph
+	// or snapshot that is never committed, or from before the commit.
+	defer s.graph.invalidate()
+	defer s.snapshots.invalidate()
+
+	atomic, err := s.txManager.Supported(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check transaction support: %w", err)
+	}
+
+	var plan, applied *manifest.Plan
+	err = s.txManager.Run(ctx, func(ctx context.Context) error {
+		changes, err := s.diffManifest(ctx, m, opts)
+		if err != nil {
+			return err
+		}
+		if !opts.Sync {
+			if err := s.checkChangesUnmanaged(changes); err != nil {
+				return err
+			}
+			if len(changes.protected) > 0 {
+				return fmt.Errorf("%w: %s", ErrManifestProtected, strings.Join(changes.protected, ", "))
+			}
+			for _, features := range [][]*models.Feature{changes.creates, changes.updates} {
+				for _, f := range features {
+					if err := s.checkProtectable(f.Protected); err != nil {
+						return fmt.Errorf("%w: %s", err, f.Name)
+					}
+				}
+			}
+		}
+		// Run may retry fn, so only the last attempt is reported.
+		applied = manifest.NewPlan()
+		if err := s.applyManifestChanges(ctx, changes, applied); err != nil {
+			return err
+		}
+		plan = changes.plan
+		return nil
+	})
+	if err != nil {
+		if !atomic && applied != nil && !applied.Empty() {
+			slog.ErrorContext(ctx, "manifest partly applied",
+				"creates", len(applied.Creates),
+				"updates", len(applied.Updates)+len(applied.Cascades),
+				"deletes", len(applied.Deletes),
+				"sync", opts.Sync,
+			)
+			return nil, &PartialApplyError{Applied: applied, Err: err}
+		}
+		return nil, err
+	}
+	plan.Applied = true
+	if !plan.Empty() {
+		slog.InfoContext(ctx, "manifest applied",
+			"creates", len(plan.Creates),
+			"updates", len(plan.Updates),
+			"deletes", len(plan.Deletes),
+			"sync", opts.Sync,
+		)
+	}
+	return plan, nil
+}
+
+func (s *FeatureService) diffManifest(ctx context.Context, m *manifest.Manifest, opts ManifestOptions) (*manifestChanges, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkManifestSegments(ctx, m); err != nil {
+		return nil, err
+	}
+
+	features, err := s.featureRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list features: %w", err)
+	}
+	dependencies, err := s.dependencyRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dependencies: %w", err)
+	}
+	return buildManifestChanges(ctx, features, dependencies, m, opts)
+}
+
+// checkManifestSegments reports rules that reference segments which do not
+// exist. Segments are not part of manifests, so they must be created first.
+func (s *FeatureService) checkManifestSegments(ctx context.Context, m *manifest.Manifest) error {
+	var keys []string
+	for i := range m.Features {
+		for _, key := range m.Features[i].Targeting.SegmentKeys() {
+			if !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	missing, err := s.missingSegments(ctx, keys)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: unknown segments %s", manifest.ErrInvalid, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// applyManifestChanges carries out changes, recording in applied each
+// change once it has landed.
+func (s *FeatureService) applyManifestChanges(ctx context.Context, changes *manifestChanges, applied *manifest.Plan) error {
+	plan := changes.plan
+	for i, f := range changes.creates {
+		if err := s.createFeature(ctx, f); err != nil {
+			return fmt.Errorf("failed to create feature %s: %w", f.Name, err)
+		}
+		applied.Creates = append(applied.Creates, plan.Creates[i])
+	}
+	for _, f := range changes.updates {
+		if err := s.featureRepo.Update(ctx, f); err != nil {
+			return fmt.Errorf("failed to update feature %s: %w", f.Name, err)
+		}
+		if i := slices.IndexFunc(plan.Updates, func(c manifest.FeatureChange) bool { return c.Name == f.Name }); i >= 0 {
+			applied.Updates = append(applied.Updates, plan.Updates[i])
+		}
+		if i := slices.IndexFunc(plan.Cascades, func(c manifest.Cascade) bool { return c.Name == f.Name }); i >= 0 {
+			applied.Cascades = append(applied.Cascades, plan.Cascades[i])
+		}
+	}
+	for _, d := range changes.removeEdges {
+		if err := s.dependencyRepo.Delete(ctx, d.ParentID, d.ChildID); err != nil {
+			return fmt.Errorf("failed to remove dependency: %w", err)
+		}
+		applied.DependencyRemoves = append(applied.DependencyRemoves, manifest.Dependency{Parent: changes.names[d.ParentID], Child: changes.names[d.ChildID]})
+	}
+	for _, d := range changes.addEdges {
+		dependency := d
+		if err := s.dependencyRepo.Create(ctx, &dependency); err != nil {
+			return fmt.Errorf("failed to add dependency: %w", err)
+		}
+		applied.DependencyAdds = append(applied.DependencyAdds, manifest.Dependency{Parent: changes.names[d.ParentID], Child: changes.names[d.ChildID]})
+	}
+	for _, id := range changes.deletes {
+		if err := s.dependencyRepo.DeleteByFeature(ctx, id); err != nil {
+			return fmt.Errorf("failed to remove dependencies: %w", err)
+		}
+		if err := s.featureRepo.Delete(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete feature: %w", err)
+		}
+		applied.Deletes = append(applied.Deletes, manifest.FeatureChange{Name: changes.names[id]})
+	}
+	return nil
+}
+
+// buildManifestChanges diffs the current features and dependencies against
+// the manifest. The resulting graph is checked for cycles with the same
+// logic AddChild uses, and features whose parents end up disabled are
+// disabled too, as DisableFeature would.
+func buildManifestChanges(ctx context.Context, features []*models.Feature, dependencies []models.FeatureDependency, m *manifest.Manifest, opts ManifestOptions) (*manifestChanges, error) {
+	changes := &manifestChanges{plan: manifest.NewPlan()}
+
+	current := make(map[primitive.ObjectID]*models.Feature, len(features))
+	byKey := make(map[string]*models.Feature, len(features))
+	byName := make(map[string][]*models.Feature, len(features))
+	for _, f := range features {
+		current[f.ID] = f
+		byKey[f.FlagKey()] = f
+		byName[f.Name] = append(byName[f.Name], f)
+	}
+
+	// Desired state of every feature that survives the apply. Features are
+	// matched by key, so a manifest can rename them.
+	desired := make(map[primitive.ObjectID]*models.Feature, len(features)+len(m.Features))
+	ids := make(map[string]primitive.ObjectID, len(m.Features))
+	inManifest := make(map[primitive.ObjectID]bool, len(m.Features))
+	for _, mf := range m.Features {
+		target, err := matchManifestFeature(mf, byKey, byName)
+		if err != nil {
+			return nil, err
+		}
+		if inManifest[target.ID] {
+			return nil, fmt.Errorf("%w: feature %q matches a feature listed earlier in the manifest", manifest.ErrInvalid, mf.Name)
+		}
+		target.Name = mf.Name
+		target.Type = mf.Type
+		target.IsEnabled = mf.Enabled
+		target.Protected = mf.Protected
+		target.Targeting = mf.Targeting
+
+		desired[target.ID] = target
+		ids[mf.Name] = target.ID
+		inManifest[target.ID] = true
+	}
+	for _, f := range features {
+		if inManifest[f.ID] {
+			continue
+		}
+		if opts.Prune {
+			changes.deletes = append(changes.deletes, f.ID)
+			changes.plan.Deletes = append(changes.plan.Deletes, manifest.FeatureChange{Name: f.Name})
+			continue
+		}
+		kept := *f
+		desired[f.ID] = &kept
+	}
+
+	// Desired dependency edges.
+	type edge struct{ parent, child primitive.ObjectID }
+	finalEdges := make(map[edge]bool)
+	for _, d := range m.Dependencies {
+		finalEdges[edge{ids[d.Parent], ids[d.Child]}] = true
+	}
+	currentEdges := make(map[edge]bool, len(dependencies))
+	for _, d := range dependencies {
+		e := edge{d.ParentID, d.ChildID}
+		currentEdges[e] = true
+		if desired[d.ParentID] == nil || desired[d.ChildID] == nil {
+			// Edges of pruned features go away with them.
+			continue
+		}
+		if !opts.Prune {
+			finalEdges[e] = true
+		}
+	}
+
+	changes.names = make(map[primitive.ObjectID]string, len(current)+len(desired))
+	changes.keys = make(map[primitive.ObjectID]string, len(current)+len(desired))
+	for id, f := range current {
+		changes.names[id] = f.Name
+		changes.keys[id] = f.FlagKey()
+	}
+	for id, f := range desired {
+		changes.names[id] = f.Name
+		changes.keys[id] = f.FlagKey()
+	}
+	name := func(id primitive.ObjectID) string {
+		if n, ok := changes.names[id]; ok {
+			return n
+		}
+		return id.Hex()
+	}
+
+	// Protected features, by their current name, that the apply changes.
+	protected := make(map[string]bool)
+	touch := func(ids ...primitive.ObjectID) {
+		for _, id := range ids {
+			if f, ok := current[id]; ok && f.Protected {
+				protected[f.Name] = true
+			}
+		}
+	}
+	touch(changes.deletes...)
+
+	for e := range finalEdges {
+		if !currentEdges[e] {
+			changes.addEdges = append(changes.addEdges, models.FeatureDependency{ParentID: e.parent, ChildID: e.child})
+			changes.plan.DependencyAdds = append(changes.plan.DependencyAdds, manifest.Dependency{Parent: name(e.parent), Child: name(e.child)})
+			touch(e.parent, e.child)
+		}
+	}
+	for e := range currentEdges {
+		if !finalEdges[e] {
+			changes.removeEdges = append(changes.removeEdges, models.FeatureDependency{ParentID: e.parent, ChildID: e.child})
+			changes.plan.DependencyRemoves = append(changes.plan.DependencyRemoves, manifest.Dependency{Parent: name(e.parent), Child: name(e.child)})
+			touch(e.parent, e.child)
+		}
+	}
+
+	// Reject the manifest if the new edges close a cycle.
+	children := make(map[primitive.ObjectID][]primitive.ObjectID)
+	parents := make(map[primitive.ObjectID][]primitive.ObjectID)
+	for e := range finalEdges {
+		children[e.parent] = append(children[e.parent], e.child)
+		parents[e.child] = append(parents[e.child], e.parent)
+	}
+	inMemoryChildren := func(_ context.Context, id primitive.ObjectID) ([]primitive.ObjectID, error) {
+		return children[id], nil
+	}
+	sort.Slice(changes.addEdges, func(i, j int) bool {
+		a, b := changes.addEdges[i], changes.addEdges[j]
+		return lessDependency(manifest.Dependency{Parent: name(a.ParentID), Child: name(a.ChildID)}, manifest.Dependency{Parent: name(b.ParentID), Child: name(b.ChildID)})
+	})
+	for _, d := range changes.addEdges {
+		if err := detectCycle(ctx, d.ParentID, d.ChildID, inMemoryChildren); err != nil {
+			return nil, fmt.Errorf("dependency %s -> %s: %w", name(d.ParentID), name(d.ChildID), err)
+		}
+	}
+
+	// A feature stays enabled only if every parent ends up enabled.
+	effective := make(map[primitive.ObjectID]bool, len(desired))
+	disabledParent := make(map[primitive.ObjectID]primitive.ObjectID)
+	var resolve func(id primitive.ObjectID) bool
+	resolve = func(id primitive.ObjectID) bool {
+		if enabled, ok := effective[id]; ok {
+			return enabled
+		}
+		enabled := desired[id].IsEnabled
+		for _, parentID := range parents[id] {
+			if !resolve(parentID) {
+				disabledParent[id] = parentID
+				enabled = false
+				break
+			}
+		}
+		effective[id] = enabled
+		return enabled
+	}
+
+	ordered := make([]*models.Feature, 0, len(desired))
+	for _, f := range desired {
+		ordered = append(ordered, f)
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].Name < ordered[j].Name })
+
+	for _, f := range ordered {
+		cascaded := f.IsEnabled && !resolve(f.ID)
+		f.IsEnabled = effective[f.ID]
+
+		existing, exists := current[f.ID]
+		if cascaded && (!exists || existing.IsEnabled) {
+			changes.plan.Cascades = append(changes.plan.Cascades, manifest.Cascade{Name: f.Name, Parent: name(disabledParent[f.ID])})
+		}
+		if !exists {
+			changes.creates = append(changes.creates, f)
+			changes.plan.Creates = append(changes.plan.Creates, manifest.FeatureChange{
+				Name: f.Name,
+				Changes: []manifest.FieldChange{
+					{Field: "type", To: f.Type},
+					{Field: "enabled", To: f.IsEnabled},
+				},
+			})
+			continue
+		}
+
+		fieldChanges := diffFeature(existing, f)
+		if len(fieldChanges) == 0 {
+			continue
+		}
+		if changesEvaluation(fieldChanges) {
+			f.Version++
+		}
+		changes.updates = append(changes.updates, f)
+		touch(f.ID)
+
+		// A disable that only happens because of a cascade is reported as a
+		// cascade rather than as an update.
+		if cascaded {
+			fieldChanges = withoutField(fieldChanges, "enabled")
+		}
+		if len(fieldChanges) > 0 {
+			changes.plan.Updates = append(changes.plan.Updates, manifest.FeatureChange{Name: f.Name, Changes: fieldChanges})
+		}
+	}
+
+	sort.Slice(changes.plan.Deletes, func(i, j int) bool { return changes.plan.Deletes[i].Name < changes.plan.Deletes[j].Name })
+	sort.Slice(changes.plan.DependencyAdds, func(i, j int) bool {
+		return lessDependency(changes.plan.DependencyAdds[i], changes.plan.DependencyAdds[j])
+	})
+	sort.Slice(changes.plan.DependencyRemoves, func(i, j int) bool {
+		return lessDependency(changes.plan.DependencyRemoves[i], changes.plan.DependencyRemoves[j])
+	})
+	sort.Slice(changes.plan.Cascades, func(i, j int) bool { return changes.plan.Cascades[i].Name < changes.plan.Cascades[j].Name })
+	for n := range protected {
+		changes.protected = append(changes.protected, n)
+	}
+	sort.Strings(changes.protected)
+	return changes, nil
+}
+
+// matchManifestFeature returns a copy of the feature mf describes, or a new
+// feature if there is none. Features are matched by key; a feature without
+// a key, created before keys existed, is matched by name.
+func matchManifestFeature(mf manifest.Feature, byKey map[string]*models.Feature, byName map[string][]*models.Feature) (*models.Feature, error) {
+	key := mf.FeatureKey()
+	if f, ok := byKey[key]; ok {
+		target := *f
+		return &target, nil
+	}
+
+	matches := byName[mf.Name]
+	if len(matches) > 1 {
+		return nil, fmt.Errorf("feature name %q matches %d features", mf.Name, len(matches))
+	}
+	if len(matches) == 1 {
+		f := matches[0]
+		switch {
+		case f.Key == "":
+			target := *f
+			return &target, nil
+		case mf.Key == "":
+			return nil, fmt.Errorf("%w: feature %q has key %q; set it as the key in the manifest", manifest.ErrInvalid, mf.Name, f.Key)
+		default:
+			return nil, fmt.Errorf("%w: feature %q has key %q; keys cannot be changed", manifest.ErrInvalid, mf.Name, f.Key)
+		}
+	}
+	return &models.Feature{ID: primitive.NewObjectID(), Key: key}, nil
+}
+
+func diffFeature(from, to *models.Feature) []manifest.FieldChange {
+	var changes []manifest.FieldChange
+	if from.Name != to.Name {
+		changes = append(changes, manifest.FieldChange{Field: "name", From: from.Name, To: to.Name})
+	}
+	if from.Type != to.Type {
+		changes = append(changes, manifest.FieldChange{Field: "type", From: from.Type, To: to.Type})
+	}
+	if from.IsEnabled != to.IsEnabled {
+		changes = append(changes, manifest.FieldChange{Field: "enabled", From: from.IsEnabled, To: to.IsEnabled})
+	}
+	if from.Protected != to.Protected {
+		changes = append(changes, manifest.FieldChange{Field: "protected", From: from.Protected, To: to.Protected})
+	}
+	if (len(from.Rules) > 0 || len(to.Rules) > 0) && !reflect.DeepEqual(from.Rules, to.Rules) {
+		changes = append(changes, manifest.FieldChange{Field: "rules", From: len(from.Rules), To: len(to.Rules)})
+	}
+	if !sameVariants(from.Variants, to.Variants) {
+		changes = append(changes, manifest.FieldChange{Field: "variants", From: len(from.Variants), To: len(to.Variants)})
+	}
+	if from.DefaultVariant != to.DefaultVariant {
+		changes = append(changes, manifest.FieldChange{Field: "default_variant", From: from.DefaultVariant, To: to.DefaultVariant})
+	}
+	if from.OffVariant != to.OffVariant {
+		changes = append(changes, manifest.FieldChange{Field: "off_variant", From: from.OffVariant, To: to.OffVariant})
+	}
+	return changes
+}
+
+// changesEvaluation reports whether any of the changes can change how the
+// feature evaluates. Protection does not; the type does, since it decides
+// which plans are entitled to the feature.
+func changesEvaluation(changes []manifest.FieldChange) bool {
+	for _, c := range changes {
+		if c.Field != "protected" {
+			return true
+		}
+	}
+	return false
+}
+
+// sameVariants compares variants by their JSON encoding, since values
+// decoded from YAML, JSON and BSON use different numeric types.
+func sameVariants(a, b []models.Variant) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(encodedA, encodedB)
+}
+
+func withoutField(changes []manifest.FieldChange, field string) []manifest.FieldChange {
+	kept := changes[:0]
+	for _, c := range changes {
+		if c.Field != field {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+func lessDependency(a, b manifest.Dependency) bool {
+	if a.Parent != b.Parent {
+		return a.Parent < b.Parent
+	}
+	return a.Child < b.Child
+}
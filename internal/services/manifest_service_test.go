@@ -0,0 +1,299 @@
+package services
+
+import (
+	"context"
+	"feature-flags/internal/manifest"
+	"feature-flags/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func testFeature(name string, enabled bool) *models.Feature {
+	return &models.Feature{ID: primitive.NewObjectID(), Name: name, Type: models.FeatureTypeBasic, IsEnabled: enabled}
+}
+
+// applyInMemory applies changes to a copy of the given state, so tests can
+// check that planning again converges.
+func applyInMemory(features []*models.Feature, dependencies []models.FeatureDependency, changes *manifestChanges) ([]*models.Feature, []models.FeatureDependency) {
+	byID := make(map[primitive.ObjectID]*models.Feature)
+	for _, f := range features {
+		copied := *f
+		byID[f.ID] = &copied
+	}
+	for _, f := range append(changes.creates, changes.updates...) {
+		copied := *f
+		byID[f.ID] = &copied
+	}
+	deleted := make(map[primitive.ObjectID]bool)
+	for _, id := range changes.deletes {
+		deleted[id] = true
+		delete(byID, id)
+	}
+
+	removed := make(map[models.FeatureDependency]bool)
+	for _, d := range changes.removeEdges {
+		removed[models.FeatureDependency{ParentID: d.ParentID, ChildID: d.ChildID}] = true
+	}
+	var edges []models.FeatureDependency
+	for _, d := range append(dependencies, changes.addEdges...) {
+		key := models.FeatureDependency{ParentID: d.ParentID, ChildID: d.ChildID}
+		if removed[key] || deleted[d.ParentID] || deleted[d.ChildID] {
+			continue
+		}
+		edges = append(edges, key)
+	}
+
+	var result []*models.Feature
+	for _, f := range byID {
+		result = append(result, f)
+	}
+	return result, edges
+}
+
+func TestBuildManifestChanges(t *testing.T) {
+	ctx := context.Background()
+	checkout := testFeature("checkout", true)
+	oneClick := testFeature("one-click", true)
+	legacy := testFeature("legacy", false)
+	features := []*models.Feature{checkout, oneClick, legacy}
+	dependencies := []models.FeatureDependency{{ParentID: checkout.ID, ChildID: oneClick.ID}}
+
+	m := &manifest.Manifest{
+		Features: []manifest.Feature{
+			{Name: "checkout", Type: models.FeatureTypeBasic, Enabled: false},
+			{Name: "one-click", Type: models.FeatureTypePremium, Enabled: true},
+			{Name: "wallet", Type: models.FeatureTypeBasic, Enabled: true},
+		},
+		Dependencies: []manifest.Dependency{
+			{Parent: "checkout", Child: "one-click"},
+			{Parent: "one-click", Child: "wallet"},
+		},
+	}
+	require.NoError(t, m.Validate())
+
+	t.Run("without prune", func(t *testing.T) {
+		changes, err := buildManifestChanges(ctx, features, dependencies, m, ManifestOptions{})
+		require.NoError(t, err)
+		plan := changes.plan
+
+		require.Len(t, plan.Creates, 1)
+		assert.Equal(t, "wallet", plan.Creates[0].Name)
+		assert.Empty(t, plan.Deletes)
+		assert.Equal(t, []manifest.Dependency{{Parent: "one-click", Child: "wallet"}}, plan.DependencyAdds)
+
+		// checkout is disabled explicitly; one-click only changes type, its
+		// disable is a cascade.
+		require.Len(t, plan.Updates, 2)
+		assert.Equal(t, "checkout", plan.Updates[0].Name)
+		assert.Equal(t, []manifest.FieldChange{{Field: "type", From: models.FeatureTypeBasic, To: models.FeatureTypePremium}}, plan.Updates[1].Changes)
+		assert.Equal(t, []manifest.Cascade{
+			{Name: "one-click", Parent: "checkout"},
+			{Name: "wallet", Parent: "one-click"},
+		}, plan.Cascades)
+
+		// The new feature is created disabled because its parent will be.
+		assert.False(t, changes.creates[0].IsEnabled)
+
+		// Planning again after applying finds nothing to do.
+		nextFeatures, nextDependencies := applyInMemory(features, dependencies, changes)
+		again, err := buildManifestChanges(ctx, nextFeatures, nextDependencies, m, ManifestOptions{})
+		require.NoError(t, err)
+		assert.True(t, again.plan.Empty())
+	})
+
+	t.Run("with prune", func(t *testing.T) {
+		changes, err := buildManifestChanges(ctx, features, dependencies, m, ManifestOptions{Prune: true})
+		require.NoError(t, err)
+		assert.Equal(t, []manifest.FeatureChange{{Name: "legacy"}}, changes.plan.Deletes)
+		assert.Equal(t, []primitive.ObjectID{legacy.ID}, changes.deletes)
+	})
+
+	t.Run("prune removes undeclared edges", func(t *testing.T) {
+		bare := &manifest.Manifest{Features: []manifest.Feature{
+			{Name: "checkout", Type: models.FeatureTypeBasic, Enabled: true},
+			{Name: "one-click", Type: models.FeatureTypeBasic, Enabled: true},
+			{Name: "legacy", Type: models.FeatureTypeBasic},
+		}}
+		changes, err := buildManifestChanges(ctx, features, dependencies, bare, ManifestOptions{})
+		require.NoError(t, err)
+		assert.True(t, changes.plan.Empty())
+
+		changes, err = buildManifestChanges(ctx, features, dependencies, bare, ManifestOptions{Prune: true})
+		require.NoError(t, err)
+		assert.Equal(t, []manifest.Dependency{{Parent: "checkout", Child: "one-click"}}, changes.plan.DependencyRemoves)
+	})
+}
+
+func TestBuildManifestChanges_RejectsCycles(t *testing.T) {
+	ctx := context.Background()
+	a := testFeature("a", true)
+	b := testFeature("b", true)
+	features := []*models.Feature{a, b}
+	dependencies := []models.FeatureDependency{{ParentID: a.ID, ChildID: b.ID}}
+
+	m := &manifest.Manifest{
+		Features: []manifest.Feature{
+			{Name: "a", Type: models.FeatureTypeBasic, Enabled: true},
+			{Name: "b", Type: models.FeatureTypeBasic, Enabled: true},
+			{Name: "c", Type: models.FeatureTypeBasic, Enabled: true},
+		},
+		Dependencies: []manifest.Dependency{
+			{Parent: "b", Child: "c"},
+			{Parent: "c", Child: "a"},
+		},
+	}
+
+	_, err := buildManifestChanges(ctx, features, dependencies, m, ManifestOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cyclic dependency detected")
+
+	self := &manifest.Manifest{
+		Features:     []manifest.Feature{{Name: "a", Type: models.FeatureTypeBasic}},
+		Dependencies: []manifest.Dependency{{Parent: "a", Child: "a"}},
+	}
+	_, err = buildManifestChanges(ctx, features, nil, self, ManifestOptions{Prune: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot add self as child")
+}
+
+func TestBuildManifestChanges_Keys(t *testing.T) {
+	ctx := context.Background()
+	checkout := testFeature("checkout", true)
+	checkout.Key = "checkout"
+	features := []*models.Feature{checkout}
+
+	m := &manifest.Manifest{
+		Features: []manifest.Feature{
+			{Name: "checkout", Type: models.FeatureTypeBasic, Enabled: true},
+			{Name: "One Click", Type: models.FeatureTypeBasic, Enabled: true},
+			{Name: "wallet", Key: "wallet-v2", Type: models.FeatureTypeBasic, Enabled: true},
+		},
+	}
+	require.NoError(t, m.Validate())
+	changes, err := buildManifestChanges(ctx, features, nil, m, ManifestOptions{})
+	require.NoError(t, err)
+	keys := make(map[string]string)
+	for _, f := range changes.creates {
+		keys[f.Name] = f.Key
+	}
+	assert.Equal(t, map[string]string{"One Click": "one-click", "wallet": "wallet-v2"}, keys)
+
+	m.Features[0].Key = "checkout-v2"
+	_, err = buildManifestChanges(ctx, features, nil, m, ManifestOptions{})
+	assert.ErrorIs(t, err, manifest.ErrInvalid)
+	assert.ErrorContains(t, err, "keys cannot be changed")
+
+	// Features are matched by key, so the name can change.
+	m.Features[0] = manifest.Feature{Name: "Checkout", Key: "checkout", Type: models.FeatureTypeBasic, Enabled: true}
+	changes, err = buildManifestChanges(ctx, features, nil, m, ManifestOptions{})
+	require.NoError(t, err)
+	require.Len(t, changes.updates, 1)
+	assert.Equal(t, checkout.ID, changes.updates[0].ID)
+	assert.Equal(t, []manifest.FeatureChange{{Name: "Checkout", Changes: []manifest.FieldChange{{Field: "name", From: "checkout", To: "Checkout"}}}}, changes.plan.Updates)
+
+	// A new key must not reuse the name of a feature with another key.
+	m.Features[0] = manifest.Feature{Name: "checkout", Key: "basket", Type: models.FeatureTypeBasic, Enabled: true}
+	_, err = buildManifestChanges(ctx, features, nil, m, ManifestOptions{})
+	assert.ErrorIs(t, err, manifest.ErrInvalid)
+
+	duplicate := &manifest.Manifest{Features: []manifest.Feature{
+		{Name: "one click", Type: models.FeatureTypeBasic},
+		{Name: "One-Click", Type: models.FeatureTypeBasic},
+		{Name: "wallet", Key: "Wallet", Type: models.FeatureTypeBasic},
+	}}
+	err = duplicate.Validate()
+	assert.ErrorContains(t, err, `the same key "one-click"`)
+	assert.ErrorContains(t, err, "must be lowercase")
+}
+
+func TestBuildManifestChanges_Protected(t *testing.T) {
+	ctx := context.Background()
+	checkout := testFeature("checkout", true)
+	checkout.Protected = true
+	wallet := testFeature("wallet", true)
+	legacy := testFeature("legacy", true)
+	legacy.Protected = true
+	features := []*models.Feature{checkout, wallet, legacy}
+
+	manifestFeature := func(f *models.Feature) manifest.Feature {
+		return manifest.Feature{Name: f.Name, Type: f.Type, Enabled: f.IsEnabled, Protected: f.Protected}
+	}
+	m := &manifest.Manifest{Features: []manifest.Feature{manifestFeature(checkout), manifestFeature(wallet), manifestFeature(legacy)}}
+
+	// Unprotected changes, and protecting a feature, are allowed.
+	m.Features[1].Enabled = false
+	changes, err := buildManifestChanges(ctx, features, nil, m, ManifestOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, changes.protected)
+
+	// Changing or unprotecting a protected feature is not.
+	m.Features[0].Protected = false
+	changes, err = buildManifestChanges(ctx, features, nil, m, ManifestOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"checkout"}, changes.protected)
+
+	// Nor is adding a dependency to one or deleting one.
+	m.Features[0].Protected = true
+	m.Features = m.Features[:2]
+	m.Dependencies = []manifest.Dependency{{Parent: "wallet", Child: "checkout"}}
+	changes, err = buildManifestChanges(ctx, features, nil, m, ManifestOptions{Prune: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"checkout", "legacy"}, changes.protected)
+}
+
+func TestParseManifest(t *testing.T) {
+	m, err := manifest.Parse([]byte(`
+features:
+  - name: checkout
+    type: basic
+    enabled: true
+    rules:
+      - attribute: country
+        operator: in
+        values: [DE, FR]
+  - name: one-click
+    type: premium
+    enabled: false
+dependencies:
+  - parent: checkout
+    child: one-click
+`))
+	require.NoError(t, err)
+	require.Len(t, m.Features, 2)
+	assert.Equal(t, []string{"DE", "FR"}, m.Features[0].Rules[0].Values)
+
+	_, err = manifest.Parse([]byte(`{"features": [{"name": "x", "type": "gold"}], "dependencies": [{"parent": "x", "child": "y"}]}`))
+	require.ErrorIs(t, err, manifest.ErrInvalid)
+	assert.Contains(t, err.Error(), `unknown type "gold"`)
+	assert.Contains(t, err.Error(), `unknown child "y"`)
+
+	_, err = manifest.Parse([]byte("features:\n  - name: x\n    colour: red\n"))
+	assert.ErrorIs(t, err, manifest.ErrInvalid)
+}
+
+func TestFeatureService_ManagedFeaturesAreLocked(t *testing.T) {
+	service := &FeatureService{}
+	service.SetManagedFeatures([]string{"checkout"})
+	assert.Equal(t, []string{"checkout"}, service.ManagedFeatures())
+
+	err := service.CreateFeature(context.Background(), &models.Feature{Name: "checkout"})
+	assert.ErrorIs(t, err, ErrFeatureManaged)
+
+	// The lock follows the key, so renaming does not move it.
+	renamed := &models.Feature{Key: "checkout", Name: "Checkout v2"}
+	assert.ErrorIs(t, service.checkUnmanaged(renamed), ErrFeatureManaged)
+	assert.NoError(t, service.checkUnmanaged(&models.Feature{Key: "checkout-v2", Name: "checkout"}))
+
+	checkout, wallet := primitive.NewObjectID(), primitive.NewObjectID()
+	changes := &manifestChanges{
+		addEdges: []models.FeatureDependency{{ParentID: checkout, ChildID: wallet}},
+		keys:     map[primitive.ObjectID]string{checkout: "checkout", wallet: "wallet"},
+	}
+	assert.ErrorIs(t, service.checkChangesUnmanaged(changes), ErrFeatureManaged)
+
+	service.SetManagedFeatures(nil)
+	assert.NoError(t, service.checkChangesUnmanaged(changes))
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"context"
+	"feature-flags/internal/logging"
+	"feature-flags/internal/tracing"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// start begins a FeatureService operation: a span named after it, and log
+// context carrying the operation and the same attributes, e.g. feature_id.
+func start(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	ctx, span := tracing.Start(ctx, "FeatureService."+operation, attrs...)
+
+	logAttrs := make([]slog.Attr, 0, len(attrs)+1)
+	logAttrs = append(logAttrs, slog.String("operation", operation))
+	for _, attr := range attrs {
+		key := strings.ReplaceAll(string(attr.Key), ".", "_")
+		logAttrs = append(logAttrs, slog.Any(key, attr.Value.AsInterface()))
+	}
+	return logging.With(ctx, logAttrs...), span
+}
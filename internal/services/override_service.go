@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"feature-flags/internal/models"
+	"feature-flags/internal/tracing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AddOverrides adds overrides to a feature, replacing any it already has
+// with the same keys. The overrides are validated and the feature's limit
+// checked before anything is applied. If the feature is protected a pending
+// change request is created instead and a *PendingChangeError is returned.
+//
+// Overrides are not part of manifests, so they may be added to features
+// managed by GitOps sync.
+func (s *FeatureService) AddOverrides(ctx context.Context, id primitive.ObjectID, overrides []models.Override) (*models.Feature, error) {
+	ctx, span := start(ctx, "AddOverrides", tracing.FeatureID(id), tracing.OverrideCountKey.Int(len(overrides)))
+	defer span.End()
+
+	if len(overrides) == 0 {
+		return nil, fmt.Errorf("%w: at least one override is required", ErrInvalidOverride)
+	}
+	feature, err := s.getFeature(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	actor, now := actorFromContext(ctx), time.Now()
+	for i := range overrides {
+		overrides[i].CreatedBy, overrides[i].CreatedAt = actor, now
+	}
+	merged, replaced, err := mergeOverrides(feature, overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	if feature.Protected {
+		return nil, s.submitChangeRequest(ctx, &models.ChangeRequest{
+			Action:    models.ChangeRequestActionAddOverrides,
+			FeatureID: id,
+			Overrides: overrides,
+			Diff: models.ChangeRequestDiff{Features: []models.FeatureChange{{
+				FeatureID: id,
+				Name:      feature.Name,
+				Field:     "overrides",
+				From:      replaced,
+				To:        overrides,
+			}}},
+		})
+	}
+	return s.applyOverrides(ctx, feature, merged)
+}
+
+// RemoveOverrides removes the overrides with the given keys from a feature.
+// Keys the feature has no override for are ignored, so a removal can be
+// retried. If the feature is protected a pending change request is created
+// instead and a *PendingChangeError is returned.
+func (s *FeatureService) RemoveOverrides(ctx context.Context, id primitive.ObjectID, keys []models.OverrideKey) (*models.Feature, error) {
+	ctx, span := start(ctx, "RemoveOverrides", tracing.FeatureID(id), tracing.OverrideCountKey.Int(len(keys)))
+	defer span.End()
+
+	feature, err := s.getFeature(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	remaining, removed := removeOverrides(feature, keys)
+	if len(removed) == 0 {
+		return feature, nil
+	}
+
+	if feature.Protected {
+		return nil, s.submitChangeRequest(ctx, &models.ChangeRequest{
+			Action:    models.ChangeRequestActionRemoveOverrides,
+			FeatureID: id,
+			Overrides: removed,
+			Diff: models.ChangeRequestDiff{Features: []models.FeatureChange{{
+				FeatureID: id,
+				Name:      feature.Name,
+				Field:     "overrides",
+				From:      removed,
+			}}},
+		})
+	}
+	return s.applyOverrides(ctx, feature, remaining)
+}
+
+// addOverrides applies an approved change request against the current
+// state of the feature, so the limit is checked again.
+func (s *FeatureService) addOverrides(ctx context.Context, id primitive.ObjectID, overrides []models.Override) error {
+	feature, err := s.getFeature(ctx, id)
+	if err != nil {
+		return err
+	}
+	merged, _, err := mergeOverrides(feature, overrides)
+	if err != nil {
+		return err
+	}
+	_, err = s.applyOverrides(ctx, feature, merged)
+	return err
+}
+
+func (s *FeatureService) removeOverrides(ctx context.Context, id primitive.ObjectID, overrides []models.Override) error {
+	feature, err := s.getFeature(ctx, id)
+	if err != nil {
+		return err
+	}
+	keys := make([]models.OverrideKey, len(overrides))
+	for i := range overrides {
+		keys[i] = overrides[i].Key()
+	}
+	remaining, removed := removeOverrides(feature, keys)
+	if len(removed) == 0 {
+		return nil
+	}
+	_, err = s.applyOverrides(ctx, feature, remaining)
+	return err
+}
+
+// applyOverrides stores overrides on feature, provided it is unchanged
+// since it was read, so concurrent additions and removals cannot drop each
+// other's overrides.
+func (s *FeatureService) applyOverrides(ctx context.Context, feature *models.Feature, overrides []models.Override) (*models.Feature, error) {
+	feature.Overrides = overrides
+	updated, err := s.featureRepo.SetOverrides(ctx, feature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update overrides: %w", err)
+	}
+	if !updated {
+		return nil, fmt.Errorf("%w: feature %s", ErrConcurrentChange, feature.FlagKey())
+	}
+	return feature, nil
+}
+
+// checkOverrideVariants reports targeting that drops a variant one of the
+// feature's overrides serves as ErrInvalidTargeting.
+func checkOverrideVariants(feature *models.Feature, targeting *models.Targeting) error {
+	for i := range feature.Overrides {
+		if err := feature.Overrides[i].Validate(targeting); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidTargeting, err)
+		}
+	}
+	return nil
+}
+
+// mergeOverrides returns the feature's overrides with overrides added, and
+// the existing overrides they replace. It reports invalid or duplicated
+// overrides as ErrInvalidOverride and a result over models.MaxOverrides as
+// an *OverrideLimitError.
+func mergeOverrides(feature *models.Feature, overrides []models.Override) (merged, replaced []models.Override, err error) {
+	added := make(map[models.OverrideKey]bool, len(overrides))
+	for i := range overrides {
+		o := &overrides[i]
+		if err := o.Validate(&feature.Targeting); err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidOverride, err)
+		}
+		if added[o.Key()] {
+			return nil, nil, fmt.Errorf("%w: override %s=%s is listed more than once", ErrInvalidOverride, o.Attribute, o.Value)
+		}
+		added[o.Key()] = true
+	}
+
+	for _, o := range feature.Overrides {
+		if added[o.Key()] {
+			replaced = append(replaced, o)
+			continue
+		}
+		merged = append(merged, o)
+	}
+	merged = append(merged, overrides...)
+	if len(merged) > models.MaxOverrides {
+		return nil, nil, &OverrideLimitError{FeatureID: feature.ID, Limit: models.MaxOverrides, Count: len(merged)}
+	}
+	return merged, replaced, nil
+}
+
+// removeOverrides splits the feature's overrides into those without and
+// those with one of keys.
+func removeOverrides(feature *models.Feature, keys []models.OverrideKey) (remaining, removed []models.Override) {
+	remove := make(map[models.OverrideKey]bool, len(keys))
+	for _, key := range keys {
+		remove[key] = true
+	}
+	for _, o := range feature.Overrides {
+		if remove[o.Key()] {
+			removed = append(removed, o)
+		} else {
+			remaining = append(remaining, o)
+		}
+	}
+	return remaining, removed
+}
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"feature-flags/internal/logging"
+	"feature-flags/internal/metrics"
+	"feature-flags/internal/models"
+	"feature-flags/internal/tracing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RolloutActor is recorded as the actor of the audit entries made when a
+// rollout plan advances.
+const RolloutActor = "system:rollout"
+
+var (
+	ErrInvalidRollout = errors.New("invalid rollout plan")
+	// ErrRolloutState is returned when a rollout plan cannot be paused,
+	// resumed or aborted from the status it is in.
+	ErrRolloutState = kindError(ErrConflict, "rollout plan cannot make this change")
+)
+
+// SetRollout starts a rollout plan on a feature from its first step,
+// replacing any plan it had, or removes its plan when plan is nil. Only the
+// steps of plan are used. Without a plan an enabled feature is on for
+// every context, so removing an unfinished plan releases it fully. If the
+// feature is protected a pending change request is created instead and a
+// *PendingChangeError is returned; the plan starts once it is approved.
+func (s *FeatureService) SetRollout(ctx context.Context, id primitive.ObjectID, plan *models.RolloutPlan) (*models.Feature, error) {
+	ctx, span := start(ctx, "SetRollout", tracing.FeatureID(id))
+	defer span.End()
+
+	if plan != nil {
+		if err := plan.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRollout, err)
+		}
+		plan = &models.RolloutPlan{Steps: plan.Steps}
+	}
+	feature, err := s.getFeature(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkUnmanaged(feature); err != nil {
+		return nil, err
+	}
+	if plan == nil && feature.Rollout == nil {
+		return feature, nil
+	}
+
+	if feature.Protected {
+		return nil, s.submitChangeRequest(ctx, &models.ChangeRequest{
+			Action:    models.ChangeRequestActionSetRollout,
+			FeatureID: id,
+			Rollout:   plan,
+			Diff: models.ChangeRequestDiff{Features: []models.FeatureChange{{
+				FeatureID: id,
+				Name:      feature.Name,
+				Field:     "rollout",
+				From:      feature.Rollout,
+				To:        plan,
+			}}},
+		})
+	}
+	return s.applyRollout(ctx, feature, plan)
+}
+
+func (s *FeatureService) setRollout(ctx context.Context, id primitive.ObjectID, plan *models.RolloutPlan) error {
+	feature, err := s.getFeature(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.checkUnmanaged(feature); err != nil {
+		return err
+	}
+	_, err = s.applyRollout(ctx, feature, plan)
+	return err
+}
+
+// applyRollout starts plan on feature, or removes its plan when nil,
+// provided feature is unchanged since it was read, so it cannot overwrite a
+// step the executor took meanwhile.
+func (s *FeatureService) applyRollout(ctx context.Context, feature *models.Feature, plan *models.RolloutPlan) (*models.Feature, error) {
+	if plan != nil {
+		plan.Status = models.RolloutStatusActive
+		plan.Step = 0
+		plan.StepStartedAt = time.Now()
+		plan.PausedAt = nil
+	}
+	feature.Rollout = plan
+	updated, err := s.featureRepo.SetRollout(ctx, feature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set rollout plan: %w", err)
+	}
+	if !updated {
+		return nil, fmt.Errorf("%w: feature %s", ErrConcurrentChange, feature.FlagKey())
+	}
+	return feature, nil
+}
+
+// PauseRollout stops an active rollout plan at its current step.
+func (s *FeatureService) PauseRollout(ctx context.Context, id primitive.ObjectID) (*models.Feature, error) {
+	ctx, span := start(ctx, "PauseRollout", tracing.FeatureID(id))
+	defer span.End()
+
+	return s.changeRollout(ctx, id, func(plan *models.RolloutPlan) error {
+		if plan.Status != models.RolloutStatusActive {
+			return fmt.Errorf("%w: only an active plan can be paused, this one is %s", ErrRolloutState, plan.Status)
+		}
+		plan.Pause(time.Now())
+		return nil
+	})
+}
+
+// ResumeRollout continues a paused rollout plan. The current step holds for
+// what remained of its hold when the plan was paused.
+func (s *FeatureService) ResumeRollout(ctx context.Context, id primitive.ObjectID) (*models.Feature, error) {
+	ctx, span := start(ctx, "ResumeRollout", tracing.FeatureID(id))
+	defer span.End()
+
+	return s.changeRollout(ctx, id, func(plan *models.RolloutPlan) error {
+		if plan.Status != models.RolloutStatusPaused {
+			return fmt.Errorf("%w: only a paused plan can be resumed, this one is %s", ErrRolloutState, plan.Status)
+		}
+		plan.Resume(time.Now())
+		return nil
+	})
+}
+
+// AbortRollout rolls an active or paused rollout plan back to 0%, so the
+// feature is off for every context at once, then disables the feature with
+// DisableFeature and its full cascade. If that cascade is protected the
+// plan stays rolled back while the disable waits for approval, and a
+// *PendingChangeError is returned. The abort is recorded in the audit
+// trail.
+func (s *FeatureService) AbortRollout(ctx context.Context, id primitive.ObjectID) (*models.Feature, error) {
+	ctx, span := start(ctx, "AbortRollout", tracing.FeatureID(id))
+	defer span.End()
+
+	feature, err := s.changeRollout(ctx, id, func(plan *models.RolloutPlan) error {
+		if plan.Status != models.RolloutStatusActive && plan.Status != models.RolloutStatusPaused {
+			return fmt.Errorf("%w: only an active or paused plan can be aborted, this one is %s", ErrRolloutState, plan.Status)
+		}
+		plan.Abort()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	metrics.RolloutTransitions.WithLabelValues("aborted").Inc()
+
+	plan := feature.Rollout
+	entry := &models.AuditEntry{
+		Action:     models.AuditActionRolloutAbort,
+		FeatureID:  feature.ID,
+		FeatureKey: feature.FlagKey(),
+		Actor:      actorFromContext(ctx),
+		Reason:     fmt.Sprintf("aborted at step %d of %d (%g%%)", plan.Step+1, len(plan.Steps), plan.Steps[plan.Step].Percentage),
+	}
+	cascade, disableErr := s.requestDisable(ctx, id)
+	var pending *PendingChangeError
+	switch {
+	case errors.As(disableErr, &pending):
+		entry.ChangeRequestID = &pending.ChangeRequest.ID
+		entry.Pending = flagKeys(cascade)
+	case disableErr != nil:
+		entry.Error = disableErr.Error()
+	default:
+		entry.Disabled = flagKeys(cascade)
+	}
+
+	attrs := []any{"feature", entry.FeatureKey, "reason", entry.Reason, "disabled", entry.Disabled}
+	if pending != nil {
+		attrs = append(attrs, "pending", entry.Pending)
+	}
+	if entry.Error != "" {
+		attrs = append(attrs, logging.Error(disableErr))
+	}
+	slog.WarnContext(ctx, "rollout aborted", attrs...)
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	if disableErr != nil {
+		return nil, disableErr
+	}
+	return s.getFeature(ctx, id)
+}
+
+// changeRollout applies change to the rollout plan of a feature and stores
+// it, provided no one else moved the plan in the meantime.
+func (s *FeatureService) changeRollout(ctx context.Context, id primitive.ObjectID, change func(*models.RolloutPlan) error) (*models.Feature, error) {
+	feature, err := s.getFeature(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if feature.Rollout == nil {
+		return nil, fmt.Errorf("%w: the feature has no rollout plan", ErrRolloutState)
+	}
+
+	previous := *feature.Rollout
+	plan := previous
+	if err := change(&plan); err != nil {
+		return nil, err
+	}
+	feature.Rollout = &plan
+	updated, err := s.featureRepo.UpdateRollout(ctx, feature, previous)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update rollout plan: %w", err)
+	}
+	if !updated {
+		return nil, fmt.Errorf("%w: the plan changed meanwhile, try again", ErrRolloutState)
+	}
+	return feature, nil
+}
+
+// AdvanceRollouts moves every active rollout plan of an enabled feature
+// whose current step has held long enough to its next step. Plans of
+// disabled features, such as those a guard rule turned off, do not advance.
+// Each step is claimed in the database, so when several instances advance
+// at once only one moves a plan.
+//
+// It returns an audit entry per step taken, also stored in the audit trail.
+// The error joins the failures to advance a plan or to store an entry.
+func (s *FeatureService) AdvanceRollouts(ctx context.Context) ([]*models.AuditEntry, error) {
+	ctx, span := start(ctx, "AdvanceRollouts")
+	defer span.End()
+
+	enabled := true
+	features, err := s.featureRepo.Find(ctx, models.FeatureFilter{Enabled: &enabled, Rollout: models.RolloutStatusActive})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active rollouts: %w", err)
+	}
+
+	var (
+		steps []*models.AuditEntry
+		errs  []error
+	)
+	now := time.Now()
+	for _, feature := range features {
+		if !feature.Rollout.Due(now) {
+			continue
+		}
+		entry, err := s.advanceRollout(ctx, feature, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("feature %s: %w", feature.FlagKey(), err))
+		}
+		if entry != nil {
+			steps = append(steps, entry)
+		}
+	}
+	tracing.SetAttributes(ctx, tracing.ResultCountKey.Int(len(steps)))
+	return steps, errors.Join(errs...)
+}
+
+// advanceRollout moves the plan of feature to its next step. It returns nil
+// when another instance or request moved the plan first.
+func (s *FeatureService) advanceRollout(ctx context.Context, feature *models.Feature, now time.Time) (*models.AuditEntry, error) {
+	previous := *feature.Rollout
+	plan := previous
+	plan.Advance(now)
+	feature.Rollout = &plan
+	updated, err := s.featureRepo.UpdateRollout(ctx, feature, previous)
+	if err != nil {
+		return nil, fmt.Errorf("failed to advance rollout plan: %w", err)
+	}
+	if !updated {
+		return nil, nil
+	}
+
+	reason := fmt.Sprintf("advanced to step %d of %d (%g%%)", plan.Step+1, len(plan.Steps), plan.Percentage())
+	transition := "advanced"
+	if plan.Status == models.RolloutStatusCompleted {
+		reason = fmt.Sprintf("completed at step %d of %d (100%%)", plan.Step+1, len(plan.Steps))
+		transition = "completed"
+	}
+	metrics.RolloutTransitions.WithLabelValues(transition).Inc()
+
+	entry := &models.AuditEntry{
+		Action:     models.AuditActionRolloutStep,
+		FeatureID:  feature.ID,
+		FeatureKey: feature.FlagKey(),
+		Actor:      RolloutActor,
+		Reason:     reason,
+	}
+	slog.InfoContext(ctx, "rollout advanced", "feature", entry.FeatureKey, "reason", entry.Reason)
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		return entry, fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return entry, nil
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"feature-flags/internal/models"
+	"feature-flags/internal/tracing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrSegmentExists  = kindError(ErrConflict, "segment key already exists")
+	ErrSegmentInUse   = kindError(ErrConflict, "segment is referenced by features")
+	ErrInvalidSegment = errors.New("invalid segment")
+)
+
+// SegmentInUseError is returned when a segment cannot be deleted because
+// targeting rules reference it. Features lists their keys. It matches
+// ErrSegmentInUse.
+type SegmentInUseError struct {
+	Key      string
+	Features []string
+}
+
+func (e *SegmentInUseError) Error() string {
+	return fmt.Sprintf("%v: segment %s is used by %s", ErrSegmentInUse, e.Key, strings.Join(e.Features, ", "))
+}
+
+func (e *SegmentInUseError) Unwrap() error { return ErrSegmentInUse }
+
+// CreateSegment creates a segment as version 1. The name defaults to the
+// key.
+func (s *FeatureService) CreateSegment(ctx context.Context, segment *models.Segment) error {
+	ctx, span := start(ctx, "CreateSegment", tracing.SegmentKeyKey.String(segment.Key))
+	defer span.End()
+
+	if err := models.ValidateKey(segment.Key); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+	if err := segment.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSegment, err)
+	}
+	if segment.Name == "" {
+		segment.Name = segment.Key
+	}
+	segment.Version = 1
+	segment.CreatedBy = actorFromContext(ctx)
+	err := s.segmentRepo.Create(ctx, segment)
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("%w: %s", ErrSegmentExists, segment.Key)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create segment: %w", err)
+	}
+	return nil
+}
+
+// GetSegment returns the segment with key.
+func (s *FeatureService) GetSegment(ctx context.Context, key string) (*models.Segment, error) {
+	ctx, span := start(ctx, "GetSegment", tracing.SegmentKeyKey.String(key))
+	defer span.End()
+
+	return s.getSegment(ctx, key)
+}
+
+// ListSegments returns every segment, ordered by key.
+func (s *FeatureService) ListSegments(ctx context.Context) ([]*models.Segment, error) {
+	ctx, span := start(ctx, "ListSegments")
+	defer span.End()
+
+	return s.segmentRepo.List(ctx)
+}
+
+// UpdateSegment replaces the name, description, rules and lists of the
+// segment with key. The change applies at once to every feature whose rules
+// reference the segment, including protected and GitOps-managed ones. If the
+// segment changed since it was read, ErrConcurrentChange is returned.
+func (s *FeatureService) UpdateSegment(ctx context.Context, key string, update *models.Segment) (*models.Segment, error) {
+	ctx, span := start(ctx, "UpdateSegment", tracing.SegmentKeyKey.String(key))
+	defer span.End()
+
+	if err := update.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSegment, err)
+	}
+	segment, err := s.getSegment(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	segment.Name = update.Name
+	if segment.Name == "" {
+		segment.Name = segment.Key
+	}
+	segment.Description = update.Description
+	segment.Rules = update.Rules
+	segment.Attribute = update.Attribute
+	segment.Included = update.Included
+	segment.Excluded = update.Excluded
+	segment.Version++
+	updated, err := s.segmentRepo.Update(ctx, segment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update segment: %w", err)
+	}
+	if !updated {
+		return nil, fmt.Errorf("%w: segment %s", ErrConcurrentChange, key)
+	}
+	return segment, nil
+}
+
+// DeleteSegment deletes the segment with key. Segments that targeting rules
+// still reference are not deleted; a *SegmentInUseError names the features.
+func (s *FeatureService) DeleteSegment(ctx context.Context, key string) error {
+	ctx, span := start(ctx, "DeleteSegment", tracing.SegmentKeyKey.String(key))
+	defer span.End()
+
+	features, err := s.featureRepo.Find(ctx, models.FeatureFilter{Segment: key})
+	if err != nil {
+		return fmt.Errorf("failed to find features using segment: %w", err)
+	}
+	if len(features) > 0 {
+		keys := make([]string, len(features))
+		for i, feature := range features {
+			keys[i] = feature.FlagKey()
+		}
+		return &SegmentInUseError{Key: key, Features: keys}
+	}
+
+	err = s.segmentRepo.Delete(ctx, key)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return &NotFoundError{Resource: "segment", ID: key}
+	}
+	return err
+}
+
+// SegmentUsage returns the features whose targeting rules reference the
+// segment with key, ordered by name.
+func (s *FeatureService) SegmentUsage(ctx context.Context, key string) ([]*models.Feature, error) {
+	ctx, span := start(ctx, "SegmentUsage", tracing.SegmentKeyKey.String(key))
+	defer span.End()
+
+	if _, err := s.getSegment(ctx, key); err != nil {
+		return nil, err
+	}
+	features, err := s.featureRepo.Find(ctx, models.FeatureFilter{Segment: key})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find features using segment: %w", err)
+	}
+	span.SetAttributes(tracing.ResultCountKey.Int(len(features)))
+	return features, nil
+}
+
+// getSegment loads a segment, reporting a missing one as a *NotFoundError.
+func (s *FeatureService) getSegment(ctx context.Context, key string) (*models.Segment, error) {
+	segment, err := s.segmentRepo.GetByKey(ctx, key)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, &NotFoundError{Resource: "segment", ID: key}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get segment: %w", err)
+	}
+	return segment, nil
+}
+
+// checkSegments reports rules that reference segments which do not exist as
+// ErrInvalidTargeting.
+func (s *FeatureService) checkSegments(ctx context.Context, targeting *models.Targeting) error {
+	missing, err := s.missingSegments(ctx, targeting.SegmentKeys())
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: unknown segments %s", ErrInvalidTargeting, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// missingSegments returns the keys no segment has, in the order given.
+func (s *FeatureService) missingSegments(ctx context.Context, keys []string) ([]string, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	segments, err := s.segmentRepo.GetByKeys(ctx, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get segments: %w", err)
+	}
+	found := make(map[string]bool, len(segments))
+	for _, segment := range segments {
+		found[segment.Key] = true
+	}
+	var missing []string
+	for _, key := range keys {
+		if !found[key] {
+			missing = append(missing, key)
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,47 @@
+package tracing
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Attribute keys shared by the service and repository spans.
+const (
+	FeatureIDKey       = attribute.Key("feature.id")
+	ParentIDKey        = attribute.Key("feature.parent_id")
+	ChildIDKey         = attribute.Key("feature.child_id")
+	ChangeRequestIDKey = attribute.Key("change_request.id")
+	FlagKeyKey         = attribute.Key("flag.key")
+	CascadeSizeKey     = attribute.Key("cascade.size")
+	CascadeDepthKey    = attribute.Key("cascade.depth")
+	ChildrenCountKey   = attribute.Key("children.count")
+	ResultCountKey     = attribute.Key("result.count")
+	DependencyCountKey = attribute.Key("dependency.count")
+	TenantIDKey        = attribute.Key("tenant.id")
+	OverrideCountKey   = attribute.Key("override.count")
+	SegmentKeyKey      = attribute.Key("segment.key")
+	EventNameKey       = attribute.Key("event.name")
+	EventCountKey      = attribute.Key("event.count")
+	SignalCountKey     = attribute.Key("signal.count")
+)
+
+// Handler records the handler that served a request.
+func Handler(name string) attribute.KeyValue {
+	return attribute.String("handler", name)
+}
+
+func FeatureID(id primitive.ObjectID) attribute.KeyValue {
+	return FeatureIDKey.String(id.Hex())
+}
+
+func ParentID(id primitive.ObjectID) attribute.KeyValue {
+	return ParentIDKey.String(id.Hex())
+}
+
+func ChildID(id primitive.ObjectID) attribute.KeyValue {
+	return ChildIDKey.String(id.Hex())
+}
+
+func ChangeRequestID(id primitive.ObjectID) attribute.KeyValue {
+	return ChangeRequestIDKey.String(id.Hex())
+}
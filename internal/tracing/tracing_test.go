@@ -0,0 +1,97 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type testHandler struct{}
+
+func (testHandler) DisableFeature(c *gin.Context) {
+	_, span := Start(c.Request.Context(), "FeatureService.DisableFeature")
+	span.End()
+	c.Status(http.StatusInternalServerError)
+}
+
+func TestMiddleware(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware())
+	r.POST("/api/features/:id/disable", testHandler{}.DisableFeature)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/features/abc/disable", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	service, server := spans[0], spans[1]
+
+	assert.Equal(t, "POST /api/features/:id/disable", server.Name())
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+	assert.Contains(t, server.Attributes(), Handler("testHandler.DisableFeature"))
+	assert.Equal(t, "Error", server.Status().Code.String())
+
+	assert.Equal(t, server.SpanContext().SpanID(), service.Parent().SpanID())
+}
+
+func TestHandlerName(t *testing.T) {
+	assert.Equal(t, "FeatureHandler.EnableFeature", handlerName("feature-flags/internal/handlers.(*FeatureHandler).EnableFeature-fm"))
+}
+
+func TestSetup_OTLPFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	shutdown, err := Setup(context.Background(), Config{Exporter: ExporterOTLPFile, File: path, ServiceName: "ff-test"})
+	require.NoError(t, err)
+
+	_, span := Start(context.Background(), "FeatureService.EnableFeature", FeatureIDKey.String("abc"))
+	traceID := span.SpanContext().TraceID().String()
+	span.End()
+	require.NoError(t, shutdown(context.Background()))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 1)
+
+	var doc struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []struct {
+					TraceID string `json:"traceId"`
+					Name    string `json:"name"`
+					Kind    int    `json:"kind"`
+				} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &doc))
+	got := doc.ResourceSpans[0].ScopeSpans[0].Spans[0]
+	assert.Equal(t, "FeatureService.EnableFeature", got.Name)
+	assert.Equal(t, traceID, got.TraceID)
+	assert.Contains(t, lines[0], `"ff-test"`)
+
+	_, err = Setup(context.Background(), Config{Exporter: "zipkin"})
+	assert.Error(t, err)
+}
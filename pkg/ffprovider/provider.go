@@ -0,0 +1,234 @@
+// Package ffprovider is an OpenFeature provider backed by a feature-flags
+// server. Flags are evaluated remotely over the OpenFeature Remote
+// Evaluation Protocol (OFREP), so dependency gating, targeting rules and
+// variant splits behave exactly as they do on the server.
+//
+//	provider := ffprovider.NewProvider("http://localhost:8080", ffprovider.WithToken(token))
+//	openfeature.SetProviderAndWait(provider)
+//	client := openfeature.NewClient("checkout-service")
+//	on, _ := client.BooleanValue(ctx, "one-click", false, openfeature.NewEvaluationContext("user-1", nil))
+package ffprovider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// Name is reported in the provider metadata.
+const Name = "feature-flags"
+
+// Provider implements openfeature.FeatureProvider.
+type Provider struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// Option configures a Provider.
+type Option func(*Provider)
+
+// WithToken sends token as a bearer token, for servers with authentication
+// enabled. Evaluation needs the viewer role.
+func WithToken(token string) Option {
+	return func(p *Provider) {
+		p.token = token
+	}
+}
+
+// WithHTTPClient replaces http.DefaultClient.
+func WithHTTPClient(client *http.Client) Option {
+	return func(p *Provider) {
+		p.client = client
+	}
+}
+
+// NewProvider returns a provider for the server at baseURL, e.g.
+// http://localhost:8080.
+func NewProvider(baseURL string, opts ...Option) *Provider {
+	p := &Provider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func (p *Provider) Metadata() openfeature.Metadata {
+	return openfeature.Metadata{Name: Name}
+}
+
+func (p *Provider) Hooks() []openfeature.Hook {
+	return nil
+}
+
+func (p *Provider) BooleanEvaluation(ctx context.Context, flag string, defaultValue bool, evalCtx openfeature.FlattenedContext) openfeature.BoolResolutionDetail {
+	value, detail := p.evaluate(ctx, flag, evalCtx)
+	if value == nil {
+		return openfeature.BoolResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+	v, ok := value.(bool)
+	if !ok {
+		return openfeature.BoolResolutionDetail{Value: defaultValue, ProviderResolutionDetail: typeMismatch(detail, "boolean", value)}
+	}
+	return openfeature.BoolResolutionDetail{Value: v, ProviderResolutionDetail: detail}
+}
+
+func (p *Provider) StringEvaluation(ctx context.Context, flag string, defaultValue string, evalCtx openfeature.FlattenedContext) openfeature.StringResolutionDetail {
+	value, detail := p.evaluate(ctx, flag, evalCtx)
+	if value == nil {
+		return openfeature.StringResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+	v, ok := value.(string)
+	if !ok {
+		return openfeature.StringResolutionDetail{Value: defaultValue, ProviderResolutionDetail: typeMismatch(detail, "string", value)}
+	}
+	return openfeature.StringResolutionDetail{Value: v, ProviderResolutionDetail: detail}
+}
+
+func (p *Provider) FloatEvaluation(ctx context.Context, flag string, defaultValue float64, evalCtx openfeature.FlattenedContext) openfeature.FloatResolutionDetail {
+	value, detail := p.evaluate(ctx, flag, evalCtx)
+	if value == nil {
+		return openfeature.FloatResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+	v, ok := value.(float64)
+	if !ok {
+		return openfeature.FloatResolutionDetail{Value: defaultValue, ProviderResolutionDetail: typeMismatch(detail, "number", value)}
+	}
+	return openfeature.FloatResolutionDetail{Value: v, ProviderResolutionDetail: detail}
+}
+
+// IntEvaluation accepts numbers without a fractional part.
+func (p *Provider) IntEvaluation(ctx context.Context, flag string, defaultValue int64, evalCtx openfeature.FlattenedContext) openfeature.IntResolutionDetail {
+	value, detail := p.evaluate(ctx, flag, evalCtx)
+	if value == nil {
+		return openfeature.IntResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+	v, ok := value.(float64)
+	if !ok || v != math.Trunc(v) || math.Abs(v) > 1<<53 {
+		return openfeature.IntResolutionDetail{Value: defaultValue, ProviderResolutionDetail: typeMismatch(detail, "integer", value)}
+	}
+	return openfeature.IntResolutionDetail{Value: int64(v), ProviderResolutionDetail: detail}
+}
+
+// ObjectEvaluation accepts any value; objects decode as
+// map[string]interface{} and arrays as []interface{}.
+func (p *Provider) ObjectEvaluation(ctx context.Context, flag string, defaultValue interface{}, evalCtx openfeature.FlattenedContext) openfeature.InterfaceResolutionDetail {
+	value, detail := p.evaluate(ctx, flag, evalCtx)
+	if value == nil {
+		return openfeature.InterfaceResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+	return openfeature.InterfaceResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+// result is an OFREP evaluation response.
+type result struct {
+	Key          string                 `json:"key"`
+	Value        interface{}            `json:"value"`
+	Variant      string                 `json:"variant"`
+	Reason       string                 `json:"reason"`
+	ErrorCode    string                 `json:"errorCode"`
+	ErrorDetails string                 `json:"errorDetails"`
+	Metadata     map[string]interface{} `json:"metadata"`
+}
+
+// evaluate resolves flag on the server. The value is nil when the caller
+// should use its default: on errors, and when the flag is off without an
+// off variant.
+func (p *Provider) evaluate(ctx context.Context, flag string, evalCtx openfeature.FlattenedContext) (interface{}, openfeature.ProviderResolutionDetail) {
+	r, err := p.post(ctx, flag, evalCtx)
+	if err != nil {
+		return nil, failed(openfeature.NewGeneralResolutionError(err.Error()))
+	}
+	if r.ErrorCode != "" {
+		return nil, failed(resolutionError(r.ErrorCode, r.ErrorDetails))
+	}
+	return r.Value, openfeature.ProviderResolutionDetail{
+		Reason:       openfeature.Reason(r.Reason),
+		Variant:      r.Variant,
+		FlagMetadata: openfeature.FlagMetadata(r.Metadata),
+	}
+}
+
+func (p *Provider) post(ctx context.Context, flag string, evalCtx openfeature.FlattenedContext) (*result, error) {
+	body, err := json.Marshal(map[string]interface{}{"context": evalCtx})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode evaluation context: %w", err)
+	}
+
+	endpoint := p.baseURL + "/ofrep/v1/evaluate/flags/" + url.PathEscape(flag)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusBadRequest, http.StatusNotFound:
+		var r result
+		if err := json.Unmarshal(data, &r); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK && r.ErrorCode == "" {
+			r.ErrorCode = string(openfeature.GeneralCode)
+			r.ErrorDetails = strings.TrimSpace(string(data))
+		}
+		return &r, nil
+	default:
+		return nil, fmt.Errorf("server returned %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+}
+
+func failed(err openfeature.ResolutionError) openfeature.ProviderResolutionDetail {
+	return openfeature.ProviderResolutionDetail{
+		ResolutionError: err,
+		Reason:          openfeature.ErrorReason,
+	}
+}
+
+func typeMismatch(detail openfeature.ProviderResolutionDetail, want string, value interface{}) openfeature.ProviderResolutionDetail {
+	mismatch := failed(openfeature.NewTypeMismatchResolutionError(fmt.Sprintf("flag value %v is not a %s", value, want)))
+	mismatch.FlagMetadata = detail.FlagMetadata
+	return mismatch
+}
+
+func resolutionError(code, details string) openfeature.ResolutionError {
+	switch openfeature.ErrorCode(code) {
+	case openfeature.FlagNotFoundCode:
+		return openfeature.NewFlagNotFoundResolutionError(details)
+	case openfeature.TypeMismatchCode:
+		return openfeature.NewTypeMismatchResolutionError(details)
+	case openfeature.TargetingKeyMissingCode:
+		return openfeature.NewTargetingKeyMissingResolutionError(details)
+	case openfeature.InvalidContextCode:
+		return openfeature.NewInvalidContextResolutionError(details)
+	case openfeature.ParseErrorCode:
+		return openfeature.NewParseErrorResolutionError(details)
+	default:
+		return openfeature.NewGeneralResolutionError(details)
+	}
+}
@@ -0,0 +1,128 @@
+package ffprovider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"feature-flags/internal/evaluation"
+	"feature-flags/internal/models"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// newServer serves single flag OFREP evaluation from a snapshot, with the
+// status codes the server's OFREP handler uses.
+func newServer(t *testing.T, snapshot *evaluation.Snapshot) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, `{"error":"missing bearer token"}`, http.StatusUnauthorized)
+			return
+		}
+		var req struct {
+			Context evaluation.Context `json:"context"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		result := snapshot.Evaluate(strings.TrimPrefix(r.URL.Path, "/ofrep/v1/evaluate/flags/"), req.Context)
+		switch result.ErrorCode {
+		case "":
+		case evaluation.ErrorFlagNotFound:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(result))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestProvider(t *testing.T) {
+	checkout := &models.Feature{ID: primitive.NewObjectID(), Name: "checkout", IsEnabled: true}
+	oneClick := &models.Feature{ID: primitive.NewObjectID(), Name: "one-click", IsEnabled: true, Targeting: models.Targeting{
+		Rules: []models.TargetingRule{{Attribute: "country", Operator: models.RuleOperatorIn, Values: []string{"DE"}}},
+	}}
+	limit := &models.Feature{ID: primitive.NewObjectID(), Name: "limit", IsEnabled: true, Targeting: models.Targeting{
+		Variants:       []models.Variant{{Name: "low", Value: 10}, {Name: "high", Value: 2.5}},
+		DefaultVariant: "low",
+	}}
+	layout := &models.Feature{ID: primitive.NewObjectID(), Name: "layout", IsEnabled: true, Targeting: models.Targeting{
+		Variants:       []models.Variant{{Name: "grid", Value: map[string]interface{}{"columns": 3}}},
+		DefaultVariant: "grid",
+	}}
+	theme := &models.Feature{ID: primitive.NewObjectID(), Name: "theme", IsEnabled: true, Targeting: models.Targeting{
+		Variants: []models.Variant{{Name: "dark", Value: "dark", Weight: 1}},
+	}}
+	snapshot := evaluation.NewSnapshot(
+		[]*models.Feature{checkout, oneClick, limit, layout, theme},
+		[]models.FeatureDependency{{ParentID: oneClick.ID, ChildID: theme.ID}},
+	)
+	server := newServer(t, snapshot)
+
+	provider := NewProvider(server.URL+"/", WithToken("secret"), WithHTTPClient(server.Client()))
+	require.NoError(t, openfeature.SetNamedProviderAndWait(t.Name(), provider))
+	client := openfeature.NewClient(t.Name())
+	ctx := context.Background()
+	de := openfeature.NewEvaluationContext("user-1", map[string]interface{}{"country": "DE"})
+	us := openfeature.NewEvaluationContext("user-2", map[string]interface{}{"country": "US"})
+
+	details, err := client.BooleanValueDetails(ctx, "checkout", false, us)
+	require.NoError(t, err)
+	assert.True(t, details.Value)
+	assert.Equal(t, openfeature.StaticReason, details.Reason)
+	assert.Equal(t, checkout.ID.Hex(), details.FlagMetadata["featureId"])
+
+	details, err = client.BooleanValueDetails(ctx, "one-click", true, us)
+	require.NoError(t, err)
+	assert.False(t, details.Value)
+	assert.Equal(t, openfeature.DefaultReason, details.Reason)
+
+	// Gated by a parent that is off for the context: the caller's default
+	// is used since theme has no off variant.
+	s, err := client.StringValueDetails(ctx, "theme", "light", us)
+	require.NoError(t, err)
+	assert.Equal(t, "light", s.Value)
+	assert.Equal(t, openfeature.DisabledReason, s.Reason)
+	s, err = client.StringValueDetails(ctx, "theme", "light", de)
+	require.NoError(t, err)
+	assert.Equal(t, "dark", s.Value)
+	assert.Equal(t, openfeature.SplitReason, s.Reason)
+
+	n, err := client.IntValue(ctx, "limit", 0, us)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), n)
+	f, err := client.FloatValue(ctx, "limit", 0, us)
+	require.NoError(t, err)
+	assert.Equal(t, 10.0, f)
+
+	obj, err := client.ObjectValue(ctx, "layout", nil, us)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"columns": 3.0}, obj)
+
+	mismatch, err := client.StringValueDetails(ctx, "checkout", "fallback", us)
+	require.Error(t, err)
+	assert.Equal(t, "fallback", mismatch.Value)
+	assert.Equal(t, openfeature.TypeMismatchCode, mismatch.ErrorCode)
+	assert.Equal(t, openfeature.ErrorReason, mismatch.Reason)
+
+	missing, err := client.BooleanValueDetails(ctx, "missing", true, us)
+	require.Error(t, err)
+	assert.True(t, missing.Value)
+	assert.Equal(t, openfeature.FlagNotFoundCode, missing.ErrorCode)
+
+	noKey, err := client.StringValueDetails(ctx, "theme", "light", openfeature.NewTargetlessEvaluationContext(map[string]interface{}{"country": "DE"}))
+	require.Error(t, err)
+	assert.Equal(t, openfeature.TargetingKeyMissingCode, noKey.ErrorCode)
+
+	unauthorized := NewProvider(server.URL, WithHTTPClient(server.Client()))
+	detail := unauthorized.BooleanEvaluation(ctx, "checkout", false, openfeature.FlattenedContext{})
+	assert.Equal(t, openfeature.ErrorReason, detail.Reason)
+	assert.Equal(t, openfeature.GeneralCode, detail.ResolutionDetail().ErrorCode)
+}